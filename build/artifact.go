@@ -13,8 +13,10 @@ import (
 	"djinn-ci.com/auth"
 	"djinn-ci.com/crypto"
 	"djinn-ci.com/database"
+	"djinn-ci.com/driver"
 	"djinn-ci.com/env"
 	"djinn-ci.com/errors"
+	"djinn-ci.com/manifest"
 
 	"github.com/andrewpillar/fs"
 	"github.com/andrewpillar/query"
@@ -202,6 +204,21 @@ func (s *ArtifactStore) CreateTx(ctx context.Context, tx database.Tx, a *Artifac
 	return nil
 }
 
+func (s *ArtifactStore) Create(ctx context.Context, a *Artifact) error {
+	hash, err := s.Hasher.HashNow()
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	a.Hash = hash
+
+	if err := s.Store.Create(ctx, a); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
 func (s *ArtifactStore) Index(ctx context.Context, vals url.Values, opts ...query.Option) (*database.Paginator[*Artifact], error) {
 	page, _ := strconv.Atoi(vals.Get("page"))
 
@@ -273,6 +290,75 @@ type artifactFilestore struct {
 	limit int64
 	store *ArtifactStore
 	build *Build
+	job   *Job
+}
+
+func (s *artifactFilestore) get(ctx context.Context, name string) (*Artifact, bool, error) {
+	a, ok, err := s.store.SelectOne(
+		ctx,
+		[]string{"id", "user_id", "job_id", "hash", "size", "md5", "sha256"},
+		query.Where("build_id", "=", query.Arg(s.build.ID)),
+		query.Where("name", "=", query.Arg(name)),
+	)
+
+	if err != nil {
+		return nil, false, errors.Err(err)
+	}
+	return a, ok, nil
+}
+
+// artifact returns the Artifact for the file with the given name. If the
+// filestore is for a Job, and there is no Artifact with the name, then one is
+// created for the file. The names of the files collected for a Job expanded
+// from the build matrix are suffixed with the matrix combination.
+func (s *artifactFilestore) artifact(ctx context.Context, f fs.File, name string) (*Artifact, error) {
+	a, ok, err := s.get(ctx, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.job == nil {
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return a, nil
+	}
+
+	if !ok && s.job.Matrix != nil {
+		name = manifest.MatrixArtifact(name, s.job.Matrix)
+
+		if a, ok, err = s.get(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok {
+		if a.JobID != s.job.ID {
+			return nil, fs.ErrExist
+		}
+		return a, nil
+	}
+
+	src := name
+
+	if af, ok := f.(*driver.ArtifactFile); ok {
+		src = af.Source
+	}
+
+	a = &Artifact{
+		UserID:    s.build.UserID,
+		BuildID:   s.build.ID,
+		JobID:     s.job.ID,
+		Source:    src,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.store.Create(ctx, a); err != nil {
+		return nil, errors.Err(err)
+	}
+	return a, nil
 }
 
 func (s *artifactFilestore) Put(f fs.File) (fs.File, error) {
@@ -286,21 +372,12 @@ func (s *artifactFilestore) Put(f fs.File) (fs.File, error) {
 
 	ctx := context.Background()
 
-	a, ok, err := s.store.SelectOne(
-		ctx,
-		[]string{"id", "user_id", "hash", "size", "md5", "sha256"},
-		query.Where("build_id", "=", query.Arg(s.build.ID)),
-		query.Where("name", "=", query.Arg(name)),
-	)
+	a, err := s.artifact(ctx, f, name)
 
 	if err != nil {
 		return nil, &fs.PathError{Op: "put", Path: name, Err: err}
 	}
 
-	if !ok {
-		return nil, &fs.PathError{Op: "put", Path: name, Err: fs.ErrNotExist}
-	}
-
 	md5 := md5.New()
 	sha256 := sha256.New()
 
@@ -341,6 +418,20 @@ func (s *artifactFilestore) Put(f fs.File) (fs.File, error) {
 	return f, nil
 }
 
+// JobFilestore returns a filestore for collecting the artifacts of the given
+// Job. Unlike Filestore, an Artifact is created for each collected file that
+// does not already have one, this allows for collecting the files matched by a
+// pattern, or found in a directory.
+func (s *ArtifactStore) JobFilestore(b *Build, j *Job, limit int64) fs.FS {
+	return fs.WriteOnly(&artifactFilestore{
+		FS:    s.FS,
+		limit: limit,
+		store: s,
+		build: b,
+		job:   j,
+	})
+}
+
 func (s *ArtifactStore) Filestore(b *Build, limit int64) fs.FS {
 	return fs.WriteOnly(&artifactFilestore{
 		FS:    s.FS,
@@ -349,3 +440,55 @@ func (s *ArtifactStore) Filestore(b *Build, limit int64) fs.FS {
 		build: b,
 	})
 }
+
+type inputFilestore struct {
+	fs.FS
+
+	store *ArtifactStore
+	build *Build
+	job   *Job
+}
+
+// Open opens the collected Artifact of the build with the given name. If the
+// Job was expanded from the build matrix, then the Artifact with the name
+// suffixed with the matrix combination of the Job is preferred.
+func (s *inputFilestore) Open(name string) (fs.File, error) {
+	names := []string{name}
+
+	if s.job.Matrix != nil {
+		names = append([]string{manifest.MatrixArtifact(name, s.job.Matrix)}, names...)
+	}
+
+	ctx := context.Background()
+
+	for _, name := range names {
+		a, ok, err := s.store.SelectOne(
+			ctx,
+			[]string{"id", "user_id", "hash"},
+			query.Where("build_id", "=", query.Arg(s.build.ID)),
+			query.Where("name", "=", query.Arg(name)),
+			query.Where("size", "IS NOT", query.Lit("NULL")),
+			query.Where("deleted_at", "IS", query.Lit("NULL")),
+		)
+
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+
+		if ok {
+			return a.Open(s.store.FS)
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// InputFilestore returns a filestore for reading the inputs of the given Job
+// from the Artifacts collected from the earlier Jobs of the Build.
+func (s *ArtifactStore) InputFilestore(b *Build, j *Job) fs.FS {
+	return fs.ReadOnly(&inputFilestore{
+		FS:    s.FS,
+		store: s,
+		build: b,
+		job:   j,
+	})
+}
@@ -0,0 +1,137 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"djinn-ci.com/database"
+	"djinn-ci.com/errors"
+	"djinn-ci.com/runner"
+)
+
+// JobAttempt is a previous attempt at executing a Job that was retried. The
+// output and status of the final attempt are stored on the Job itself.
+type JobAttempt struct {
+	ID         int64
+	JobID      int64
+	Number     int
+	Status     runner.Status
+	Output     database.Null[string]
+	Results    JobCommands
+	StartedAt  database.Null[time.Time]
+	FinishedAt database.Null[time.Time]
+
+	Job *Job
+}
+
+var _ database.Model = (*JobAttempt)(nil)
+
+func (a *JobAttempt) Primary() (string, any) { return "id", a.ID }
+
+func (a *JobAttempt) Scan(r *database.Row) error {
+	valtab := map[string]any{
+		"id":              &a.ID,
+		"job_id":          &a.JobID,
+		"number":          &a.Number,
+		"status":          &a.Status,
+		"output":          &a.Output,
+		"command_results": &a.Results,
+		"started_at":      &a.StartedAt,
+		"finished_at":     &a.FinishedAt,
+	}
+
+	if err := database.Scan(r, valtab); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
+func (a *JobAttempt) Params() database.Params {
+	return database.Params{
+		"id":              database.ImmutableParam(a.ID),
+		"job_id":          database.CreateOnlyParam(a.JobID),
+		"number":          database.CreateOnlyParam(a.Number),
+		"status":          database.CreateOnlyParam(a.Status),
+		"output":          database.CreateOnlyParam(a.Output),
+		"command_results": database.CreateOnlyParam(a.Results),
+		"started_at":      database.CreateOnlyParam(a.StartedAt),
+		"finished_at":     database.CreateOnlyParam(a.FinishedAt),
+	}
+}
+
+func (a *JobAttempt) Bind(m database.Model) {
+	if v, ok := m.(*Job); ok {
+		if a.JobID == v.ID {
+			a.Job = v
+		}
+	}
+}
+
+func (a *JobAttempt) MarshalJSON() ([]byte, error) {
+	if a == nil {
+		return []byte("null"), nil
+	}
+
+	b, err := json.Marshal(map[string]any{
+		"number":      a.Number,
+		"status":      a.Status,
+		"output":      a.Output,
+		"results":     a.Results,
+		"started_at":  a.StartedAt,
+		"finished_at": a.FinishedAt,
+	})
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return b, nil
+}
+
+func (*JobAttempt) Endpoint(...string) string { return "" }
+
+type JobAttemptStore struct {
+	*database.Store[*JobAttempt]
+}
+
+const jobAttemptTable = "build_job_attempts"
+
+func NewJobAttemptStore(pool *database.Pool) *database.Store[*JobAttempt] {
+	return database.NewStore[*JobAttempt](pool, jobAttemptTable, func() *JobAttempt {
+		return &JobAttempt{}
+	})
+}
+
+// Retried records the current attempt of the given Job as a JobAttempt, and
+// resets the Job so the next attempt can be recorded against it. The given
+// Job's Status and Output should be those of the failed attempt.
+func (s JobAttemptStore) Retried(ctx context.Context, j *Job) (*JobAttempt, error) {
+	a := &JobAttempt{
+		JobID:     j.ID,
+		Number:    j.Attempt,
+		Status:    j.Status,
+		Output:    j.Output,
+		Results:   j.Results,
+		StartedAt: j.StartedAt,
+		FinishedAt: database.Null[time.Time]{
+			Elem:  time.Now(),
+			Valid: true,
+		},
+		Job: j,
+	}
+
+	if err := s.Create(ctx, a); err != nil {
+		return nil, errors.Err(err)
+	}
+
+	j.Attempt++
+	j.Status = runner.Running
+	j.Output = database.Null[string]{}
+	j.Results = nil
+	j.StartedAt = database.Null[time.Time]{
+		Elem:  time.Now(),
+		Valid: true,
+	}
+	j.Attempts = append(j.Attempts, a)
+	return a, nil
+}
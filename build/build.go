@@ -11,7 +11,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -48,6 +47,7 @@ type Build struct {
 	NamespaceID database.Null[int64]
 	Number      int64
 	Manifest    manifest.Manifest
+	Parameters  manifest.ParamValues
 	Status      runner.Status
 	Output      database.Null[string]
 	Secret      database.Null[string]
@@ -193,9 +193,9 @@ func (b *Build) Tag(ctx context.Context, pool *database.Pool, u *auth.User, tags
 
 // Kill publishes the current Build's secret to the given Redis client to signal
 // that the worker should immediately kill the Build's execution. This will only
-// publish the secret if the Build is running.
+// publish the secret if the Build is running, or waiting for approval.
 func (b *Build) Kill(redis *redis.Client) error {
-	if b.Status != runner.Running {
+	if b.Status != runner.Running && b.Status != runner.Waiting {
 		return nil
 	}
 
@@ -247,6 +247,7 @@ func (b *Build) Scan(r *database.Row) error {
 		"namespace_id": &b.NamespaceID,
 		"number":       &b.Number,
 		"manifest":     &b.Manifest,
+		"params":       &b.Parameters,
 		"status":       &b.Status,
 		"output":       &b.Output,
 		"secret":       &b.Secret,
@@ -271,6 +272,7 @@ func (b *Build) Params() database.Params {
 		"namespace_id": database.CreateOnlyParam(b.NamespaceID),
 		"number":       database.CreateOnlyParam(b.Number),
 		"manifest":     database.CreateOnlyParam(b.Manifest),
+		"params":       database.CreateOnlyParam(b.Parameters),
 		"status":       database.CreateUpdateParam(b.Status),
 		"output":       database.CreateUpdateParam(b.Output),
 		"secret":       database.CreateOnlyParam(b.Secret),
@@ -337,6 +339,7 @@ func (b *Build) MarshalJSON() ([]byte, error) {
 		"namespace_id":  b.NamespaceID,
 		"number":        b.Number,
 		"manifest":      b.Manifest.String(),
+		"params":        b.Parameters,
 		"status":        b.Status,
 		"output":        b.Output,
 		"tags":          tags,
@@ -615,11 +618,14 @@ func (s *Store) Index(ctx context.Context, vals url.Values, opts ...query.Option
 	return paginator, nil
 }
 
+// Params is what a Build is created from. The Parameters are the values for
+// the params declared in the Manifest.
 type Params struct {
-	User     *auth.User
-	Trigger  *Trigger
-	Manifest manifest.Manifest
-	Tags     []string
+	User       *auth.User
+	Trigger    *Trigger
+	Manifest   manifest.Manifest
+	Parameters manifest.ParamValues
+	Tags       []string
 }
 
 func (s *Store) Create(ctx context.Context, p *Params) (*Build, error) {
@@ -636,10 +642,15 @@ func (s *Store) Create(ctx context.Context, p *Params) (*Build, error) {
 		return nil, driver.ErrUnknown(typ)
 	}
 
+	if _, err := p.Manifest.ParamEnv(p.Parameters); err != nil {
+		return nil, errors.Err(err)
+	}
+
 	b := Build{
-		UserID:   p.User.ID,
-		Manifest: p.Manifest,
-		User:     p.User,
+		UserID:     p.User.ID,
+		Manifest:   p.Manifest,
+		Parameters: p.Parameters,
+		User:       p.User,
 	}
 
 	if p.Manifest.Namespace != "" {
@@ -737,18 +748,164 @@ func (s *Store) Finished(ctx context.Context, b *Build) error {
 	return nil
 }
 
+// SetStatus sets the status of the given Build. Unlike Update, only the status
+// of the Build is updated. This is used to mark a running Build as waiting for
+// a manual job to be approved, and back again.
+func (s *Store) SetStatus(ctx context.Context, b *Build, status runner.Status) error {
+	b.Status = status
+
+	update := &Build{
+		loaded: []string{"status"},
+		Status: status,
+	}
+
+	if err := s.UpdateMany(ctx, update, query.Where("id", "=", query.Arg(b.ID))); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
 // Payload is how the Build is put onto the queue.
 type Payload struct {
 	Host    string // Host from which the Build was submitted.
 	BuildID int64  // ID of the Build.
 }
 
-var (
-	reSlug = regexp.MustCompile("[^a-zA-Z0-9.]")
-	reDup  = regexp.MustCompile("-{2,}")
-)
+// expandJob expands the given manifest Job into a Job for each of the given
+// matrix combinations. The needs of each expanded Job are the Jobs expanded
+// from the same combination. If there are no combinations then a single Job
+// is returned.
+func expandJob(job manifest.Job, combos []map[string]string) []*Job {
+	name := manifest.Slug(job.Name)
+
+	if len(combos) == 0 {
+		needs := make([]string, 0, len(job.Needs))
+
+		for _, need := range job.Needs {
+			needs = append(needs, manifest.Slug(need))
+		}
+
+		return []*Job{{
+			Name:     name,
+			Needs:    strings.Join(needs, "\n"),
+			Environ:  job.Env,
+			Workdir:  job.Workdir,
+			Quiet:    job.Quiet,
+			Commands: strings.Join(job.Commands, "\n"),
+			Inputs:   JobArtifacts(job.Inputs),
+			Timeout:  time.Duration(job.Timeout),
+			Retry:    job.Retry,
+			Attempt:  1,
+		}}
+	}
+
+	jj := make([]*Job, 0, len(combos))
+
+	for _, vars := range combos {
+		needs := make([]string, 0, len(job.Needs))
+
+		for _, need := range job.Needs {
+			needs = append(needs, manifest.Slug(manifest.MatrixName(need, vars)))
+		}
+
+		jj = append(jj, &Job{
+			Parent: database.Null[string]{
+				Elem:  name,
+				Valid: true,
+			},
+			Name:     manifest.Slug(manifest.MatrixName(job.Name, vars)),
+			Matrix:   JobMatrix(vars),
+			Needs:    strings.Join(needs, "\n"),
+			Environ:  job.Env,
+			Workdir:  job.Workdir,
+			Quiet:    job.Quiet,
+			Commands: strings.Join(job.Commands, "\n"),
+			Inputs:   JobArtifacts(job.Inputs),
+			Timeout:  time.Duration(job.Timeout),
+			Retry:    job.Retry,
+			Attempt:  1,
+		})
+	}
+	return jj
+}
+
+// applyDefaults merges the defaults of the namespace the given Build was
+// submitted to into the manifest of the Build, and stores the merged manifest.
+// The defaults of the root namespace are applied first, followed by those of
+// the namespace itself.
+func (s *Store) applyDefaults(ctx context.Context, b *Build) error {
+	if !b.NamespaceID.Valid {
+		return nil
+	}
+
+	namespaces := namespace.NewStore(s.Pool)
+
+	n := b.Namespace
+
+	if n == nil {
+		var (
+			ok  bool
+			err error
+		)
+
+		n, ok, err = namespaces.Get(ctx, query.Where("id", "=", query.Arg(b.NamespaceID)))
+
+		if err != nil {
+			return errors.Err(err)
+		}
+
+		if !ok {
+			return nil
+		}
+	}
+
+	nn := []*namespace.Namespace{n}
+
+	if n.RootID.Valid && n.RootID.Elem != n.ID {
+		root, ok, err := namespaces.Get(ctx, query.Where("id", "=", query.Arg(n.RootID)))
+
+		if err != nil {
+			return errors.Err(err)
+		}
+
+		if ok {
+			nn = append([]*namespace.Namespace{root}, nn...)
+		}
+	}
+
+	applied := false
+
+	for _, n := range nn {
+		if n.Defaults.IsZero() {
+			continue
+		}
+
+		b.Manifest = n.Defaults.Apply(b.Manifest)
+		applied = true
+	}
+
+	if !applied {
+		return nil
+	}
+
+	update := &Build{
+		loaded:   []string{"manifest"},
+		Manifest: b.Manifest,
+	}
+
+	if err := s.UpdateMany(ctx, update, query.Where("id", "=", query.Arg(b.ID))); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
 
 func (s *Store) Submit(ctx context.Context, host string, b *Build) error {
+	// The defaults are applied before anything else, so the merged manifest
+	// is what the build is executed with.
+	if err := s.applyDefaults(ctx, b); err != nil {
+		return errors.Err(err)
+	}
+
 	tx, err := s.Begin(ctx)
 
 	if err != nil {
@@ -792,7 +949,15 @@ func (s *Store) Submit(ctx context.Context, host string, b *Build) error {
 		return errors.Err(err)
 	}
 
-	for _, env := range b.Manifest.Env {
+	params, err := b.Manifest.ParamEnv(b.Parameters)
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	// The params come after the environment of the manifest, so they take
+	// precedence over any variables of the same name.
+	for _, env := range append(b.Manifest.Env, params...) {
 		parts := strings.SplitN(env, "=", 2)
 
 		vv = append(vv, &variable.Variable{
@@ -912,6 +1077,31 @@ func (s *Store) Submit(ctx context.Context, host string, b *Build) error {
 		stagetab[name] = s.ID
 	}
 
+	postStages := []struct {
+		name  string
+		jobs  []manifest.Job
+		stage Stage
+	}{
+		{manifest.AlwaysStage, b.Manifest.Always, Stage{Always: true}},
+		{manifest.AfterFailureStage, b.Manifest.AfterFailure, Stage{AfterFailure: true}},
+	}
+
+	for _, post := range postStages {
+		if len(post.jobs) == 0 {
+			continue
+		}
+
+		s := post.stage
+		s.BuildID = b.ID
+		s.Name = post.name
+		s.CreatedAt = time.Now()
+
+		if err := stages.CreateTx(ctx, tx, &s); err != nil {
+			return errors.Err(err)
+		}
+		stagetab[post.name] = s.ID
+	}
+
 	setupJobs := make([]manifest.Job, 0, len(b.Manifest.Sources)+1)
 	setupJobs = append(setupJobs, manifest.Job{
 		Stage: setup,
@@ -939,6 +1129,7 @@ func (s *Store) Submit(ctx context.Context, host string, b *Build) error {
 			StageID:   stagetab[job.Stage],
 			Name:      job.Name,
 			Commands:  strings.Join(job.Commands, "\n"),
+			Attempt:   1,
 			CreatedAt: time.Now(),
 		})
 
@@ -947,18 +1138,68 @@ func (s *Store) Submit(ctx context.Context, host string, b *Build) error {
 		}
 	}
 
+	if b.Trigger == nil {
+		b.Trigger, _, err = NewTriggerStore(s.Pool).Get(ctx, query.Where("build_id", "=", query.Arg(b.ID)))
+
+		if err != nil {
+			return errors.Err(err)
+		}
+	}
+
+	vars := make(map[string]string, len(vv))
+
+	for _, v := range vv {
+		if !v.Masked {
+			vars[v.Key] = v.Value
+		}
+	}
+
+	rulesCtx := b.Trigger.RulesContext(vars)
+
 	stage := ""
 	number := 1
 
-	artifacts := &ArtifactStore{
-		Store:  NewArtifactStore(s.Pool),
-		Hasher: s.Hasher,
+	combos := b.Manifest.Matrix.Combinations()
+
+	manifestJobs := make([]manifest.Job, 0, len(b.Manifest.Jobs))
+
+	// Jobs filtered out by the rules of the manifest are still created, but
+	// as skipped. Jobs that need a skipped job no longer need it. Jobs are
+	// keyed by stage and name, so a skipped Job does not affect a Job of the
+	// same name in another stage.
+	type stageJob struct {
+		stage, name string
 	}
 
-	for _, job := range b.Manifest.Jobs {
-		stageId, ok := stagetab[job.Stage]
+	skipped := make(map[stageJob]struct{})
 
-		if !ok {
+	// The stages of the Jobs, so a need can be resolved to a Job in the same
+	// stage first, then to a Job in the nearest earlier stage.
+	jobStages := make(map[string]map[string]struct{})
+
+	needStage := func(stage, need string) string {
+		reached := false
+
+		for i := len(b.Manifest.Stages) - 1; i >= 0; i-- {
+			name := b.Manifest.Stages[i]
+
+			if name == stage {
+				reached = true
+			}
+
+			if !reached {
+				continue
+			}
+
+			if _, ok := jobStages[need][name]; ok {
+				return name
+			}
+		}
+		return stage
+	}
+
+	for _, job := range b.Manifest.Jobs {
+		if _, ok := stagetab[job.Stage]; !ok {
 			continue
 		}
 
@@ -973,26 +1214,90 @@ func (s *Store) Submit(ctx context.Context, host string, b *Build) error {
 			job.Name = job.Stage + "." + strconv.Itoa(number)
 		}
 
-		// Slugify the job name.
-		job.Name = strings.TrimSpace(job.Name)
-		job.Name = reSlug.ReplaceAllString(job.Name, "-")
-		job.Name = reDup.ReplaceAllString(job.Name, "-")
-		job.Name = strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(job.Name, "-"), "-"))
+		manifestJobs = append(manifestJobs, job)
 
-		j := Job{
-			BuildID:   b.ID,
-			StageID:   stageId,
-			Name:      job.Name,
-			Commands:  strings.Join(job.Commands, "\n"),
-			CreatedAt: time.Now(),
+		allow := b.Manifest.StageRules[job.Stage].Allow(rulesCtx) && job.Rules.Allow(rulesCtx)
+
+		for _, j := range expandJob(job, combos) {
+			if jobStages[j.Name] == nil {
+				jobStages[j.Name] = make(map[string]struct{})
+			}
+			jobStages[j.Name][job.Stage] = struct{}{}
+
+			if !allow {
+				skipped[stageJob{job.Stage, j.Name}] = struct{}{}
+			}
+		}
+	}
+
+	postJobs := b.Manifest.PostJobs()
+
+	for _, job := range postJobs {
+		if !job.Rules.Allow(rulesCtx) {
+			skipped[stageJob{job.Stage, manifest.Slug(job.Name)}] = struct{}{}
 		}
+	}
 
-		if err := jobs.CreateTx(ctx, tx, &j); err != nil {
-			return errors.Err(err)
+	artifacts := &ArtifactStore{
+		Store:  NewArtifactStore(s.Pool),
+		Hasher: s.Hasher,
+	}
+
+	// The always, and after failure jobs are not expanded from the matrix.
+	post := len(manifestJobs)
+	manifestJobs = append(manifestJobs, postJobs...)
+
+	for i, job := range manifestJobs {
+		jobCombos := combos
+
+		if i >= post {
+			jobCombos = nil
 		}
 
-		if len(job.Artifacts) > 0 {
-			for src, dst := range job.Artifacts {
+		for _, j := range expandJob(job, jobCombos) {
+			j.BuildID = b.ID
+			j.StageID = stagetab[job.Stage]
+			j.Shell = b.Manifest.JobShell(job)
+			j.Manual = b.Manifest.JobManual(job)
+			j.CreatedAt = time.Now()
+
+			if _, ok := skipped[stageJob{job.Stage, j.Name}]; ok {
+				j.Status = runner.Skipped
+
+				if err := jobs.CreateTx(ctx, tx, j); err != nil {
+					return errors.Err(err)
+				}
+				continue
+			}
+
+			needs := make([]string, 0, len(j.NeedsList()))
+
+			for _, need := range j.NeedsList() {
+				if _, ok := skipped[stageJob{needStage(job.Stage, need), need}]; !ok {
+					needs = append(needs, need)
+				}
+			}
+
+			j.Needs = strings.Join(needs, "\n")
+			j.ArtifactPaths = make(JobArtifacts, len(job.Artifacts))
+
+			for src, dst := range manifest.MatrixArtifacts(job.Artifacts, j.Matrix) {
+				j.ArtifactPaths[src] = dst
+			}
+
+			if err := jobs.CreateTx(ctx, tx, j); err != nil {
+				return errors.Err(err)
+			}
+
+			// Only the artifacts with names that are known up front are
+			// created now, the rest are created as they are collected.
+			for _, a := range runner.Artifacts(runner.Passthrough(j.ArtifactPaths)) {
+				if !a.Known() {
+					continue
+				}
+
+				src, dst := a.Source, a.Name
+
 				err := artifacts.CreateTx(ctx, tx, &Artifact{
 					UserID:    b.UserID,
 					BuildID:   b.ID,
@@ -1041,10 +1346,16 @@ func (s *Store) Orphan(ctx context.Context, b *Build) error {
 	}
 
 	j := Job{
-		Status: runner.Queued,
+		Attempt: 1,
+		Status:  runner.Queued,
+	}
+
+	opts := []query.Option{
+		query.Where("build_id", "=", query.Arg(b.ID)),
+		query.Where("status", "!=", query.Arg(runner.Skipped)),
 	}
 
-	if err := NewJobStore(s.Pool).UpdateMany(ctx, &j, query.Where("build_id", "=", query.Arg(b.ID))); err != nil {
+	if err := NewJobStore(s.Pool).UpdateMany(ctx, &j, opts...); err != nil {
 		return errors.Err(err)
 	}
 
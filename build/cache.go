@@ -0,0 +1,278 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"djinn-ci.com/crypto"
+	"djinn-ci.com/database"
+	"djinn-ci.com/errors"
+
+	"github.com/andrewpillar/fs"
+	"github.com/andrewpillar/query"
+)
+
+// Cache represents a cache archive that is restored into, and saved from the
+// jobs of a build. Caches are shared between the builds of a namespace, or
+// between the builds of a user that are not in a namespace. The key of a
+// Cache is unique within this scope, so any build in the scope may overwrite
+// the Cache, whichever branch it is for.
+type Cache struct {
+	loaded      []string
+	ID          int64
+	UserID      int64
+	NamespaceID database.Null[int64]
+	Key         string
+	Hash        string
+	Size        int64
+	CreatedAt   time.Time
+	UsedAt      time.Time
+}
+
+var _ database.Model = (*Cache)(nil)
+
+func (c *Cache) Primary() (string, any) { return "id", c.ID }
+
+func (c *Cache) Scan(r *database.Row) error {
+	valtab := map[string]any{
+		"id":           &c.ID,
+		"user_id":      &c.UserID,
+		"namespace_id": &c.NamespaceID,
+		"key":          &c.Key,
+		"hash":         &c.Hash,
+		"size":         &c.Size,
+		"created_at":   &c.CreatedAt,
+		"used_at":      &c.UsedAt,
+	}
+
+	if err := database.Scan(r, valtab); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
+func (c *Cache) Params() database.Params {
+	params := database.Params{
+		"id":           database.ImmutableParam(c.ID),
+		"user_id":      database.CreateOnlyParam(c.UserID),
+		"namespace_id": database.CreateOnlyParam(c.NamespaceID),
+		"key":          database.CreateOnlyParam(c.Key),
+		"hash":         database.CreateOnlyParam(c.Hash),
+		"size":         database.CreateUpdateParam(c.Size),
+		"created_at":   database.CreateOnlyParam(c.CreatedAt),
+		"used_at":      database.CreateUpdateParam(c.UsedAt),
+	}
+
+	if len(c.loaded) > 0 {
+		params.Only(c.loaded...)
+	}
+	return params
+}
+
+func (*Cache) Bind(database.Model)       {}
+func (*Cache) Endpoint(...string) string { return "" }
+
+func (c *Cache) MarshalJSON() ([]byte, error) {
+	if c == nil {
+		return []byte("null"), nil
+	}
+
+	b, err := json.Marshal(map[string]any{
+		"id":           c.ID,
+		"user_id":      c.UserID,
+		"namespace_id": c.NamespaceID,
+		"key":          c.Key,
+		"size":         c.Size,
+		"created_at":   c.CreatedAt,
+		"used_at":      c.UsedAt,
+	})
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return b, nil
+}
+
+const cacheTable = "build_caches"
+
+type CacheStore struct {
+	*database.Store[*Cache]
+
+	FS     fs.FS
+	Hasher *crypto.Hasher
+}
+
+func NewCacheStore(pool *database.Pool) *database.Store[*Cache] {
+	return database.NewStore[*Cache](pool, cacheTable, func() *Cache {
+		return &Cache{}
+	})
+}
+
+// Delete deletes the given caches, and removes their archives from the
+// filestore.
+func (s *CacheStore) Delete(ctx context.Context, cc ...*Cache) error {
+	if len(cc) == 0 {
+		return nil
+	}
+
+	if err := s.Store.Delete(ctx, cc...); err != nil {
+		return errors.Err(err)
+	}
+
+	for _, c := range cc {
+		if err := s.FS.Remove(c.Hash); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return errors.Err(err)
+			}
+		}
+	}
+	return nil
+}
+
+type cacheFilestore struct {
+	fs.FS
+
+	store *CacheStore
+	build *Build
+}
+
+// get returns the Cache with the given key that is visible to the build. This
+// is either the Cache in the namespace of the build, or the Cache of the
+// build's user if the build is not in a namespace.
+func (s *cacheFilestore) get(ctx context.Context, key string) (*Cache, bool, error) {
+	opts := []query.Option{
+		query.Where("key", "=", query.Arg(key)),
+	}
+
+	if s.build.NamespaceID.Valid {
+		opts = append(opts, query.Where("namespace_id", "=", query.Arg(s.build.NamespaceID)))
+	} else {
+		opts = append(opts,
+			query.Where("user_id", "=", query.Arg(s.build.UserID)),
+			query.Where("namespace_id", "IS", query.Lit("NULL")),
+		)
+	}
+
+	c, ok, err := s.store.Get(ctx, opts...)
+
+	if err != nil {
+		return nil, false, errors.Err(err)
+	}
+	return c, ok, nil
+}
+
+func (s *cacheFilestore) Open(name string) (fs.File, error) {
+	ctx := context.Background()
+
+	c, ok, err := s.get(ctx, name)
+
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	f, err := s.store.FS.Open(c.Hash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.loaded = []string{"used_at"}
+	c.UsedAt = time.Now()
+
+	if err := s.store.Update(ctx, c); err != nil {
+		f.Close()
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return f, nil
+}
+
+func (s *cacheFilestore) Put(f fs.File) (fs.File, error) {
+	info, err := f.Stat()
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+
+	name := info.Name()
+
+	ctx := context.Background()
+
+	c, ok, err := s.get(ctx, name)
+
+	if err != nil {
+		return nil, &fs.PathError{Op: "put", Path: name, Err: err}
+	}
+
+	if !ok {
+		hash, err := s.store.Hasher.HashNow()
+
+		if err != nil {
+			return nil, &fs.PathError{Op: "put", Path: name, Err: err}
+		}
+
+		c = &Cache{
+			UserID:      s.build.UserID,
+			NamespaceID: s.build.NamespaceID,
+			Key:         name,
+			Hash:        hash,
+			CreatedAt:   time.Now(),
+		}
+	}
+
+	f, err = s.store.FS.Put(fs.Rename(f, c.Hash))
+
+	if err != nil {
+		return nil, &fs.PathError{Op: "put", Path: name, Err: err}
+	}
+
+	c.Size = info.Size()
+	c.UsedAt = time.Now()
+
+	if err := s.upsert(ctx, c); err != nil {
+		return nil, &fs.PathError{Op: "put", Path: name, Err: err}
+	}
+	return f, nil
+}
+
+// upsert creates the given Cache, or updates the Cache with the same key if
+// one was created by another build after this build looked it up. The last
+// build to put the Cache wins.
+func (s *cacheFilestore) upsert(ctx context.Context, c *Cache) error {
+	q := query.Insert(
+		cacheTable,
+		query.Columns("user_id", "namespace_id", "key", "hash", "size", "created_at", "used_at"),
+		query.Values(c.UserID, c.NamespaceID, c.Key, c.Hash, c.Size, c.CreatedAt, c.UsedAt),
+	)
+
+	// The conflict target must match one of the partial unique indexes on
+	// the table, depending on whether the Cache is in a namespace.
+	target := "(user_id, key) WHERE namespace_id IS NULL"
+
+	if c.NamespaceID.Valid {
+		target = "(namespace_id, key) WHERE namespace_id IS NOT NULL"
+	}
+
+	stmt := q.Build() + " ON CONFLICT " + target +
+		" DO UPDATE SET hash = EXCLUDED.hash, size = EXCLUDED.size, used_at = EXCLUDED.used_at" +
+		" RETURNING id"
+
+	if err := s.store.QueryRow(ctx, stmt, q.Args()...).Scan(&c.ID); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
+// Filestore returns a filestore for restoring, and saving the caches of the
+// given Build. Caches are opened, and put by their key.
+func (s *CacheStore) Filestore(b *Build) fs.FS {
+	return &cacheFilestore{
+		FS:    s.FS,
+		store: s,
+		build: b,
+	}
+}
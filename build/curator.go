@@ -2,6 +2,8 @@ package build
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"djinn-ci.com/auth"
 	"djinn-ci.com/database"
@@ -98,3 +100,77 @@ func (c *Curator) Invoke() error {
 	}
 	return err
 }
+
+// CacheCurator is used for evicting caches that have not been used within the
+// configured age, and the least recently used caches of a namespace, or user
+// whose total size exceed the configured limit.
+type CacheCurator struct {
+	log    *log.Logger
+	caches *CacheStore
+	maxAge time.Duration
+	limit  int64
+}
+
+// NewCacheCurator creates a new curator for evicting caches from the given
+// store. If maxAge or limit are zero, then caches are not evicted by age, or
+// by size respectively.
+func NewCacheCurator(log *log.Logger, pool *database.Pool, store fs.FS, maxAge time.Duration, limit int64) CacheCurator {
+	return CacheCurator{
+		log: log,
+		caches: &CacheStore{
+			Store: NewCacheStore(pool),
+			FS:    store,
+		},
+		maxAge: maxAge,
+		limit:  limit,
+	}
+}
+
+// Invoke evicts any caches older than the maximum age, and any caches that
+// exceed the size limit of the namespace, or user they belong to.
+func (c *CacheCurator) Invoke() error {
+	ctx := context.Background()
+
+	cc, err := c.caches.Select(
+		ctx,
+		[]string{"id", "user_id", "namespace_id", "hash", "size", "used_at"},
+		query.OrderDesc("used_at"),
+	)
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	sumtab := make(map[string]int64)
+	curated := make([]*Cache, 0, len(cc))
+
+	for _, cache := range cc {
+		if c.maxAge > 0 && time.Since(cache.UsedAt) > c.maxAge {
+			c.log.Debug.Println("curating cache", cache.ID, "unused since", cache.UsedAt)
+
+			curated = append(curated, cache)
+			continue
+		}
+
+		owner := "user:" + strconv.FormatInt(cache.UserID, 10)
+
+		if cache.NamespaceID.Valid {
+			owner = "namespace:" + strconv.FormatInt(cache.NamespaceID.Elem, 10)
+		}
+
+		sum := sumtab[owner] + cache.Size
+
+		if c.limit > 0 && sum > c.limit {
+			c.log.Debug.Println("curating cache", cache.ID, "for", owner)
+
+			curated = append(curated, cache)
+			continue
+		}
+		sumtab[owner] = sum
+	}
+
+	if err := c.caches.Delete(ctx, curated...); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
@@ -1,13 +1,17 @@
 package http
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 
 	"djinn-ci.com/auth"
 	"djinn-ci.com/build"
 	"djinn-ci.com/database"
 	"djinn-ci.com/errors"
+	"djinn-ci.com/manifest"
+	"djinn-ci.com/namespace"
 	"djinn-ci.com/server"
 	"djinn-ci.com/user"
 
@@ -18,6 +22,9 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// maxManifestSize is the maximum size of a manifest that can be validated.
+const maxManifestSize = 1 << 20
+
 type API struct {
 	*Handler
 }
@@ -44,6 +51,46 @@ func (h API) Store(u *auth.User, w http.ResponseWriter, r *http.Request) {
 	webutil.JSON(w, b, http.StatusCreated)
 }
 
+// ValidateManifest lints the manifest in the request body, and responds with
+// the problems found. The manifest can either be sent as the body itself, or
+// as the "manifest" field of a JSON object, the same as when submitting a
+// build.
+func (h API) ValidateManifest(u *auth.User, w http.ResponseWriter, r *http.Request) {
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxManifestSize))
+
+	if err != nil {
+		h.InternalServerError(w, r, errors.Wrap(err, "Failed to read manifest"))
+		return
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var body struct {
+			Manifest string
+		}
+
+		if err := json.Unmarshal(b, &body); err != nil {
+			webutil.JSON(w, map[string]string{"message": "invalid json: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		b = []byte(body.Manifest)
+	}
+
+	templates := &namespace.TemplateStore{
+		Store: namespace.NewTemplateStore(h.DB),
+	}
+
+	errs := manifest.Lint(b, templates.Resolver(r.Context(), u))
+
+	if errs == nil {
+		errs = manifest.Errors{}
+	}
+
+	webutil.JSON(w, map[string]any{
+		"valid":  len(errs) == 0,
+		"errors": errs,
+	}, http.StatusOK)
+}
+
 func (h API) Show(u *auth.User, b *build.Build, w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -167,6 +214,33 @@ func (h API) ShowJob(u *auth.User, b *build.Build, w http.ResponseWriter, r *htt
 	webutil.JSON(w, j, http.StatusOK)
 }
 
+func (h API) ApproveJob(u *auth.User, b *build.Build, w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	j, err := h.Handler.ApproveJob(ctx, u, b, mux.Vars(r)["name"])
+
+	if err != nil {
+		if errors.Is(err, database.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, errNotWaiting) {
+			webutil.JSON(w, map[string]string{"message": "Job is not waiting for approval"}, http.StatusBadRequest)
+			return
+		}
+
+		h.InternalServerError(w, r, errors.Wrap(err, "Failed to approve job"))
+		return
+	}
+
+	if err := build.LoadJobRelations(ctx, h.DB, j); err != nil {
+		h.InternalServerError(w, r, errors.Wrap(err, "Failed to load job relations"))
+		return
+	}
+	webutil.JSON(w, j, http.StatusOK)
+}
+
 func (h API) Destroy(u *auth.User, b *build.Build, w http.ResponseWriter, r *http.Request) {
 	if err := b.Kill(h.Redis); err != nil {
 		h.InternalServerError(w, r, errors.Wrap(err, "Failed to kill build"))
@@ -297,11 +371,13 @@ func RegisterAPI(a auth.Authenticator, srv *server.Server) {
 
 	srv.Router.HandleFunc("/builds", index).Methods("GET")
 	srv.Router.HandleFunc("/builds", store).Methods("POST")
+	srv.Router.HandleFunc("/manifests/validate", srv.Restrict(a, []string{"build:read"}, api.ValidateManifest)).Methods("POST")
 
 	show := srv.Optional(a, api.Build(api.Show))
 	destroy := srv.Restrict(a, []string{"build:delete"}, api.Build(api.Destroy))
 	pin := srv.Restrict(a, []string{"build:write"}, api.Build(api.TogglePin))
 	showJob := srv.Restrict(a, []string{"build:read"}, api.Build(api.ShowJob))
+	approveJob := srv.Restrict(a, []string{"build:write"}, api.Build(api.ApproveJob))
 	download := srv.Restrict(a, []string{"build:read"}, api.Build(api.Download))
 	storeTag := srv.Restrict(a, []string{"build:write"}, api.Build(api.StoreTag))
 	showTag := srv.Restrict(a, []string{"build:read"}, api.Build(api.ShowTag))
@@ -317,6 +393,7 @@ func RegisterAPI(a auth.Authenticator, srv *server.Server) {
 	sr.HandleFunc("/keys", show).Methods("GET")
 	sr.HandleFunc("/jobs", show).Methods("GET")
 	sr.HandleFunc("/jobs/{name}", showJob).Methods("GET")
+	sr.HandleFunc("/jobs/{name}/approve", approveJob).Methods("PATCH")
 	sr.HandleFunc("/artifacts", show).Methods("GET")
 	sr.HandleFunc("/artifacts/{name}", download).Methods("GET")
 	sr.HandleFunc("/tags", show).Methods("GET")
@@ -108,6 +108,7 @@ type Form struct {
 
 	Drivers  map[string]struct{} `json:"-" schema:"-"`
 	Manifest manifest.Manifest
+	Params   manifest.ParamValues
 	Comment  string
 	Tags     tags
 }
@@ -117,6 +118,7 @@ var _ webutil.Form = (*Form)(nil)
 func (f *Form) Fields() map[string]string {
 	return map[string]string{
 		"manifest": f.Manifest.String(),
+		"params":   f.Params.String(),
 		"comment":  f.Comment,
 		"tags":     f.Tags.String(),
 	}
@@ -150,12 +152,26 @@ func (f *Form) Validate(ctx context.Context) error {
 		webutil.WrapFieldError,
 	)
 
+	templates := &namespace.TemplateStore{
+		Store: namespace.NewTemplateStore(f.DB),
+	}
+
+	resolve := templates.Resolver(ctx, f.User)
+
+	// The manifest is linted as it was given so any problems are reported
+	// with their position, and is submitted with its includes merged in.
+	raw := f.Manifest
+
+	if m, err := f.Manifest.Resolve(resolve); err == nil {
+		f.Manifest = m
+	}
+
 	v.Add("manifest", f.Manifest, webutil.FieldRequired)
-	v.Add("manifest", f.Manifest, driverValid(f.Drivers))
-	v.Add("manifest", f.Manifest, func(_ context.Context, v any) error {
+	v.Add("manifest", raw, func(_ context.Context, v any) error {
 		m := v.(manifest.Manifest)
-		return m.Validate()
+		return m.Lint(resolve).Err()
 	})
+	v.Add("manifest", f.Manifest, driverValid(f.Drivers))
 	v.Add("manifest", f.Manifest.Namespace, func(ctx context.Context, v any) error {
 		p, err := namespace.ParsePath(v.(string))
 
@@ -177,6 +193,11 @@ func (f *Form) Validate(ctx context.Context) error {
 		return nil
 	})
 
+	v.Add("params", f.Params, func(_ context.Context, v any) error {
+		_, err := f.Manifest.ParamEnv(v.(manifest.ParamValues))
+		return err
+	})
+
 	errs := v.Validate(ctx)
 
 	return errs.Err()
@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"djinn-ci.com/auth"
 	"djinn-ci.com/build"
@@ -10,6 +11,7 @@ import (
 	"djinn-ci.com/errors"
 	"djinn-ci.com/namespace"
 	"djinn-ci.com/object"
+	"djinn-ci.com/runner"
 	"djinn-ci.com/server"
 	"djinn-ci.com/user"
 
@@ -27,6 +29,7 @@ type Handler struct {
 	Builds     *build.Store
 	Artifacts  *build.ArtifactStore
 	Jobs       build.JobStore
+	Attempts   *database.Store[*build.JobAttempt]
 	Objects    *object.Store
 	Namespaces *database.Store[*namespace.Namespace]
 	Variables  *database.Store[*build.Variable]
@@ -48,6 +51,7 @@ func NewHandler(srv *server.Server) *Handler {
 		Jobs: build.JobStore{
 			Store: build.NewJobStore(srv.DB),
 		},
+		Attempts: build.NewJobAttemptStore(srv.DB),
 		Objects: &object.Store{
 			Store: object.NewStore(srv.DB),
 		},
@@ -213,8 +217,9 @@ func (h *Handler) Store(u *auth.User, r *http.Request) (*build.Build, *Form, err
 				"username": u.Username,
 			},
 		},
-		Manifest: f.Manifest,
-		Tags:     f.Tags,
+		Manifest:   f.Manifest,
+		Parameters: f.Params,
+		Tags:       f.Tags,
 	}
 
 	b, err := h.Builds.Create(ctx, &p)
@@ -277,3 +282,49 @@ func (h *Handler) DestroyTag(ctx context.Context, b *build.Build, name string) e
 	}
 	return nil
 }
+
+// errNotWaiting is returned when approving a job that is not waiting for
+// approval, or has already been approved.
+var errNotWaiting = errors.New("job is not waiting for approval")
+
+// ApproveJob approves the job of the given name in the given Build, and
+// signals the worker executing the Build that the job has been approved. If
+// the job is not waiting for approval then errNotWaiting is returned.
+func (h *Handler) ApproveJob(ctx context.Context, u *auth.User, b *build.Build, name string) (*build.Job, error) {
+	j, ok, err := h.Jobs.Get(
+		ctx,
+		query.Where("build_id", "=", query.Arg(b.ID)),
+		query.Where("name", "=", query.Arg(name)),
+	)
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+
+	if !ok {
+		return nil, database.ErrNoRows
+	}
+
+	if j.Status != runner.Waiting {
+		return nil, errNotWaiting
+	}
+
+	ok, err = h.Jobs.Approve(ctx, j, u)
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+
+	if !ok {
+		return nil, errNotWaiting
+	}
+
+	j.Build = b
+
+	key := "approve-" + strconv.FormatInt(b.ID, 10)
+
+	if _, err := h.Redis.Publish(key, j.Name).Result(); err != nil {
+		return nil, errors.Err(err)
+	}
+	return j, nil
+}
@@ -327,6 +327,18 @@ func (h UI) ShowJob(u *auth.User, b *build.Build, w http.ResponseWriter, r *http
 		return
 	}
 
+	j.Attempts, err = h.Attempts.All(ctx, query.Where("job_id", "=", query.Arg(j.ID)), query.OrderAsc("number"))
+
+	if err != nil {
+		h.InternalServerError(w, r, errors.Err(err))
+		return
+	}
+
+	if err := user.Loader(h.DB).Load(ctx, "approved_by", "id", j); err != nil {
+		h.InternalServerError(w, r, errors.Err(err))
+		return
+	}
+
 	b.Trigger, _, err = h.Triggers.Get(ctx, query.Where("build_id", "=", query.Arg(b.ID)))
 
 	if err != nil {
@@ -362,6 +374,29 @@ func (h UI) ShowJob(u *auth.User, b *build.Build, w http.ResponseWriter, r *http
 	h.Template(w, r, tmpl, http.StatusOK)
 }
 
+func (h UI) ApproveJob(u *auth.User, b *build.Build, w http.ResponseWriter, r *http.Request) {
+	sess, _ := h.Session(r)
+
+	if _, err := h.Handler.ApproveJob(r.Context(), u, b, mux.Vars(r)["name"]); err != nil {
+		if errors.Is(err, database.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+
+		if errors.Is(err, errNotWaiting) {
+			alert.Flash(sess, alert.Danger, "Job is not waiting for approval")
+			h.RedirectBack(w, r)
+			return
+		}
+
+		h.InternalServerError(w, r, errors.Wrap(err, "Failed to approve job"))
+		return
+	}
+
+	alert.Flash(sess, alert.Success, "Job approved")
+	h.RedirectBack(w, r)
+}
+
 func (h UI) Download(u *auth.User, b *build.Build, w http.ResponseWriter, r *http.Request) {
 	name := mux.Vars(r)["name"]
 
@@ -442,6 +477,7 @@ func RegisterUI(a auth.Authenticator, srv *server.Server) {
 	destroy := srv.Restrict(a, []string{"build:delete"}, ui.Build(ui.Destroy))
 	pin := srv.Restrict(a, []string{"build:write"}, ui.Build(ui.TogglePin))
 	showJob := srv.Restrict(a, []string{"build:read"}, ui.Build(ui.ShowJob))
+	approveJob := srv.Restrict(a, []string{"build:write"}, ui.Build(ui.ApproveJob))
 	download := srv.Restrict(a, []string{"build:read"}, ui.Build(ui.Download))
 	storeTag := srv.Restrict(a, []string{"build:write"}, ui.Build(ui.StoreTag))
 	destroyTag := srv.Restrict(a, []string{"build:delete"}, ui.Build(ui.DestroyTag))
@@ -459,6 +495,7 @@ func RegisterUI(a auth.Authenticator, srv *server.Server) {
 	sr.HandleFunc("/keys", show).Methods("GET")
 	sr.HandleFunc("/jobs/{name}", showJob).Methods("GET")
 	sr.HandleFunc("/jobs/{name}/output/raw", showJob).Methods("GET")
+	sr.HandleFunc("/jobs/{name}/approve", approveJob).Methods("PATCH")
 	sr.HandleFunc("/artifacts", show).Methods("GET")
 	sr.HandleFunc("/artifacts/{name}", download).Methods("GET")
 	sr.HandleFunc("/tags", show).Methods("GET")
@@ -2,36 +2,263 @@ package build
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"net/url"
+	"strings"
 	"time"
 
+	"djinn-ci.com/auth"
 	"djinn-ci.com/database"
 	"djinn-ci.com/env"
 	"djinn-ci.com/errors"
+	"djinn-ci.com/manifest"
 	"djinn-ci.com/runner"
+	"djinn-ci.com/user"
 
 	"github.com/andrewpillar/query"
 )
 
-// Job represents a single build Job.
+// JobMatrix is the matrix combination that a Job was expanded from.
+type JobMatrix map[string]string
+
+var (
+	_ sql.Scanner   = (*JobMatrix)(nil)
+	_ driver.Valuer = (*JobMatrix)(nil)
+)
+
+func (m *JobMatrix) Scan(val any) error {
+	v, err := driver.String.ConvertValue(val)
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	b, ok := v.([]byte)
+
+	if !ok {
+		return errors.New("build: could not type assert JobMatrix to byte slice")
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(b, m); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
+func (m JobMatrix) Value() (driver.Value, error) {
+	if m == nil {
+		return driver.Value("{}"), nil
+	}
+
+	b, err := json.Marshal(m)
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return driver.Value(string(b)), nil
+}
+
+// JobEnv is the environment variables declared on a Job in the manifest.
+type JobEnv []string
+
+var (
+	_ sql.Scanner   = (*JobEnv)(nil)
+	_ driver.Valuer = (*JobEnv)(nil)
+)
+
+func (e *JobEnv) Scan(val any) error {
+	v, err := driver.String.ConvertValue(val)
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	b, ok := v.([]byte)
+
+	if !ok {
+		return errors.New("build: could not type assert JobEnv to byte slice")
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(b, e); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
+func (e JobEnv) Value() (driver.Value, error) {
+	if e == nil {
+		return driver.Value("[]"), nil
+	}
+
+	b, err := json.Marshal(e)
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return driver.Value(string(b)), nil
+}
+
+// JobArtifacts is the artifacts to collect from a Job, mapping the source of
+// each artifact to its name. Unlike the Artifacts of a Job, this includes the
+// directories, and patterns whose files are not known until they are
+// collected. This is also used for the inputs of a Job, mapping the name of
+// each artifact to where it is placed.
+type JobArtifacts map[string]string
+
+var (
+	_ sql.Scanner   = (*JobArtifacts)(nil)
+	_ driver.Valuer = (*JobArtifacts)(nil)
+)
+
+func (a *JobArtifacts) Scan(val any) error {
+	v, err := driver.String.ConvertValue(val)
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	b, ok := v.([]byte)
+
+	if !ok {
+		return errors.New("build: could not type assert JobArtifacts to byte slice")
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(b, a); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
+func (a JobArtifacts) Value() (driver.Value, error) {
+	if a == nil {
+		return driver.Value("{}"), nil
+	}
+
+	b, err := json.Marshal(a)
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return driver.Value(string(b)), nil
+}
+
+// JobCommands is the result of each command executed in an attempt at a Job.
+type JobCommands []runner.CommandResult
+
+var (
+	_ sql.Scanner   = (*JobCommands)(nil)
+	_ driver.Valuer = (*JobCommands)(nil)
+)
+
+func (c *JobCommands) Scan(val any) error {
+	v, err := driver.String.ConvertValue(val)
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	b, ok := v.([]byte)
+
+	if !ok {
+		return errors.New("build: could not type assert JobCommands to byte slice")
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(b, c); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
+func (c JobCommands) Value() (driver.Value, error) {
+	if c == nil {
+		return driver.Value("[]"), nil
+	}
+
+	b, err := json.Marshal(c)
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return driver.Value(string(b)), nil
+}
+
+// Failed returns the index of the first command that exited with a non-zero
+// exit code, or -1 if none did.
+func (c JobCommands) Failed() int {
+	for i, res := range c {
+		if res.ExitCode != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Job represents a single build Job. If the Job was expanded from the build
+// matrix then Parent will be the name of the Job it was expanded from, and
+// Matrix will be the combination of values it was expanded with. Attempt is
+// the number of the current attempt at the Job, the Output and Status of the
+// Job are those of the current attempt, and the Attempts are the previous
+// attempts that were retried. Environ and Workdir are the environment
+// variables, and working directory declared on the Job in the manifest. Shell
+// is the shell the Job is executed with, and Quiet is whether its commands are
+// echoed. ArtifactPaths is the artifacts to collect from the Job. Inputs is the
+// artifacts of earlier Jobs to place before the Job is executed. Results is the
+// result of each command executed in the current attempt. Manual is whether
+// the Job has to be approved before it is executed, ApprovedBy and ApprovedAt
+// record who approved the Job and when.
 type Job struct {
 	loaded []string
 
-	ID         int64
-	BuildID    int64
-	StageID    int64
-	Name       string
-	Commands   string
-	Status     runner.Status
-	Output     database.Null[string]
-	CreatedAt  time.Time
-	StartedAt  database.Null[time.Time]
-	FinishedAt database.Null[time.Time]
+	ID            int64
+	BuildID       int64
+	StageID       int64
+	Parent        database.Null[string]
+	Name          string
+	Matrix        JobMatrix
+	Needs         string
+	Environ       JobEnv
+	Workdir       string
+	Shell         string
+	Quiet         bool
+	Manual        bool
+	Commands      string
+	ArtifactPaths JobArtifacts
+	Inputs        JobArtifacts
+	Timeout       time.Duration
+	Retry         manifest.Retry
+	Attempt       int
+	Status        runner.Status
+	Output        database.Null[string]
+	Results       JobCommands
+	ApprovedBy    database.Null[int64]
+	ApprovedAt    database.Null[time.Time]
+	CreatedAt     time.Time
+	StartedAt     database.Null[time.Time]
+	FinishedAt    database.Null[time.Time]
 
 	Build     *Build
 	Stage     *Stage
+	Approver  *auth.User
 	Artifacts []*Artifact
+	Attempts  []*JobAttempt
 }
 
 func LoadJobRelations(ctx context.Context, pool *database.Pool, jj ...*Job) error {
@@ -50,6 +277,18 @@ func LoadJobRelations(ctx context.Context, pool *database.Pool, jj ...*Job) erro
 				return &Artifact{}
 			}),
 		},
+		{
+			From: "id",
+			To:   "job_id",
+			Loader: database.ModelLoader(pool, jobAttemptTable, func() database.Model {
+				return &JobAttempt{}
+			}),
+		},
+		{
+			From:   "approved_by",
+			To:     "id",
+			Loader: user.Loader(pool),
+		},
 	}
 
 	if err := database.LoadRelations[*Job](ctx, jj, rels...); err != nil {
@@ -64,16 +303,32 @@ func (j *Job) Primary() (string, any) { return "id", j.ID }
 
 func (j *Job) Scan(r *database.Row) error {
 	valtab := map[string]any{
-		"id":          &j.ID,
-		"build_id":    &j.BuildID,
-		"stage_id":    &j.StageID,
-		"name":        &j.Name,
-		"commands":    &j.Commands,
-		"status":      &j.Status,
-		"output":      &j.Output,
-		"created_at":  &j.CreatedAt,
-		"started_at":  &j.StartedAt,
-		"finished_at": &j.FinishedAt,
+		"id":              &j.ID,
+		"build_id":        &j.BuildID,
+		"stage_id":        &j.StageID,
+		"parent":          &j.Parent,
+		"name":            &j.Name,
+		"matrix":          &j.Matrix,
+		"needs":           &j.Needs,
+		"env":             &j.Environ,
+		"workdir":         &j.Workdir,
+		"shell":           &j.Shell,
+		"quiet":           &j.Quiet,
+		"manual":          &j.Manual,
+		"commands":        &j.Commands,
+		"artifact_paths":  &j.ArtifactPaths,
+		"inputs":          &j.Inputs,
+		"timeout":         &j.Timeout,
+		"retry":           &j.Retry,
+		"attempt":         &j.Attempt,
+		"status":          &j.Status,
+		"output":          &j.Output,
+		"command_results": &j.Results,
+		"approved_by":     &j.ApprovedBy,
+		"approved_at":     &j.ApprovedAt,
+		"created_at":      &j.CreatedAt,
+		"started_at":      &j.StartedAt,
+		"finished_at":     &j.FinishedAt,
 	}
 
 	if err := database.Scan(r, valtab); err != nil {
@@ -86,16 +341,32 @@ func (j *Job) Scan(r *database.Row) error {
 
 func (j *Job) Params() database.Params {
 	params := database.Params{
-		"id":          database.ImmutableParam(j.ID),
-		"build_id":    database.CreateOnlyParam(j.BuildID),
-		"stage_id":    database.CreateOnlyParam(j.StageID),
-		"name":        database.CreateOnlyParam(j.Name),
-		"commands":    database.CreateOnlyParam(j.Commands),
-		"status":      database.CreateUpdateParam(j.Status),
-		"output":      database.UpdateOnlyParam(j.Output),
-		"created_at":  database.CreateOnlyParam(j.CreatedAt),
-		"started_at":  database.UpdateOnlyParam(j.StartedAt),
-		"finished_at": database.UpdateOnlyParam(j.FinishedAt),
+		"id":              database.ImmutableParam(j.ID),
+		"build_id":        database.CreateOnlyParam(j.BuildID),
+		"stage_id":        database.CreateOnlyParam(j.StageID),
+		"parent":          database.CreateOnlyParam(j.Parent),
+		"name":            database.CreateOnlyParam(j.Name),
+		"matrix":          database.CreateOnlyParam(j.Matrix),
+		"needs":           database.CreateOnlyParam(j.Needs),
+		"env":             database.CreateOnlyParam(j.Environ),
+		"workdir":         database.CreateOnlyParam(j.Workdir),
+		"shell":           database.CreateOnlyParam(j.Shell),
+		"quiet":           database.CreateOnlyParam(j.Quiet),
+		"manual":          database.CreateOnlyParam(j.Manual),
+		"commands":        database.CreateOnlyParam(j.Commands),
+		"artifact_paths":  database.CreateOnlyParam(j.ArtifactPaths),
+		"inputs":          database.CreateOnlyParam(j.Inputs),
+		"timeout":         database.CreateOnlyParam(j.Timeout),
+		"retry":           database.CreateOnlyParam(j.Retry),
+		"attempt":         database.CreateUpdateParam(j.Attempt),
+		"status":          database.CreateUpdateParam(j.Status),
+		"output":          database.UpdateOnlyParam(j.Output),
+		"command_results": database.UpdateOnlyParam(j.Results),
+		"approved_by":     database.UpdateOnlyParam(j.ApprovedBy),
+		"approved_at":     database.UpdateOnlyParam(j.ApprovedAt),
+		"created_at":      database.CreateOnlyParam(j.CreatedAt),
+		"started_at":      database.UpdateOnlyParam(j.StartedAt),
+		"finished_at":     database.UpdateOnlyParam(j.FinishedAt),
 	}
 
 	if len(j.loaded) > 0 {
@@ -104,8 +375,9 @@ func (j *Job) Params() database.Params {
 	return params
 }
 
-// Bind the given Model to the current Job if it is one of Build, Stage, or
-// Artifact, and if there is a direct relation between the two.
+// Bind the given Model to the current Job if it is one of Build, Stage,
+// Artifact, JobAttempt, or the User who approved the Job, and if there is a
+// direct relation between the two.
 func (j *Job) Bind(m database.Model) {
 	switch v := m.(type) {
 	case *Build:
@@ -120,6 +392,14 @@ func (j *Job) Bind(m database.Model) {
 		if j.ID == v.JobID {
 			j.Artifacts = append(j.Artifacts, v)
 		}
+	case *JobAttempt:
+		if j.ID == v.JobID {
+			j.Attempts = append(j.Attempts, v)
+		}
+	case *auth.User:
+		if j.ApprovedBy.Valid && j.ApprovedBy.Elem == v.ID {
+			j.Approver = v
+		}
 	}
 }
 
@@ -130,10 +410,26 @@ func (j *Job) MarshalJSON() ([]byte, error) {
 
 	b, err := json.Marshal(map[string]any{
 		"build_id":    j.BuildID,
+		"parent":      j.Parent,
 		"name":        j.Name,
+		"matrix":      j.Matrix,
+		"needs":       j.NeedsList(),
+		"env":         j.Environ,
+		"workdir":     j.Workdir,
+		"shell":       j.Shell,
+		"quiet":       j.Quiet,
+		"manual":      j.Manual,
 		"commands":    j.Commands,
+		"inputs":      j.Inputs,
+		"timeout":     j.Timeout.String(),
+		"retry":       j.Retry.Max,
+		"attempt":     j.Attempt,
+		"attempts":    j.Attempts,
 		"status":      j.Status,
 		"output":      j.Output,
+		"results":     j.Results,
+		"approved_by": j.Approver,
+		"approved_at": j.ApprovedAt,
 		"created_at":  j.CreatedAt,
 		"started_at":  j.StartedAt,
 		"finished_at": j.FinishedAt,
@@ -148,6 +444,21 @@ func (j *Job) MarshalJSON() ([]byte, error) {
 	return b, nil
 }
 
+// NeedsList returns the names of the jobs the current Job needs.
+func (j *Job) NeedsList() []string {
+	if j.Needs == "" {
+		return []string{}
+	}
+	return strings.Split(j.Needs, "\n")
+}
+
+// Env returns the matrix combination of the current Job as a slice of
+// environment variables, followed by the environment variables declared on
+// the Job.
+func (j *Job) Env() []string {
+	return append(manifest.MatrixEnv(j.Matrix), j.Environ...)
+}
+
 // Endpoint returns the endpoint for the current Job. this will only return an
 // endpoint if the current Job has a non-nil build. The given uris are appended
 // to the returned endpoint.
@@ -196,13 +507,69 @@ func (s JobStore) Finished(ctx context.Context, j *Job) error {
 	return nil
 }
 
+// Approve marks the given Job as approved by the given User. Only a Job that
+// is waiting for approval, and has not already been approved, is updated, so
+// false is returned if the Job could not be approved.
+func (s JobStore) Approve(ctx context.Context, j *Job, u *auth.User) (bool, error) {
+	if j.Status != runner.Waiting || j.ApprovedBy.Valid {
+		return false, nil
+	}
+
+	now := time.Now()
+
+	q := query.Update(
+		jobTable,
+		query.Set("approved_by", query.Arg(u.ID)),
+		query.Set("approved_at", query.Arg(now)),
+		query.Where("id", "=", query.Arg(j.ID)),
+		query.Where("status", "=", query.Arg(runner.Waiting)),
+		query.Where("approved_by", "IS", query.Lit("NULL")),
+	)
+
+	res, err := s.Exec(ctx, q.Build(), q.Args()...)
+
+	if err != nil {
+		return false, errors.Err(err)
+	}
+
+	// Nothing is updated if the Job was approved, or stopped waiting, since
+	// it was loaded.
+	if res.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	j.ApprovedBy = database.Null[int64]{
+		Elem:  u.ID,
+		Valid: true,
+	}
+	j.ApprovedAt = database.Null[time.Time]{
+		Elem:  now,
+		Valid: true,
+	}
+	j.Approver = u
+	return true, nil
+}
+
+// WhereParent returns a query option for getting the Jobs that were expanded
+// from the matrix Job with the given name.
+func WhereParent(parent string) query.Option {
+	return func(q query.Query) query.Query {
+		if parent == "" {
+			return q
+		}
+		return query.Where("parent", "=", query.Arg(parent))(q)
+	}
+}
+
 // Index returns the Jobs with the given query options applied. The given
-// url.Values are used to apply the database.Search, and WhereStatus query
-// options if the name, and search values are present in the underlying map.
+// url.Values are used to apply the database.Search, WhereStatus, and
+// WhereParent query options if the name, status, and parent values are
+// present in the underlying map.
 func (s JobStore) Index(ctx context.Context, vals url.Values, opts ...query.Option) ([]*Job, error) {
 	opts = append([]query.Option{
 		database.Search("name", vals.Get("name")),
 		WhereStatus(vals.Get("status")),
+		WhereParent(vals.Get("parent")),
 	}, opts...)
 
 	jj, err := s.All(ctx, append(opts, query.OrderAsc("created_at"))...)
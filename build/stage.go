@@ -12,12 +12,17 @@ import (
 	"github.com/andrewpillar/query"
 )
 
+// Stage represents a single stage of a Build. Always, and AfterFailure denote
+// whether the Stage holds the always, or after failure jobs from the
+// manifest.
 type Stage struct {
-	ID        int64
-	BuildID   int64
-	Name      string
-	CanFail   bool
-	CreatedAt time.Time
+	ID           int64
+	BuildID      int64
+	Name         string
+	CanFail      bool
+	Always       bool
+	AfterFailure bool
+	CreatedAt    time.Time
 
 	Jobs []*Job
 }
@@ -55,11 +60,13 @@ func (s *Stage) Primary() (string, any) { return "id", s.ID }
 
 func (s *Stage) Scan(r *database.Row) error {
 	valtab := map[string]any{
-		"id":         &s.ID,
-		"build_id":   &s.BuildID,
-		"name":       &s.Name,
-		"can_fail":   &s.CanFail,
-		"created_at": &s.CreatedAt,
+		"id":            &s.ID,
+		"build_id":      &s.BuildID,
+		"name":          &s.Name,
+		"can_fail":      &s.CanFail,
+		"always":        &s.Always,
+		"after_failure": &s.AfterFailure,
+		"created_at":    &s.CreatedAt,
 	}
 
 	if err := database.Scan(r, valtab); err != nil {
@@ -70,14 +77,50 @@ func (s *Stage) Scan(r *database.Row) error {
 
 func (s *Stage) Params() database.Params {
 	return database.Params{
-		"id":         database.ImmutableParam(s.ID),
-		"build_id":   database.CreateOnlyParam(s.BuildID),
-		"name":       database.CreateOnlyParam(s.Name),
-		"can_fail":   database.CreateOnlyParam(s.CanFail),
-		"created_at": database.CreateOnlyParam(s.CreatedAt),
+		"id":            database.ImmutableParam(s.ID),
+		"build_id":      database.CreateOnlyParam(s.BuildID),
+		"name":          database.CreateOnlyParam(s.Name),
+		"can_fail":      database.CreateOnlyParam(s.CanFail),
+		"always":        database.CreateOnlyParam(s.Always),
+		"after_failure": database.CreateOnlyParam(s.AfterFailure),
+		"created_at":    database.CreateOnlyParam(s.CreatedAt),
 	}
 }
 
+// JobGroup is a group of Jobs in a Stage. Jobs that were expanded from the
+// build matrix are grouped under the name of the Job they were expanded from.
+type JobGroup struct {
+	Parent string
+	Jobs   []*Job
+}
+
+// JobGroups returns the Jobs of the current Stage grouped by their matrix
+// parent. Jobs that were not expanded from the matrix are in a group of their
+// own with an empty Parent. Groups are returned in the order in which their
+// first Job appears in the Stage.
+func (s *Stage) JobGroups() []JobGroup {
+	groups := make([]JobGroup, 0, len(s.Jobs))
+	grouptab := make(map[string]int)
+
+	for _, j := range s.Jobs {
+		if !j.Parent.Valid {
+			groups = append(groups, JobGroup{Jobs: []*Job{j}})
+			continue
+		}
+
+		i, ok := grouptab[j.Parent.Elem]
+
+		if !ok {
+			i = len(groups)
+			grouptab[j.Parent.Elem] = i
+
+			groups = append(groups, JobGroup{Parent: j.Parent.Elem})
+		}
+		groups[i].Jobs = append(groups[i].Jobs, j)
+	}
+	return groups
+}
+
 func (s *Stage) Bind(m database.Model) {
 	if v, ok := m.(*Job); ok {
 		if s.ID == v.StageID {
@@ -97,8 +140,10 @@ func (*Stage) Endpoint(...string) string { return "" }
 
 func (s *Stage) Stage() *runner.Stage {
 	return &runner.Stage{
-		Name:    s.Name,
-		CanFail: s.CanFail,
+		Name:         s.Name,
+		CanFail:      s.CanFail,
+		Always:       s.Always,
+		AfterFailure: s.AfterFailure,
 	}
 }
 
@@ -10,6 +10,7 @@ import (
 
 	"djinn-ci.com/database"
 	"djinn-ci.com/errors"
+	"djinn-ci.com/manifest"
 )
 
 type TriggerType uint8
@@ -189,6 +190,30 @@ func (t *Trigger) MarshalJSON() ([]byte, error) {
 
 func (*Trigger) Endpoint(...string) string { return "" }
 
+// RulesContext returns the context for evaluating the rules of a manifest
+// against the current Trigger, and the given variables. For push triggers the
+// branch or tag is taken from the pushed ref, and for pull triggers the branch
+// is the branch being merged into.
+func (t *Trigger) RulesContext(vars map[string]string) manifest.Context {
+	ctx := manifest.Context{
+		Variables: vars,
+	}
+
+	if t == nil {
+		return ctx
+	}
+
+	ctx.Trigger = t.Type.String()
+
+	switch t.Type {
+	case Push:
+		ctx.Branch, ctx.Tag = manifest.ParseRef(t.Data["ref"])
+	case Pull:
+		ctx.Branch = t.Data["ref"]
+	}
+	return ctx
+}
+
 func (t *Trigger) CommentBody() string {
 	i := strings.Index(t.Comment, "\n")
 	wrap := false
@@ -85,6 +85,13 @@ func main() {
 
 	curator := build.NewCurator(log, db, artifacts)
 
+	var cacheCurator *build.CacheCurator
+
+	if caches, maxAge, limit := cfg.Caches(); caches != nil {
+		c := build.NewCacheCurator(log, db, caches, maxAge, limit)
+		cacheCurator = &c
+	}
+
 loop:
 	for {
 		select {
@@ -103,6 +110,12 @@ loop:
 				if err := curator.Invoke(); err != nil {
 					log.Error.Println(err)
 				}
+
+				if cacheCurator != nil {
+					if err := cacheCurator.Invoke(); err != nil {
+						log.Error.Println(err)
+					}
+				}
 			}()
 		case sig := <-c:
 			log.Info.Println("signal:", sig, "received, shutting down")
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 
 	"djinn-ci.com/config"
+	"djinn-ci.com/driver/docker"
 	"djinn-ci.com/manifest"
 	"djinn-ci.com/runner"
 	"djinn-ci.com/version"
@@ -18,6 +24,22 @@ import (
 	"github.com/andrewpillar/fs"
 )
 
+// dockerServices returns the given services from a manifest as services for
+// the docker driver.
+func dockerServices(services []manifest.Service) []docker.Service {
+	ss := make([]docker.Service, 0, len(services))
+
+	for _, s := range services {
+		ss = append(ss, docker.Service{
+			Image:   s.Image,
+			Alias:   s.Name(),
+			Env:     s.Env,
+			Command: s.Command,
+		})
+	}
+	return ss
+}
+
 func exiterr(err error) {
 	fmt.Fprintf(os.Stderr, "%s: %s\n", os.Args[0], err)
 	os.Exit(1)
@@ -25,16 +47,133 @@ func exiterr(err error) {
 
 const setupStage = "setup"
 
+// reports maps the format of a report to the path it should be written to,
+// this is set via the -report flag as format=path.
+type reports map[string]string
+
+func (r reports) String() string {
+	parts := make([]string, 0, len(r))
+
+	for format, path := range r {
+		parts = append(parts, format+"="+path)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (r reports) Set(s string) error {
+	format, path, ok := strings.Cut(s, "=")
+
+	if !ok || path == "" {
+		return errors.New("expected format=path")
+	}
+
+	if format != "junit" && format != "tap" {
+		return errors.New("unknown report format " + format)
+	}
+
+	r[format] = path
+	return nil
+}
+
+// prompter prompts for the approval of manual jobs, reading the answer from
+// the underlying reader. Prompts are made one at a time, since jobs may be
+// waiting for approval concurrently.
+type prompter struct {
+	mu  sync.Mutex
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// approve prompts for the approval of the given job, returning
+// runner.ErrNotApproved if the job was not approved.
+func (p *prompter) approve(ctx context.Context, j *runner.Job) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprintf(p.out, "Approve job %s? [y/N] ", j.FullName())
+
+	answer := make(chan string, 1)
+
+	go func() {
+		line, _ := p.in.ReadString('\n')
+		answer <- strings.ToLower(strings.TrimSpace(line))
+	}()
+
+	select {
+	case <-ctx.Done():
+		fmt.Fprintln(p.out)
+		return ctx.Err()
+	case s := <-answer:
+		if s != "y" && s != "yes" {
+			return runner.ErrNotApproved
+		}
+	}
+	return nil
+}
+
+// write writes each report for the given Runner to its path.
+func (r reports) write(rep *runner.Report, run *runner.Runner) error {
+	for format, path := range r {
+		f, err := os.Create(path)
+
+		if err != nil {
+			return err
+		}
+
+		write := rep.WriteJUnit
+
+		if format == "tap" {
+			write = rep.WriteTAP
+		}
+
+		if err := write(f, run); err != nil {
+			f.Close()
+			return err
+		}
+
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matrixInputs opens the inputs of a job expanded from the build matrix. The
+// artifact suffixed with the matrix combination of the job is preferred, this
+// mirrors how the inputs of a build are placed.
+type matrixInputs struct {
+	fs.FS
+
+	vars map[string]string
+}
+
+func (i *matrixInputs) Open(name string) (fs.File, error) {
+	if f, err := i.FS.Open(manifest.MatrixArtifact(name, i.vars)); err == nil {
+		return f, nil
+	}
+	return i.FS.Open(name)
+}
+
 func main() {
 	var (
 		showversion  bool
+		lint         bool
 		artifactsdir string
 		objectsdir   string
+		cachedir     string
 		manif        string
 		driverfile   string
+		templatedir  string
 		stage        string
+		parallelism  int
+		format       string
+		dryrun       bool
+		approve      bool
+		jobs         jobFilter
 	)
 
+	reportpaths := make(reports)
+
 	cfgdir, err := os.UserConfigDir()
 
 	if err != nil {
@@ -43,11 +182,20 @@ func main() {
 
 	flag := flag.CommandLine
 	flag.BoolVar(&showversion, "version", false, "show the version and exit")
+	flag.BoolVar(&lint, "lint", false, "check the manifest for problems and exit")
 	flag.StringVar(&artifactsdir, "artifacts", ".", "the directory to store artifacts")
 	flag.StringVar(&objectsdir, "objects", ".", "the directory to place objects from")
+	flag.StringVar(&cachedir, "cache", "", "the directory to restore, and save the cache to")
 	flag.StringVar(&manif, "manifest", ".djinn.yml", "the manifest file to use")
+	flag.StringVar(&templatedir, "templates", "", "the directory to include templates from")
 	flag.StringVar(&driverfile, "driver", filepath.Join(cfgdir, "djinn", "driver.conf"), "the driver config to use")
 	flag.StringVar(&stage, "stage", "", "the stage to execute")
+	flag.Var(&jobs, "job", "the job to execute, may be a glob, and given multiple times")
+	flag.BoolVar(&dryrun, "dry-run", false, "print the jobs that would be executed and exit")
+	flag.BoolVar(&approve, "approve", false, "approve manual jobs without prompting")
+	flag.IntVar(&parallelism, "parallelism", 1, "the maximum number of jobs in a stage to execute at once")
+	flag.StringVar(&format, "format", "text", "the format of the output, either text or json")
+	flag.Var(reportpaths, "report", "write a junit, or tap report of the jobs to the given path, as format=path")
 	flag.Parse(os.Args[1:])
 
 	if showversion {
@@ -55,6 +203,51 @@ func main() {
 		return
 	}
 
+	if format != "text" && format != "json" {
+		exiterr(errors.New("unknown format " + format))
+	}
+
+	// Included files are relative to the root of the repository, which is
+	// assumed to be the current directory.
+	resolve := func(_ string, inc manifest.Include) ([]byte, error) {
+		if inc.Template != "" {
+			if templatedir == "" {
+				return nil, errors.New("no templates directory given")
+			}
+			return os.ReadFile(filepath.Join(templatedir, filepath.Base(inc.Template)+".yml"))
+		}
+
+		path, err := inc.CleanPath()
+
+		if err != nil {
+			return nil, err
+		}
+		return os.ReadFile(filepath.FromSlash(path))
+	}
+
+	if lint {
+		b, err := os.ReadFile(manif)
+
+		if err != nil {
+			exiterr(err)
+		}
+
+		errs := manifest.Lint(b, resolve)
+
+		for _, err := range errs {
+			if err.Line > 0 {
+				fmt.Fprintf(os.Stderr, "%s:%s\n", manif, err)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "%s: %s\n", manif, err)
+		}
+
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
 	f, err := os.Open(manif)
 
 	if err != nil {
@@ -63,7 +256,7 @@ func main() {
 
 	defer f.Close()
 
-	m, err := manifest.Decode(f)
+	m, err := manifest.DecodeWith(f, resolve)
 
 	if err != nil {
 		exiterr(err)
@@ -81,24 +274,42 @@ func main() {
 		m.Driver["arch"] = "x86_64"
 	}
 
-	f2, err := os.Open(driverfile)
+	r := runner.Default(m.Objects)
+	r.Env = m.Env
+	r.Artifacts = fs.New(artifactsdir)
+	r.Objects = fs.New(objectsdir)
 
-	if err != nil {
-		exiterr(err)
-	}
+	// The matrix combination of each expanded job, so the artifacts from the
+	// same combination are preferred when placing inputs.
+	matrices := make(map[string]map[string]string)
 
-	defer f2.Close()
+	r.JobInputs = func(j *runner.Job) fs.FS {
+		inputs := fs.New(artifactsdir)
 
-	driverInit, driverCfg, err := config.DecodeDriver(typ, f2.Name(), f2)
+		if vars, ok := matrices[j.Name]; ok {
+			return &matrixInputs{FS: inputs, vars: vars}
+		}
+		return inputs
+	}
+	r.Parallelism = parallelism
+	r.Approve = (&prompter{in: bufio.NewReader(os.Stdin), out: os.Stderr}).approve
 
-	if err != nil {
-		exiterr(err)
+	if approve {
+		r.Approve = func(context.Context, *runner.Job) error { return nil }
 	}
 
-	r := runner.Default(m.Objects)
-	r.Env = m.Env
-	r.Artifacts = fs.New(artifactsdir)
-	r.Objects = fs.New(objectsdir)
+	var cache *runner.Cache
+
+	if cachedir != "" && !m.Cache.IsZero() {
+		cache = &runner.Cache{
+			Key: m.Cache.Key,
+			Vars: map[string]string{
+				"Namespace": m.Namespace,
+			},
+			Paths: m.Cache.Paths,
+			Store: fs.New(cachedir),
+		}
+	}
 
 	setup := runner.Stage{
 		Name: fmt.Sprintf("%s - %v", setupStage, time.Now().Unix()),
@@ -151,28 +362,183 @@ func main() {
 		}
 	}
 
+	combos := m.Matrix.Combinations()
+	counts := make(map[string]int)
+
 	for _, j := range m.Jobs {
+		counts[j.Stage]++
+
+		// Name the job as the runner would, so it can be matched.
+		if j.Name == "" {
+			j.Name = fmt.Sprintf("%s.%d", j.Stage, counts[j.Stage])
+		}
+
 		stage, ok := stagetab[j.Stage]
 
 		if !ok {
 			continue
 		}
 
-		stage.Add(&runner.Job{
-			Writer:    os.Stdout,
-			Name:      j.Name,
-			Commands:  j.Commands,
-			Artifacts: j.Artifacts,
-		})
+		patterns, err := j.Retry.Patterns()
+
+		if err != nil {
+			exiterr(err)
+		}
+
+		retry := runner.Retry{
+			Max:       j.Retry.Max,
+			ExitCodes: j.Retry.ExitCodes,
+			Patterns:  patterns,
+		}
+
+		if len(combos) == 0 {
+			if !jobs.match(j.Stage, j.Name) {
+				continue
+			}
+
+			stage.Add(&runner.Job{
+				Writer:    os.Stdout,
+				Name:      j.Name,
+				Needs:     j.Needs,
+				Env:       j.Env,
+				Workdir:   j.Workdir,
+				Shell:     m.JobShell(j),
+				Quiet:     j.Quiet,
+				Manual:    m.JobManual(j),
+				Commands:  j.Commands,
+				Artifacts: j.Artifacts,
+				Inputs:    j.Inputs,
+				Cache:     cache,
+				Timeout:   time.Duration(j.Timeout),
+				Retry:     retry,
+			})
+			continue
+		}
+
+		for _, vars := range combos {
+			name := manifest.MatrixName(j.Name, vars)
+
+			if !jobs.match(j.Stage, name) {
+				continue
+			}
+
+			needs := make([]string, 0, len(j.Needs))
+
+			for _, need := range j.Needs {
+				needs = append(needs, manifest.MatrixName(need, vars))
+			}
+
+			matrices[name] = vars
+
+			stage.Add(&runner.Job{
+				Writer:    os.Stdout,
+				Name:      name,
+				Needs:     needs,
+				Env:       append(manifest.MatrixEnv(vars), j.Env...),
+				Workdir:   j.Workdir,
+				Shell:     m.JobShell(j),
+				Quiet:     j.Quiet,
+				Manual:    m.JobManual(j),
+				Commands:  j.Commands,
+				Artifacts: manifest.MatrixArtifacts(j.Artifacts, vars),
+				Inputs:    j.Inputs,
+				Cache:     cache,
+				Timeout:   time.Duration(j.Timeout),
+				Retry:     retry,
+			})
+		}
 	}
 
 	for _, name := range m.Stages {
 		r.Add(stagetab[name])
 	}
 
+	// The always, and after failure jobs are only executed when every stage
+	// in the manifest is being executed.
+	if stage == "" {
+		posttab := map[string]*runner.Stage{
+			manifest.AlwaysStage:       {Name: manifest.AlwaysStage, Always: true},
+			manifest.AfterFailureStage: {Name: manifest.AfterFailureStage, AfterFailure: true},
+		}
+
+		postcounts := make(map[string]int)
+
+		for _, j := range m.PostJobs() {
+			postcounts[j.Stage]++
+
+			if j.Name == "" {
+				j.Name = fmt.Sprintf("%s.%d", j.Stage, postcounts[j.Stage])
+			}
+
+			if !jobs.match(j.Stage, j.Name) {
+				continue
+			}
+
+			patterns, err := j.Retry.Patterns()
+
+			if err != nil {
+				exiterr(err)
+			}
+
+			posttab[j.Stage].Add(&runner.Job{
+				Writer:    os.Stdout,
+				Name:      j.Name,
+				Env:       j.Env,
+				Workdir:   j.Workdir,
+				Shell:     m.JobShell(j),
+				Quiet:     j.Quiet,
+				Commands:  j.Commands,
+				Artifacts: j.Artifacts,
+				Inputs:    j.Inputs,
+				Cache:     cache,
+				Timeout:   time.Duration(j.Timeout),
+				Retry: runner.Retry{
+					Max:       j.Retry.Max,
+					ExitCodes: j.Retry.ExitCodes,
+					Patterns:  patterns,
+				},
+			})
+		}
+
+		if len(posttab[manifest.AlwaysStage].Jobs()) > 0 {
+			r.Add(posttab[manifest.AlwaysStage])
+		}
+		if len(posttab[manifest.AfterFailureStage].Jobs()) > 0 {
+			r.Add(posttab[manifest.AfterFailureStage])
+		}
+	}
+
+	if patterns := jobs.unmatched(); len(patterns) > 0 {
+		exiterr(errors.New("no jobs match " + strings.Join(patterns, ", ")))
+	}
+
+	if dryrun {
+		printPlan(os.Stdout, r, m)
+		return
+	}
+
+	f2, err := os.Open(driverfile)
+
+	if err != nil {
+		exiterr(err)
+	}
+
+	defer f2.Close()
+
+	driverInit, driverCfg, err := config.DecodeDriver(typ, f2.Name(), f2)
+
+	if err != nil {
+		exiterr(err)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if m.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(m.Timeout))
+		defer cancel()
+	}
+
 	ch := make(chan os.Signal)
 	signal.Notify(ch, os.Interrupt)
 
@@ -181,7 +547,53 @@ func main() {
 		cancel()
 	}()
 
-	if err := r.Run(ctx, driverInit(os.Stdout, driverCfg.Merge(m.Driver))); err != nil {
+	var w io.Writer = os.Stdout
+
+	// Output is emitted as JSON lines events, where the output of each job is
+	// tagged with the full name of the job.
+	if format == "json" {
+		events := runner.NewEvents(os.Stdout)
+		events.Attach(r)
+
+		for _, st := range r.Stages() {
+			for _, j := range st.Jobs() {
+				j.Writer = events.Writer(j)
+			}
+		}
+
+		w = events.Writer(nil)
+		r.Writer = io.Discard
+	}
+
+	var rep *runner.Report
+
+	if len(reportpaths) > 0 {
+		rep = runner.NewReport()
+
+		for _, st := range r.Stages() {
+			for _, j := range st.Jobs() {
+				j.Writer = rep.Writer(j)
+			}
+		}
+	}
+
+	d := driverInit(w, driverCfg.Merge(m.Driver))
+
+	if v, ok := d.(*docker.Driver); ok {
+		v.Services = dockerServices(m.Services)
+	}
+
+	err = r.Run(ctx, d)
+
+	// Reports are written regardless of whether the run failed, since that
+	// is what they are for.
+	if rep != nil {
+		if err := reportpaths.write(rep, r); err != nil {
+			exiterr(err)
+		}
+	}
+
+	if err != nil {
 		exiterr(err)
 	}
 }
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"djinn-ci.com/manifest"
+	"djinn-ci.com/runner"
+)
+
+// jobFilter is the list of glob patterns given via the -job flag. Each
+// pattern is matched against the name of a job, and against the name of the
+// job prefixed with its stage, for example build/test-*.
+type jobFilter struct {
+	patterns []string
+	matched  map[string]struct{}
+}
+
+func (f *jobFilter) String() string { return strings.Join(f.patterns, ",") }
+
+func (f *jobFilter) Set(s string) error {
+	if _, err := path.Match(s, ""); err != nil {
+		return err
+	}
+
+	f.patterns = append(f.patterns, s)
+	return nil
+}
+
+// match reports whether the job of the given name in the given stage matches
+// the filter. Every job matches an empty filter.
+func (f *jobFilter) match(stage, name string) bool {
+	if len(f.patterns) == 0 {
+		return true
+	}
+
+	if f.matched == nil {
+		f.matched = make(map[string]struct{})
+	}
+
+	ok := false
+
+	for _, pattern := range f.patterns {
+		// Errors are checked when each pattern is set.
+		m1, _ := path.Match(pattern, name)
+		m2, _ := path.Match(pattern, stage+"/"+name)
+
+		if m1 || m2 {
+			f.matched[pattern] = struct{}{}
+			ok = true
+		}
+	}
+	return ok
+}
+
+// unmatched returns the patterns that did not match any job.
+func (f *jobFilter) unmatched() []string {
+	patterns := make([]string, 0)
+
+	for _, pattern := range f.patterns {
+		if _, ok := f.matched[pattern]; !ok {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// expand expands the variables in the given string from the given env. Any
+// variable not in the env is left as is, since it may be set in the
+// environment of the driver.
+func expand(s string, env map[string]string) string {
+	return os.Expand(s, func(name string) string {
+		if val, ok := env[name]; ok {
+			return val
+		}
+		return "$" + name
+	})
+}
+
+func envmap(env ...[]string) map[string]string {
+	m := make(map[string]string)
+
+	for _, vars := range env {
+		for _, v := range vars {
+			if key, val, ok := strings.Cut(v, "="); ok {
+				m[key] = val
+			}
+		}
+	}
+	return m
+}
+
+// printPassthrough prints each source, and destination in the given
+// Passthrough sorted by source, with each line having the given prefix.
+func printPassthrough(w io.Writer, prefix string, pt runner.Passthrough) {
+	srcs := make([]string, 0, len(pt))
+
+	for src := range pt {
+		srcs = append(srcs, src)
+	}
+
+	sort.Strings(srcs)
+
+	for _, src := range srcs {
+		fmt.Fprintf(w, "%s%s => %s\n", prefix, src, pt[src])
+	}
+}
+
+// printPlan prints the stages, and jobs that would be executed by the given
+// Runner, along with the objects to place, the sources to clone, and the
+// services to run from the given manifest. The commands of each job are
+// printed with the variables from the manifest, and the job expanded.
+func printPlan(w io.Writer, r *runner.Runner, m manifest.Manifest) {
+	fmt.Fprintf(w, "driver: %s\n", m.Driver["type"])
+
+	if len(r.Passthrough) > 0 {
+		fmt.Fprintf(w, "\nobjects:\n")
+		printPassthrough(w, "  ", r.Passthrough)
+	}
+
+	if len(m.Sources) > 0 {
+		fmt.Fprintf(w, "\nsources:\n")
+
+		for _, src := range m.Sources {
+			fmt.Fprintf(w, "  %s => %s", src.URL, src.Dir)
+
+			if src.Ref != "" {
+				fmt.Fprintf(w, " (%s)", src.Ref)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	if len(m.Services) > 0 {
+		fmt.Fprintf(w, "\nservices:\n")
+
+		for _, s := range m.Services {
+			fmt.Fprintf(w, "  %s => %s\n", s.Image, s.Name())
+		}
+	}
+
+	for _, st := range r.Stages() {
+		jobs := st.Jobs()
+
+		if len(jobs) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "\nstage %s:\n", st.Name)
+
+		for _, j := range jobs {
+			fmt.Fprintf(w, "  job %s", j.Name)
+
+			if len(j.Needs) > 0 {
+				fmt.Fprintf(w, " (needs %s)", strings.Join(j.Needs, ", "))
+			}
+
+			if j.Manual {
+				fmt.Fprintf(w, " (manual)")
+			}
+			fmt.Fprintln(w)
+
+			env := envmap(r.Env, j.Env)
+
+			for _, cmd := range j.Commands {
+				fmt.Fprintf(w, "    $ %s\n", expand(cmd, env))
+			}
+
+			printPassthrough(w, "    input ", j.Inputs)
+			printPassthrough(w, "    artifact ", j.Artifacts)
+		}
+	}
+}
@@ -21,15 +21,26 @@ type curatorCfg struct {
 
 	Database databaseCfg
 
+	Cache curatorCacheCfg
+
 	Store map[string]storeCfg
 }
 
+type curatorCacheCfg struct {
+	MaxAge time.Duration `config:"max_age"`
+	Limit  int64
+}
+
 type Curator struct {
 	pidfile   string
 	log       *log.Logger
 	interval  time.Duration
 	db        *database.Pool
 	artifacts fs.FS
+
+	caches      fs.FS
+	cacheMaxAge time.Duration
+	cacheLimit  int64
 }
 
 func (c *Curator) Pidfile() string         { return c.pidfile }
@@ -38,6 +49,13 @@ func (c *Curator) Artifacts() fs.FS        { return c.artifacts }
 func (c *Curator) Log() *log.Logger        { return c.log }
 func (c *Curator) Interval() time.Duration { return c.interval }
 
+// Caches returns the store of build caches, the maximum age of an unused
+// cache, and the size limit of the caches for each namespace, or user. The
+// store will be nil if it has not been configured.
+func (c *Curator) Caches() (fs.FS, time.Duration, int64) {
+	return c.caches, c.cacheMaxAge, c.cacheLimit
+}
+
 func DecodeCurator(name string, r io.Reader) (*Curator, error) {
 	var cfg curatorCfg
 
@@ -86,5 +104,16 @@ func DecodeCurator(name string, r io.Reader) (*Curator, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if s, ok := cfg.Store["cache"]; ok {
+		curator.caches, _, err = s.store()
+
+		if err != nil {
+			return nil, err
+		}
+
+		curator.cacheMaxAge = cfg.Cache.MaxAge
+		curator.cacheLimit = cfg.Cache.Limit
+	}
 	return curator, nil
 }
@@ -7,6 +7,7 @@ import (
 	"djinn-ci.com/driver/docker"
 	"djinn-ci.com/driver/os"
 	"djinn-ci.com/driver/qemu"
+	"djinn-ci.com/driver/sandbox"
 	"djinn-ci.com/driver/ssh"
 	"djinn-ci.com/errors"
 
@@ -15,17 +16,19 @@ import (
 
 type driverCfg struct {
 	Driver struct {
-		SSH    ssh.Config
-		QEMU   qemu.Config
-		Docker docker.Config
+		SSH     ssh.Config
+		QEMU    qemu.Config
+		Docker  docker.Config
+		Sandbox sandbox.Config
 	} `config:",nogroup"`
 }
 
 var driverInits = map[string]driver.Init{
-	"docker": docker.Init,
-	"ssh":    ssh.Init,
-	"os":     os.Init,
-	"qemu":   qemu.Init,
+	"docker":  docker.Init,
+	"ssh":     ssh.Init,
+	"os":      os.Init,
+	"qemu":    qemu.Init,
+	"sandbox": sandbox.Init,
 }
 
 func DecodeDriver(driverName, name string, r io.Reader) (driver.Init, driver.Config, error) {
@@ -44,10 +47,11 @@ func DecodeDriver(driverName, name string, r io.Reader) (driver.Init, driver.Con
 	}
 
 	driverCfgs := map[string]driver.Config{
-		"docker": &cfg.Driver.Docker,
-		"ssh":    &cfg.Driver.SSH,
-		"os":     os.Config{},
-		"qemu":   &cfg.Driver.QEMU,
+		"docker":  &cfg.Driver.Docker,
+		"ssh":     &cfg.Driver.SSH,
+		"os":      os.Config{},
+		"qemu":    &cfg.Driver.QEMU,
+		"sandbox": &cfg.Driver.Sandbox,
 	}
 
 	drivercfg, ok := driverCfgs[driverName]
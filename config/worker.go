@@ -24,9 +24,13 @@ import (
 type workerCfg struct {
 	Pidfile string
 
-	Parallelism int
-	Driver      string
-	Timeout     time.Duration
+	Parallelism    int
+	JobParallelism int `config:"job_parallelism"`
+	Driver         string
+	Timeout        time.Duration
+
+	ApprovalTimeout time.Duration `config:"approval_timeout"`
+	PostTimeout     time.Duration `config:"post_timeout"`
 
 	Log map[string]string
 
@@ -47,14 +51,19 @@ type Worker struct {
 
 	log *log.Logger
 
-	driver      string
-	queue       string
-	parallelism int
-	timeout     time.Duration
+	driver         string
+	queue          string
+	parallelism    int
+	jobParallelism int
+	timeout        time.Duration
+
+	approvalTimeout time.Duration
+	postTimeout     time.Duration
 
 	consumer *curlyq.Consumer
 
 	aesgcm *crypto.AESGCM
+	hasher *crypto.Hasher
 
 	db    *database.Pool
 	redis *redis.Client
@@ -67,24 +76,31 @@ type Worker struct {
 
 	objects fs.FS
 
+	caches fs.FS
+
 	providers *provider.Registry
 }
 
-func (w *Worker) Pidfile() string               { return w.pidfile }
-func (w *Worker) Log() *log.Logger              { return w.log }
-func (w *Worker) Driver() string                { return w.driver }
-func (w *Worker) Parallelism() int              { return w.parallelism }
-func (w *Worker) Queue() string                 { return w.queue }
-func (w *Worker) Consumer() *curlyq.Consumer    { return w.consumer }
-func (w *Worker) Timeout() time.Duration        { return w.timeout }
-func (w *Worker) DB() *database.Pool            { return w.db }
-func (w *Worker) Redis() *redis.Client          { return w.redis }
-func (w *Worker) SMTP() (*mail.Client, string)  { return w.smtp, w.smtpadmin }
-func (w *Worker) Artifacts() fs.FS              { return w.artifacts }
-func (w *Worker) ArtifactLimit() int64          { return w.artifactLimit }
-func (w *Worker) Objects() fs.FS                { return w.objects }
-func (w *Worker) AESGCM() *crypto.AESGCM        { return w.aesgcm }
-func (w *Worker) Providers() *provider.Registry { return w.providers }
+func (w *Worker) Pidfile() string                { return w.pidfile }
+func (w *Worker) Log() *log.Logger               { return w.log }
+func (w *Worker) Driver() string                 { return w.driver }
+func (w *Worker) Parallelism() int               { return w.parallelism }
+func (w *Worker) JobParallelism() int            { return w.jobParallelism }
+func (w *Worker) Queue() string                  { return w.queue }
+func (w *Worker) Consumer() *curlyq.Consumer     { return w.consumer }
+func (w *Worker) Timeout() time.Duration         { return w.timeout }
+func (w *Worker) ApprovalTimeout() time.Duration { return w.approvalTimeout }
+func (w *Worker) PostTimeout() time.Duration     { return w.postTimeout }
+func (w *Worker) DB() *database.Pool             { return w.db }
+func (w *Worker) Redis() *redis.Client           { return w.redis }
+func (w *Worker) SMTP() (*mail.Client, string)   { return w.smtp, w.smtpadmin }
+func (w *Worker) Artifacts() fs.FS               { return w.artifacts }
+func (w *Worker) ArtifactLimit() int64           { return w.artifactLimit }
+func (w *Worker) Objects() fs.FS                 { return w.objects }
+func (w *Worker) Caches() fs.FS                  { return w.caches }
+func (w *Worker) AESGCM() *crypto.AESGCM         { return w.aesgcm }
+func (w *Worker) Hasher() *crypto.Hasher         { return w.hasher }
+func (w *Worker) Providers() *provider.Registry  { return w.providers }
 
 func DecodeWorker(name string, r io.Reader) (*Worker, error) {
 	var cfg workerCfg
@@ -119,7 +135,15 @@ func DecodeWorker(name string, r io.Reader) (*Worker, error) {
 		worker.parallelism = int(runtime.NumCPU())
 	}
 
+	worker.jobParallelism = cfg.JobParallelism
+
+	if worker.jobParallelism == 0 {
+		worker.jobParallelism = 1
+	}
+
 	worker.timeout = cfg.Timeout
+	worker.approvalTimeout = cfg.ApprovalTimeout
+	worker.postTimeout = cfg.PostTimeout
 
 	worker.aesgcm, err = cfg.Crypto.aesgcm()
 
@@ -127,6 +151,12 @@ func DecodeWorker(name string, r io.Reader) (*Worker, error) {
 		return nil, err
 	}
 
+	worker.hasher, err = cfg.Crypto.hasher()
+
+	if err != nil {
+		return nil, err
+	}
+
 	worker.db, err = cfg.Database.connect(worker.log)
 
 	if err != nil {
@@ -190,6 +220,16 @@ func DecodeWorker(name string, r io.Reader) (*Worker, error) {
 		}
 	}
 
+	// The cache store is optional, builds are not cached if it is not
+	// configured.
+	if s, ok := cfg.Store["cache"]; ok {
+		worker.caches, _, err = s.store()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for name, p := range cfg.Provider {
 		cli, err := p.client(name, "")
 
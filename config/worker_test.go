@@ -18,6 +18,10 @@ driver "qemu-x86_64"
 
 timeout 30m
 
+approval_timeout 10m
+
+post_timeout 5m
+
 provider github {}
 provider gitlab {}
 
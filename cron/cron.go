@@ -140,6 +140,7 @@ type Cron struct {
 	Name        string
 	Schedule    Schedule
 	Manifest    manifest.Manifest
+	Parameters  manifest.ParamValues
 	PrevRun     database.Null[time.Time]
 	NextRun     time.Time
 	CreatedAt   time.Time
@@ -162,6 +163,7 @@ func (c *Cron) Scan(r *database.Row) error {
 		"name":         &c.Name,
 		"schedule":     &c.Schedule,
 		"manifest":     &c.Manifest,
+		"params":       &c.Parameters,
 		"prev_run":     &c.PrevRun,
 		"next_run":     &c.NextRun,
 		"created_at":   &c.CreatedAt,
@@ -184,6 +186,7 @@ func (c *Cron) Params() database.Params {
 		"name":         database.CreateUpdateParam(c.Name),
 		"schedule":     database.CreateUpdateParam(c.Schedule),
 		"manifest":     database.CreateUpdateParam(c.Manifest),
+		"params":       database.CreateUpdateParam(c.Parameters),
 		"prev_run":     database.UpdateOnlyParam(c.PrevRun),
 		"next_run":     database.CreateUpdateParam(c.NextRun),
 		"created_at":   database.CreateOnlyParam(c.CreatedAt),
@@ -223,6 +226,7 @@ func (c *Cron) MarshalJSON() ([]byte, error) {
 		"name":         c.Name,
 		"schedule":     c.Schedule,
 		"manifest":     c.Manifest.String(),
+		"params":       c.Parameters,
 		"prev_run":     c.PrevRun,
 		"next_run":     c.NextRun,
 		"created_at":   c.CreatedAt,
@@ -300,23 +304,25 @@ func SelectBuild(expr query.Expr, opts ...query.Option) query.Query {
 }
 
 type Params struct {
-	User     *auth.User
-	Name     string
-	Schedule Schedule
-	Manifest manifest.Manifest
+	User       *auth.User
+	Name       string
+	Schedule   Schedule
+	Manifest   manifest.Manifest
+	Parameters manifest.ParamValues
 }
 
 func (s Store) Create(ctx context.Context, p *Params) (*Cron, error) {
 	c := Cron{
-		UserID:    p.User.ID,
-		AuthorID:  p.User.ID,
-		Name:      p.Name,
-		Schedule:  p.Schedule,
-		Manifest:  p.Manifest,
-		NextRun:   p.Schedule.Next(),
-		CreatedAt: time.Now(),
-		User:      p.User,
-		Author:    p.User,
+		UserID:     p.User.ID,
+		AuthorID:   p.User.ID,
+		Name:       p.Name,
+		Schedule:   p.Schedule,
+		Manifest:   p.Manifest,
+		Parameters: p.Parameters,
+		NextRun:    p.Schedule.Next(),
+		CreatedAt:  time.Now(),
+		User:       p.User,
+		Author:     p.User,
 	}
 
 	if p.Manifest.Namespace != "" {
@@ -349,7 +355,7 @@ func (s Store) Create(ctx context.Context, p *Params) (*Cron, error) {
 
 func (s Store) Update(ctx context.Context, c *Cron) error {
 	loaded := c.loaded
-	c.loaded = []string{"name", "schedule", "manifest", "prev_run", "next_run"}
+	c.loaded = []string{"name", "schedule", "manifest", "params", "prev_run", "next_run"}
 
 	if c.Manifest.Namespace != "" {
 		path, err := namespace.ParsePath(c.Manifest.Namespace)
@@ -393,7 +399,8 @@ func (s Store) Invoke(ctx context.Context, c *Cron) (*build.Build, error) {
 		User: &auth.User{
 			ID: c.UserID,
 		},
-		Manifest: c.Manifest,
+		Manifest:   c.Manifest,
+		Parameters: c.Parameters,
 		Trigger: &build.Trigger{
 			Type:    build.Schedule,
 			Comment: c.Name + ": Scheduled build, next run " + c.NextRun.Format("Mon Jan 2 15:04:05 2006"),
@@ -21,6 +21,7 @@ type Form struct {
 	Name     string
 	Schedule cron.Schedule
 	Manifest manifest.Manifest
+	Params   manifest.ParamValues
 }
 
 func (f *Form) Fields() map[string]string {
@@ -28,6 +29,7 @@ func (f *Form) Fields() map[string]string {
 		"name":     f.Name,
 		"schedule": f.Schedule.String(),
 		"manifest": f.Manifest.String(),
+		"params":   f.Params.String(),
 	}
 }
 
@@ -50,6 +52,9 @@ func (f *Form) Validate(ctx context.Context) error {
 		if f.Manifest.String() == "" {
 			f.Manifest = f.Cron.Manifest
 		}
+		if f.Params == nil {
+			f.Params = f.Cron.Parameters
+		}
 	}
 
 	v.Add("name", f.Name, webutil.FieldRequired)
@@ -62,10 +67,28 @@ func (f *Form) Validate(ctx context.Context) error {
 		}
 		return namespace.CanAccess(f.Pool, f.User)(ctx, p)
 	})
+	templates := &namespace.TemplateStore{
+		Store: namespace.NewTemplateStore(f.Pool),
+	}
+
+	resolve := templates.Resolver(ctx, f.User)
+
+	// The manifest is linted as it was given so any problems are reported
+	// with their position, and is stored with its includes merged in.
+	raw := f.Manifest
+
+	if m, err := f.Manifest.Resolve(resolve); err == nil {
+		f.Manifest = m
+	}
+
 	v.Add("manifest", f.Manifest, webutil.FieldRequired)
-	v.Add("manifest", f.Manifest, func(ctx context.Context, val any) error {
+	v.Add("manifest", raw, func(ctx context.Context, val any) error {
 		m := val.(manifest.Manifest)
-		return m.Validate()
+		return m.Lint(resolve).Err()
+	})
+	v.Add("params", f.Params, func(_ context.Context, val any) error {
+		_, err := f.Manifest.ParamEnv(val.(manifest.ParamValues))
+		return err
 	})
 
 	var pathError error
@@ -89,10 +89,11 @@ func (h *Handler) Store(u *auth.User, r *http.Request) (*cron.Cron, *Form, error
 	ctx := r.Context()
 
 	c, err := h.Crons.Create(ctx, &cron.Params{
-		User:     u,
-		Name:     f.Name,
-		Schedule: f.Schedule,
-		Manifest: f.Manifest,
+		User:       u,
+		Name:       f.Name,
+		Schedule:   f.Schedule,
+		Manifest:   f.Manifest,
+		Parameters: f.Params,
 	})
 
 	if err != nil {
@@ -123,6 +124,7 @@ func (h *Handler) Update(u *auth.User, c *cron.Cron, r *http.Request) (*cron.Cro
 	c.Name = f.Name
 	c.Schedule = f.Schedule
 	c.Manifest = f.Manifest
+	c.Parameters = f.Params
 
 	if err := h.Crons.Update(ctx, c); err != nil {
 		return nil, &f, errors.Err(err)
@@ -121,6 +121,7 @@ func (h UI) Edit(u *auth.User, c *cron.Cron, w http.ResponseWriter, r *http.Requ
 
 	f.Fields["name"] = c.Name
 	f.Fields["manifest"] = c.Manifest.String()
+	f.Fields["params"] = c.Parameters.String()
 	f.Fields["schedule"] = c.Schedule.String()
 
 	tmpl := template.NewDashboard(u, sess, r)
@@ -0,0 +1,234 @@
+package driver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"djinn-ci.com/errors"
+	"djinn-ci.com/runner"
+
+	"github.com/andrewpillar/fs"
+)
+
+// ArtifactFile is a file collected as an artifact. Source is the path to the
+// file in the environment the job was executed in.
+type ArtifactFile struct {
+	fs.File
+
+	Source string
+}
+
+// WalkFunc is called for each regular file found when walking the environment
+// of a driver, with the path to the file, information about the file, and a
+// reader for its contents.
+type WalkFunc func(name string, info fs.FileInfo, r io.Reader) error
+
+// Walker walks the files in the environment of a driver starting from the
+// given root, calling fn for each regular file. If the root is a regular file
+// then fn is only called for that file.
+type Walker func(root string, fn WalkFunc) error
+
+// archive packages collected files into a temporary archive file.
+type archive struct {
+	f   *os.File
+	tw  *tar.Writer
+	gz  *gzip.Writer
+	zw  *zip.Writer
+	len int
+}
+
+func newArchive(format string) (*archive, error) {
+	f, err := os.CreateTemp("", "djinn-artifact-*")
+
+	if err != nil {
+		return nil, err
+	}
+
+	a := archive{f: f}
+
+	switch format {
+	case "tar.gz":
+		a.gz = gzip.NewWriter(f)
+		a.tw = tar.NewWriter(a.gz)
+	case "zip":
+		a.zw = zip.NewWriter(f)
+	}
+	return &a, nil
+}
+
+func (a *archive) add(name string, info fs.FileInfo, r io.Reader) error {
+	a.len++
+
+	if a.zw != nil {
+		hdr, err := zip.FileInfoHeader(info)
+
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = name
+		hdr.Method = zip.Deflate
+
+		w, err := a.zw.CreateHeader(hdr)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(w, r)
+		return err
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+
+	if err != nil {
+		return err
+	}
+
+	hdr.Name = name
+
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(a.tw, r)
+	return err
+}
+
+// close finishes writing the archive, and rewinds it for reading.
+func (a *archive) close() error {
+	if a.zw != nil {
+		if err := a.zw.Close(); err != nil {
+			return err
+		}
+	} else {
+		if err := a.tw.Close(); err != nil {
+			return err
+		}
+		if err := a.gz.Close(); err != nil {
+			return err
+		}
+	}
+
+	_, err := a.f.Seek(0, io.SeekStart)
+	return err
+}
+
+func (a *archive) remove() {
+	a.f.Close()
+	os.Remove(a.f.Name())
+}
+
+// CollectArtifact collects the files of the given artifact into the given
+// filestore, using the given Walker to find the files. A single file is
+// collected as the artifact. The files of a directory, or pattern are either
+// collected individually, or packaged into an archive, depending on the name
+// of the artifact.
+func CollectArtifact(w io.Writer, artifacts fs.FS, a runner.Artifact, walk Walker) error {
+	root := a.Root()
+	format := a.Format()
+
+	var arc *archive
+
+	defer func() {
+		if arc != nil {
+			arc.remove()
+		}
+	}()
+
+	n := 0
+
+	err := walk(root, func(name string, info fs.FileInfo, r io.Reader) error {
+		if !a.Match(name) {
+			return nil
+		}
+
+		n++
+
+		// The source is a single file, so collect it as it is.
+		if name == root && !a.IsPattern() {
+			fmt.Fprintln(w, "Collecting artifact", name, "=>", a.Name)
+			return putArtifact(artifacts, name, a.Name, r)
+		}
+
+		if format != "" {
+			if arc == nil {
+				var err error
+
+				if arc, err = newArchive(format); err != nil {
+					return err
+				}
+			}
+
+			rel := strings.TrimPrefix(strings.TrimPrefix(name, root), "/")
+
+			if root == "." {
+				rel = name
+			}
+
+			fmt.Fprintln(w, "Packaging artifact", name, "=>", a.Name)
+			return arc.add(path.Join(path.Base(root), rel), info, r)
+		}
+
+		dst := a.FileName(name)
+
+		fmt.Fprintln(w, "Collecting artifact", name, "=>", dst)
+
+		if err := putArtifact(artifacts, name, dst, r); err != nil {
+			fmt.Fprintln(w, "artifact error:", errors.Cause(err))
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return errors.New("no files found for " + a.Source)
+	}
+
+	if arc != nil {
+		if err := arc.close(); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w, "Collecting artifact", a.Source, "=>", a.Name, "-", arc.len, "file(s)")
+		return putArtifact(artifacts, a.Source, a.Name, arc.f)
+	}
+	return nil
+}
+
+func putArtifact(artifacts fs.FS, src, dst string, r io.Reader) error {
+	f, err := fs.ReadFile(dst, r)
+
+	if err != nil {
+		return err
+	}
+
+	defer fs.Cleanup(f)
+	defer f.Close()
+
+	_, err = artifacts.Put(&ArtifactFile{
+		File:   fs.Rename(f, dst),
+		Source: src,
+	})
+	return err
+}
+
+// CollectArtifacts collects each artifact of the given job into the given
+// filestore, using the given Walker to find the files. Any errors are written
+// to the job's writer.
+func CollectArtifacts(j *runner.Job, artifacts fs.FS, walk Walker) {
+	for _, a := range runner.Artifacts(j.Artifacts) {
+		if err := CollectArtifact(j.Writer, artifacts, a, walk); err != nil {
+			fmt.Fprintln(j.Writer, "artifact error:", errors.Cause(err))
+		}
+	}
+}
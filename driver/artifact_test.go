@@ -0,0 +1,152 @@
+package driver
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"djinn-ci.com/runner"
+
+	"github.com/andrewpillar/fs"
+)
+
+type fileInfo struct {
+	name string
+	size int64
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) Mode() fs.FileMode  { return 0644 }
+func (i fileInfo) ModTime() time.Time { return time.Time{} }
+func (i fileInfo) IsDir() bool        { return false }
+func (i fileInfo) Sys() any           { return nil }
+
+// memWalker returns a Walker over the given files, mapping the path of each
+// file to its contents.
+func memWalker(files map[string]string) Walker {
+	return func(root string, fn WalkFunc) error {
+		names := make([]string, 0, len(files))
+
+		for name := range files {
+			if name == root || root == "." || strings.HasPrefix(name, root+"/") {
+				names = append(names, name)
+			}
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			info := fileInfo{
+				name: filepath.Base(name),
+				size: int64(len(files[name])),
+			}
+
+			if err := fn(name, info, strings.NewReader(files[name])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func readDir(t *testing.T, dir string) []string {
+	ents, err := os.ReadDir(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(ents))
+
+	for _, ent := range ents {
+		names = append(names, ent.Name())
+	}
+	return names
+}
+
+func Test_CollectArtifact(t *testing.T) {
+	walk := memWalker(map[string]string{
+		"bin/djinn":                     "djinn",
+		"dist/djinn-1.0.tar.gz":         "tarball",
+		"dist/linux/djinn-1.0.tar.gz":   "linux tarball",
+		"dist/linux/djinn-1.0.sha256":   "checksum",
+		"docs/index.md":                 "index",
+		"docs/guide/getting-started.md": "getting started",
+	})
+
+	tests := []struct {
+		artifact runner.Artifact
+		expected []string
+	}{
+		{runner.Artifact{Source: "bin/djinn", Name: "djinn-bin"}, []string{"djinn-bin"}},
+		{runner.Artifact{Source: "dist/**/*.tar.gz", Name: "*.tar.gz"}, []string{"djinn-1.0.tar.gz"}},
+		{runner.Artifact{Source: "dist/**/*.sha256", Name: "release-*"}, []string{"release-djinn-1.0.sha256"}},
+		{runner.Artifact{Source: "docs/", Name: "docs.tar.gz"}, []string{"docs.tar.gz"}},
+	}
+
+	for i, test := range tests {
+		dir := t.TempDir()
+
+		if err := CollectArtifact(io.Discard, fs.New(dir), test.artifact, walk); err != nil {
+			t.Fatalf("tests[%d] - %s\n", i, err)
+		}
+
+		names := readDir(t, dir)
+
+		if strings.Join(names, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("tests[%d] - unexpected artifacts, expected=%v, got=%v\n", i, test.expected, names)
+		}
+	}
+
+	dir := t.TempDir()
+
+	if err := CollectArtifact(io.Discard, fs.New(dir), runner.Artifact{Source: "docs", Name: "docs.tar.gz"}, walk); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "docs.tar.gz"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := tar.NewReader(gz)
+
+	var names []string
+
+	for {
+		hdr, err := tr.Next()
+
+		if err != nil {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+
+	expected := "docs/guide/getting-started.md,docs/index.md"
+
+	if strings.Join(names, ",") != expected {
+		t.Errorf("unexpected archive contents, expected=%q, got=%q\n", expected, strings.Join(names, ","))
+	}
+
+	if err := CollectArtifact(io.Discard, fs.New(dir), runner.Artifact{Source: "*.exe", Name: "*.exe"}, walk); err == nil {
+		t.Errorf("expected error for artifact with no files\n")
+	}
+}
@@ -0,0 +1,148 @@
+package driver
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"djinn-ci.com/errors"
+	"djinn-ci.com/runner"
+
+	"github.com/andrewpillar/fs"
+)
+
+// CacheEnv is the environment of a driver that the cache of a job is restored
+// into, and saved from.
+type CacheEnv struct {
+	// Read reads the file with the given name, this is used for resolving
+	// the checksums in the cache key.
+	Read func(name string) ([]byte, error)
+
+	// Extract extracts the given tar archive into the directory jobs are
+	// executed in.
+	Extract func(r io.Reader) error
+
+	// Walk walks the paths of the cache when saving it.
+	Walk Walker
+}
+
+// RestoreCache resolves the key of the given cache, and restores the cache
+// with that key into the environment if it exists. The resolved key is
+// returned so the cache can be saved under the same key once the job has
+// passed.
+func RestoreCache(w io.Writer, c *runner.Cache, env CacheEnv) (string, error) {
+	key, err := c.ResolveKey(env.Read)
+
+	if err != nil {
+		return "", err
+	}
+
+	f, err := c.Store.Open(key)
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintln(w, "No cache found for key", key)
+			return key, nil
+		}
+		return key, err
+	}
+
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+
+	if err != nil {
+		return key, err
+	}
+
+	defer gz.Close()
+
+	if err := env.Extract(gz); err != nil {
+		return key, err
+	}
+
+	fmt.Fprintln(w, "Restored cache", key)
+	return key, nil
+}
+
+// SaveCache saves the paths of the given cache from the environment under the
+// given key. Paths that do not exist in the environment are skipped.
+func SaveCache(w io.Writer, c *runner.Cache, key string, env CacheEnv) error {
+	arc, err := newArchive("tar.gz")
+
+	if err != nil {
+		return err
+	}
+
+	defer arc.remove()
+
+	for _, p := range c.Paths {
+		p = path.Clean(p)
+
+		err := env.Walk(p, func(name string, info fs.FileInfo, r io.Reader) error {
+			return arc.add(name, info, r)
+		})
+
+		if err != nil {
+			fmt.Fprintln(w, "cache error:", errors.Cause(err))
+		}
+	}
+
+	if err := arc.close(); err != nil {
+		return err
+	}
+
+	f, err := fs.ReadFile(key, arc.f)
+
+	if err != nil {
+		return err
+	}
+
+	defer fs.Cleanup(f)
+	defer f.Close()
+
+	if _, err := c.Store.Put(f); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "Saved cache", key, "-", arc.len, "file(s)")
+	return nil
+}
+
+// ExtractTar extracts each regular file in the given tar archive using the
+// given function. An error is returned if the archive has a file outside of
+// the directory it is being extracted to.
+func ExtractTar(r io.Reader, extract func(hdr *tar.Header, r io.Reader) error) error {
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(hdr.Name)
+
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return errors.New("invalid file in archive " + hdr.Name)
+		}
+
+		hdr.Name = name
+
+		if err := extract(hdr, tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,10 +5,18 @@ package docker
 
 import (
 	"archive/tar"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"path"
+	"strconv"
 	"strings"
+	"sync"
 
 	"djinn-ci.com/driver"
 	"djinn-ci.com/errors"
@@ -19,6 +27,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
@@ -31,6 +40,41 @@ type Config struct {
 	Version   string // The version of the Docker API.
 	Image     string // The container image to use.
 	Workspace string // The workspace in the container to mount the volume to.
+
+	// Dockerfile is the path to a Dockerfile in the workspace from which the
+	// image for the jobs is built.
+	Dockerfile string
+
+	// Context is the path to the directory in the workspace to use as the
+	// build context for the Dockerfile. If empty then the directory of the
+	// Dockerfile is used.
+	Context string
+
+	// Pull is the policy for pulling images, either always, if-not-present,
+	// or never. If empty then images are always pulled. When configured for
+	// the worker this is the most lenient policy a manifest can request, so
+	// images pulled with the credentials of one build cannot be used by
+	// another build without them.
+	Pull string
+
+	// The resource limits, and security options for each container. When
+	// configured for the worker these are the maximums that a manifest can
+	// request, and are used if the manifest does not request anything. Zero
+	// means no limit.
+	CPUs       float64  // The number of CPUs a container can use.
+	Memory     int64    // The amount of memory in bytes a container can use.
+	PidsLimit  int64    `config:"pids_limit"` // The number of processes a container can run.
+	Network    string   // The network mode of a container, either none or bridge.
+	Privileged bool     // Whether containers can be run as privileged.
+	CapAdd     []string `config:"cap_add"` // The capabilities that can be added to a container.
+
+	// auths are the registry credentials from a manifest, see
+	// Driver.RegistryAuth.
+	auths map[string]string
+
+	// err is any error that occurred when merging in the driver configuration
+	// from a manifest, this is returned when the driver is created.
+	err error
 }
 
 // Driver provides an implementation of the runner.Dirver interface for running
@@ -38,11 +82,20 @@ type Config struct {
 type Driver struct {
 	io.Writer
 
-	client     *client.Client
-	volume     types.Volume
-	env        []string
+	client  *client.Client
+	volume  types.Volume
+	env     []string
+	objects fs.FS
+
+	mu         sync.Mutex
 	containers []string
 
+	buildMu sync.Mutex
+	built   string
+	missing bool // missing is whether the Dockerfile was last seen to be missing.
+
+	network string
+
 	Host    string // Host is the host of the Docker registry to use.
 	Version string // Version is the Docker API version to use.
 	Image   string // Image is the name of the image to use in the Docker container.
@@ -50,10 +103,42 @@ type Driver struct {
 	// Workspace specifies location on the Driver container to mount a volume
 	// to so state can be persisted.
 	Workspace string
+
+	// Dockerfile and Context specify the Dockerfile in the workspace to build
+	// the image for the jobs from, and the directory to build it in. Until
+	// the Dockerfile exists in the workspace, jobs are executed in Image, so
+	// the sources containing it can be cloned.
+	Dockerfile string
+	Context    string
+
+	// Pull is the policy for pulling images.
+	Pull string
+
+	// RegistryAuth maps the host of each registry to the name of the
+	// variable containing the credentials for it, in the format of
+	// username:password. If the name is prefixed with key: then the
+	// credentials are read from the key of that name instead. Credentials
+	// are only sent when pulling images from their registry.
+	RegistryAuth map[string]string
+
+	// Services are the containers to run alongside the jobs. These are
+	// started on a network created for the driver, which every job container
+	// is attached to.
+	Services []Service
+
+	CPUs       float64  // CPUs is the number of CPUs a container can use.
+	Memory     int64    // Memory is the amount of memory in bytes a container can use.
+	PidsLimit  int64    // PidsLimit is the number of processes a container can run.
+	Network    string   // Network is the network mode of each container.
+	Privileged bool     // Privileged is whether each container is privileged.
+	CapAdd     []string // CapAdd is the capabilities added to each container.
+
+	err error
 }
 
 var (
 	_ runner.Driver = (*Driver)(nil)
+	_ runner.Placer = (*Driver)(nil)
 	_ driver.Config = (*Config)(nil)
 )
 
@@ -79,20 +164,178 @@ func (cfg *Config) Apply(d runner.Driver) {
 	v.Version = cfg.Version
 	v.Image = cfg.Image
 	v.Workspace = cfg.Workspace
+	v.Dockerfile = cfg.Dockerfile
+	v.Context = cfg.Context
+	v.Pull = cfg.Pull
+	v.RegistryAuth = cfg.auths
+	v.CPUs = cfg.CPUs
+	v.Memory = cfg.Memory
+	v.PidsLimit = cfg.PidsLimit
+	v.Network = cfg.Network
+	v.Privileged = cfg.Privileged
+	v.CapAdd = cfg.CapAdd
+	v.err = cfg.err
+}
+
+var sizes = map[string]int64{
+	"B":  1,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+	"TB": 1 << 40,
+}
+
+// parseSize parses the given size in bytes, this can have a unit suffix of
+// either B, KB, MB, GB, or TB.
+func parseSize(s string) (int64, error) {
+	num := strings.TrimRight(s, "BKMGT")
+
+	siz := int64(1)
+
+	if unit := s[len(num):]; unit != "" {
+		var ok bool
+
+		if siz, ok = sizes[unit]; !ok {
+			return 0, errors.New("unrecognized size " + unit)
+		}
+	}
+
+	i, err := strconv.ParseInt(num, 10, 64)
+
+	if err != nil || i < 0 {
+		return 0, errors.New("invalid size " + s)
+	}
+	return i * siz, nil
+}
+
+// capName normalizes the given capability name, so CAP_NET_ADMIN, and
+// net_admin are the same.
+func capName(s string) string {
+	return strings.TrimPrefix(strings.ToUpper(s), "CAP_")
+}
+
+// mergeLimits merges in the resource limits, and security options requested
+// in the given manifest driver configuration. An error is returned if any of
+// the requests are invalid, or exceed what is configured.
+func (cfg *Config) mergeLimits(m map[string]string) error {
+	if s := m["cpus"]; s != "" {
+		cpus, err := strconv.ParseFloat(s, 64)
+
+		if err != nil || cpus <= 0 {
+			return errors.New("invalid cpus " + s)
+		}
+
+		if cfg.CPUs > 0 && cpus > cfg.CPUs {
+			return errors.New("cpus " + s + " exceeds the maximum of " + strconv.FormatFloat(cfg.CPUs, 'f', -1, 64))
+		}
+		cfg.CPUs = cpus
+	}
+
+	if s := m["memory"]; s != "" {
+		memory, err := parseSize(s)
+
+		if err != nil {
+			return errors.Err(err)
+		}
+
+		if cfg.Memory > 0 && memory > cfg.Memory {
+			return errors.New("memory " + s + " exceeds the maximum of " + strconv.FormatInt(cfg.Memory, 10) + " bytes")
+		}
+		cfg.Memory = memory
+	}
+
+	if s := m["pids_limit"]; s != "" {
+		pids, err := strconv.ParseInt(s, 10, 64)
+
+		if err != nil || pids <= 0 {
+			return errors.New("invalid pids_limit " + s)
+		}
+
+		if cfg.PidsLimit > 0 && pids > cfg.PidsLimit {
+			return errors.New("pids_limit " + s + " exceeds the maximum of " + strconv.FormatInt(cfg.PidsLimit, 10))
+		}
+		cfg.PidsLimit = pids
+	}
+
+	if s := m["network"]; s != "" {
+		switch s {
+		case "none":
+		case "bridge":
+			if cfg.Network == "none" {
+				return errors.New("network bridge is not allowed")
+			}
+		default:
+			return errors.New("invalid network " + s + ", expected none or bridge")
+		}
+		cfg.Network = s
+	}
+
+	privileged := false
+
+	if s := m["privileged"]; s != "" {
+		var err error
+
+		privileged, err = strconv.ParseBool(s)
+
+		if err != nil {
+			return errors.New("invalid privileged " + s)
+		}
+
+		if privileged && !cfg.Privileged {
+			return errors.New("privileged containers are not allowed")
+		}
+	}
+
+	// Privileged, and the added capabilities, are what is allowed by the
+	// worker, so only grant them if requested.
+	cfg.Privileged = privileged
+
+	allowed := make(map[string]struct{}, len(cfg.CapAdd))
+
+	for _, name := range cfg.CapAdd {
+		allowed[capName(name)] = struct{}{}
+	}
+
+	cfg.CapAdd = nil
+
+	for _, name := range strings.FieldsFunc(m["cap_add"], func(r rune) bool { return r == ',' || r == ' ' }) {
+		name = capName(name)
+
+		if _, ok := allowed[name]; !ok {
+			return errors.New("capability " + name + " is not allowed")
+		}
+		cfg.CapAdd = append(cfg.CapAdd, name)
+	}
+	return nil
 }
 
 func (cfg *Config) Merge(m map[string]string) driver.Config {
 	cfg1 := (*cfg)
 	cfg1.Image = m["image"]
 	cfg1.Workspace = m["workspace"]
+	cfg1.Dockerfile = m["dockerfile"]
+	cfg1.Context = m["context"]
+
+	err := cfg1.mergeRegistry(m)
 
+	if err == nil {
+		err = cfg1.mergeLimits(m)
+	}
+
+	if err != nil {
+		cfg1.err = &driver.Error{
+			Driver: "docker",
+			Err:    err,
+		}
+	}
 	return &cfg1
 }
 
 // Create will create a volume, and pull down the configured image. The client
 // to the Driver daemon is derived from the environment. Once the client has
 // been established, the image volume is created, and the image is pulled down
-// from the repository.
+// from the repository according to the pull policy. Any services are then
+// started, and waited on until they are healthy.
 func (d *Driver) Create(c context.Context, env []string, pt runner.Passthrough, objects fs.FS) error {
 	var err error
 
@@ -100,9 +343,24 @@ func (d *Driver) Create(c context.Context, env []string, pt runner.Passthrough,
 		return errors.New("cannot create driver with nil io.Writer")
 	}
 
+	if d.err != nil {
+		return d.err
+	}
+
 	fmt.Fprintln(d.Writer, "Running with driver docker...")
 	fmt.Fprintf(d.Writer, "Using docker API version %s...\n", d.Version)
 
+	switch d.Pull {
+	case "", PullAlways, PullIfNotPresent, PullNever:
+	default:
+		return errors.New("invalid pull policy " + d.Pull)
+	}
+
+	// The environment, and objects are needed for looking up the registry
+	// credentials when pulling images.
+	d.env = env
+	d.objects = objects
+
 	d.client, err = client.NewClientWithOpts(
 		client.WithHost(d.Host),
 		client.WithVersion(d.Version),
@@ -123,18 +381,10 @@ func (d *Driver) Create(c context.Context, env []string, pt runner.Passthrough,
 			return
 		}
 
-		fmt.Fprintf(d.Writer, "Pulling image %s...\n", d.Image)
-
-		rc, err := d.client.ImagePull(c, d.Image, types.ImagePullOptions{})
-
-		if err != nil {
+		if err := d.pull(c, d.Image); err != nil {
 			errs <- err
 			return
 		}
-
-		defer rc.Close()
-
-		io.Copy(io.Discard, rc)
 		done <- struct{}{}
 	}()
 
@@ -150,86 +400,512 @@ func (d *Driver) Create(c context.Context, env []string, pt runner.Passthrough,
 	image, _, err := d.client.ImageInspectWithRaw(c, d.Image)
 
 	if err != nil {
+		if client.IsErrNotFound(err) && d.Pull == PullNever {
+			return errors.New("image " + d.Image + " does not exist, and pull policy is never")
+		}
 		return err
 	}
 
 	fmt.Fprintf(d.Writer, "Using Driver image %s - %s...\n\n", d.Image, image.ID)
 
-	d.env = env
+	if err := d.startServices(c); err != nil {
+		return err
+	}
 	return d.placeObjects(pt, objects)
 }
 
-func (d *Driver) collectArtifact(ctx context.Context, w io.Writer, artifacts fs.FS, id, src, dst string) error {
-	fmt.Fprintf(w, "Collecting artifact %s => %s\n", src, dst)
+// walker returns a driver.Walker for walking the files in the workspace of
+// the given container. The files are copied out of the container as a tar
+// archive.
+func (d *Driver) walker(ctx context.Context, id string) driver.Walker {
+	return func(root string, fn driver.WalkFunc) error {
+		rc, _, err := d.client.CopyFromContainer(ctx, id, path.Join(d.Workspace, root))
 
-	rc, _, err := d.client.CopyFromContainer(ctx, id, d.Workspace+"/"+src)
+		if err != nil {
+			return err
+		}
+
+		defer rc.Close()
+
+		tr := tar.NewReader(rc)
+
+		for {
+			hdr, err := tr.Next()
+
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					return err
+				}
+				break
+			}
+
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+
+			// The first element of each name in the archive is the base of
+			// the path that was copied, so swap it out for the root.
+			name := root
+
+			if i := strings.Index(hdr.Name, "/"); i >= 0 {
+				name = path.Join(root, hdr.Name[i+1:])
+			}
+
+			if err := fn(name, hdr.FileInfo(), tr); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// cacheEnv returns the driver.CacheEnv for restoring, and saving caches in
+// the workspace of the given container.
+func (d *Driver) cacheEnv(ctx context.Context, id string) driver.CacheEnv {
+	return driver.CacheEnv{
+		Read: func(name string) ([]byte, error) {
+			rc, _, err := d.client.CopyFromContainer(ctx, id, path.Join(d.Workspace, name))
+
+			if err != nil {
+				return nil, err
+			}
+
+			defer rc.Close()
+
+			tr := tar.NewReader(rc)
+
+			for {
+				hdr, err := tr.Next()
+
+				if err != nil {
+					return nil, err
+				}
+
+				if hdr.Typeflag == tar.TypeReg {
+					return io.ReadAll(tr)
+				}
+			}
+		},
+		Extract: func(r io.Reader) error {
+			pr, pw := io.Pipe()
+			defer pr.Close()
+
+			tw := tar.NewWriter(pw)
+
+			// Rewrite the archive as it is copied so files outside of the
+			// workspace are rejected.
+			go func() {
+				err := driver.ExtractTar(r, func(hdr *tar.Header, r io.Reader) error {
+					if err := tw.WriteHeader(hdr); err != nil {
+						return err
+					}
+
+					_, err := io.Copy(tw, r)
+					return err
+				})
+
+				if err == nil {
+					err = tw.Close()
+				}
+				pw.CloseWithError(err)
+			}()
+
+			return d.client.CopyToContainer(ctx, id, d.Workspace, pr, types.CopyToContainerOptions{})
+		},
+		Walk: d.walker(ctx, id),
+	}
+}
+
+// copyLogs copies whatever logs have been produced by the given container to
+// the given io.Writer. This is used for containers that were killed.
+func (d *Driver) copyLogs(w io.Writer, id string, opts types.ContainerLogsOptions) {
+	opts.Follow = false
+
+	rc, err := d.client.ContainerLogs(context.Background(), id, opts)
 
 	if err != nil {
-		return err
+		return
 	}
 
 	defer rc.Close()
+	stdcopy.StdCopy(w, io.Discard, rc)
+}
+
+// addContainer records the given container ID so it can be removed when the
+// driver is destroyed. This is safe to call from concurrently executing jobs.
+func (d *Driver) addContainer(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.containers = append(d.containers, id)
+}
+
+// copyContext copies the build context from the given tar stream into the
+// given writer. The first element of each name in the stream is the base of
+// the context directory, so this is stripped to make the names relative to the
+// context. The returned hash is of the given Dockerfile name, and the names,
+// modes, and contents of the files in the context. The content of the
+// Dockerfile is returned too, if it is in the context.
+func copyContext(w io.Writer, r io.Reader, dockerfile string) (string, []byte, error) {
+	h := sha256.New()
+	fmt.Fprintln(h, dockerfile)
 
-	tr := tar.NewReader(rc)
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+
+	var content bytes.Buffer
 
 	for {
-		header, err := tr.Next()
+		hdr, err := tr.Next()
 
 		if err != nil {
 			if !errors.Is(err, io.EOF) {
-				return err
+				return "", nil, err
 			}
 			break
 		}
 
-		switch header.Typeflag {
-		case tar.TypeDir:
-			break
-		case tar.TypeReg:
-			f, err := fs.ReadFile(dst, tr)
+		_, name, ok := strings.Cut(hdr.Name, "/")
 
-			if err != nil {
-				return err
-			}
+		if !ok || name == "" {
+			continue
+		}
 
-			defer f.Close()
+		hdr.Name = name
 
-			if _, err := artifacts.Put(f); err != nil {
-				return err
+		fmt.Fprintln(h, hdr.Name, hdr.Typeflag, hdr.Mode, hdr.Linkname)
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return "", nil, err
+		}
+
+		dst := io.MultiWriter(tw, h)
+
+		if hdr.Name == dockerfile {
+			dst = io.MultiWriter(dst, &content)
+		}
+
+		if _, err := io.Copy(dst, tr); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", nil, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), content.Bytes(), nil
+}
+
+// baseImages returns the images the stages of the given Dockerfile are built
+// from. Stages built from an earlier stage, from scratch, or from an image that
+// depends on a build argument are skipped.
+func baseImages(dockerfile []byte) []string {
+	var images []string
+
+	stages := map[string]struct{}{
+		"scratch": {},
+	}
+
+	for _, line := range strings.Split(string(dockerfile), "\n") {
+		fields := strings.Fields(line)
+
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+
+		fields = fields[1:]
+
+		for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+			fields = fields[1:]
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		image := fields[0]
+
+		// Images that depend on build arguments cannot be resolved until
+		// built, the Dockerfile itself is already hashed.
+		_, ok := stages[strings.ToLower(image)]
+
+		if !ok && !strings.Contains(image, "$") {
+			images = append(images, image)
+		}
+
+		if len(fields) == 3 && strings.EqualFold(fields[1], "AS") {
+			stages[strings.ToLower(fields[2])] = struct{}{}
+		}
+	}
+	return images
+}
+
+// buildContext copies the build context out of the workspace of the given
+// container, into a tar archive in a temporary file. The returned hash is of
+// the build context, and of the ID of each image the Dockerfile is built from,
+// so a Dockerfile built from an image that has since changed is rebuilt. Each
+// of these images is pulled first in line with the pull policy.
+func (d *Driver) buildContext(ctx context.Context, id, dir, dockerfile string) (*os.File, string, error) {
+	rc, _, err := d.client.CopyFromContainer(ctx, id, path.Join(d.Workspace, dir))
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer rc.Close()
+
+	f, err := os.CreateTemp("", "djinn-docker-context-")
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	os.Remove(f.Name())
+
+	hash, content, err := copyContext(f, rc, dockerfile)
+
+	if err != nil {
+		f.Close()
+		return nil, "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintln(h, hash)
+
+	for _, image := range baseImages(content) {
+		if err := d.pull(ctx, image); err != nil {
+			f.Close()
+			return nil, "", err
+		}
+
+		info, _, err := d.client.ImageInspectWithRaw(ctx, image)
+
+		if err != nil {
+			f.Close()
+			return nil, "", err
+		}
+		fmt.Fprintln(h, image, info.ID)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, "", err
+	}
+	return f, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// statDockerfile records whether the Dockerfile is missing from the workspace
+// mounted in the given container. This must be called with buildMu held.
+func (d *Driver) statDockerfile(ctx context.Context, id string) error {
+	_, err := d.client.ContainerStatPath(ctx, id, path.Join(d.Workspace, d.Dockerfile))
+
+	d.missing = client.IsErrNotFound(err)
+
+	if d.missing {
+		return nil
+	}
+	return err
+}
+
+// workspaceChanged is called once a job, or the placing of inputs, may have
+// changed the workspace mounted in the given container. If the image is yet to
+// be built then whether the Dockerfile is missing is checked through that
+// container, so the next job only has to start a container of its own to look
+// for the Dockerfile once it exists.
+func (d *Driver) workspaceChanged(ctx context.Context, id string) {
+	if d.Dockerfile == "" {
+		return
+	}
+
+	d.buildMu.Lock()
+	defer d.buildMu.Unlock()
+
+	if d.built == "" {
+		if err := d.statDockerfile(ctx, id); err != nil {
+			d.missing = false
+		}
+	}
+}
+
+// image returns the image to execute the next job in. If a Dockerfile is
+// configured, and exists in the workspace, then the image is built from it,
+// and tagged with the hash of the build context. If an image with that tag
+// already exists then it is reused instead of being built again. Until the
+// Dockerfile exists the configured Image is returned.
+func (d *Driver) image(ctx context.Context, w io.Writer) (string, error) {
+	if d.Dockerfile == "" {
+		return d.Image, nil
+	}
+
+	d.buildMu.Lock()
+	defer d.buildMu.Unlock()
+
+	if d.built != "" {
+		return d.built, nil
+	}
+
+	// Nothing has changed the workspace since the Dockerfile was last seen
+	// to be missing.
+	if d.missing {
+		return d.Image, nil
+	}
+
+	id, remove, err := d.placer(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer remove()
+
+	if err := d.statDockerfile(ctx, id); err != nil {
+		return "", err
+	}
+
+	if d.missing {
+		return d.Image, nil
+	}
+
+	dir := d.Context
+
+	if dir == "" {
+		dir = path.Dir(d.Dockerfile)
+	}
+
+	dockerfile := d.Dockerfile
+
+	if dir != "." {
+		if !strings.HasPrefix(dockerfile, dir+"/") {
+			return "", errors.New("dockerfile " + d.Dockerfile + " is not within context " + dir)
+		}
+		dockerfile = strings.TrimPrefix(dockerfile, dir+"/")
+	}
+
+	f, hash, err := d.buildContext(ctx, id, dir, dockerfile)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	tag := "djinn-build:" + hash[:16]
+
+	if _, _, err := d.client.ImageInspectWithRaw(ctx, tag); err == nil {
+		fmt.Fprintf(w, "Using built image %s...\n\n", tag)
+
+		d.built = tag
+		return tag, nil
+	}
+
+	fmt.Fprintf(w, "Building image %s from %s...\n", tag, d.Dockerfile)
+
+	resp, err := d.client.ImageBuild(ctx, f, types.ImageBuildOptions{
+		Tags:       []string{tag},
+		Dockerfile: dockerfile,
+		Remove:     true,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+
+	for {
+		var msg struct {
+			Stream string
+			Error  string
+		}
+
+		if err := dec.Decode(&msg); err != nil {
+			if !errors.Is(err, io.EOF) {
+				return "", err
 			}
+			break
+		}
+
+		if msg.Error != "" {
+			return "", errors.New("failed to build image: " + msg.Error)
 		}
+		io.WriteString(w, msg.Stream)
 	}
-	return nil
+
+	fmt.Fprintln(w)
+
+	d.built = tag
+	return tag, nil
+}
+
+// hostConfig returns the configuration for a container that mounts the
+// workspace volume, with the resource limits, and security options applied.
+func (d *Driver) hostConfig() container.HostConfig {
+	cfg := container.HostConfig{
+		Mounts: []mount.Mount{
+			{Type: mount.TypeVolume, Source: d.volume.Name, Target: d.Workspace},
+		},
+		NetworkMode: container.NetworkMode(d.Network),
+		Privileged:  d.Privileged,
+		CapAdd:      strslice.StrSlice(d.CapAdd),
+		Resources: container.Resources{
+			NanoCPUs: int64(d.CPUs * 1e9),
+			Memory:   d.Memory,
+		},
+	}
+
+	if d.PidsLimit > 0 {
+		cfg.Resources.PidsLimit = &d.PidsLimit
+	}
+
+	if d.network != "" {
+		cfg.NetworkMode = container.NetworkMode(d.network)
+	}
+	return cfg
 }
 
 // Execute performs the given runner.Job in a Driver container. Each job is
 // turned into a shell script and placed onto an initial container. A
 // subsequent container is then created, and the previously placed script is
 // used as that new container's entrypoint. The logs for the container are
-// forwarded to the underlying io.Writer.
-func (d *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
-	hostCfg := container.HostConfig{
-		Mounts: []mount.Mount{
-			{Type: mount.TypeVolume, Source: d.volume.Name, Target: d.Workspace},
-		},
+// forwarded to the underlying io.Writer. If the given context is cancelled
+// whilst the container is running, then the container is killed. The resource
+// limits, and security options of the driver are applied to both containers.
+func (d *Driver) Execute(ctx context.Context, j *runner.Job, artifacts fs.FS) error {
+	image, err := d.image(ctx, j.Writer)
+
+	if err != nil {
+		return err
 	}
 
+	hostCfg := d.hostConfig()
+
 	cfg := container.Config{
-		Image: d.Image,
+		Image: image,
 		Cmd:   []string{"true"},
 	}
 
-	ctx := context.Background()
-
 	ctr, err := d.client.ContainerCreate(ctx, &cfg, &hostCfg, nil, nil, "")
 
 	if err != nil {
 		return err
 	}
 
-	d.containers = append(d.containers, ctr.ID)
+	d.addContainer(ctr.ID)
+
+	var cacheKey string
+
+	if j.Cache != nil {
+		key, err := driver.RestoreCache(j.Writer, j.Cache, d.cacheEnv(ctx, ctr.ID))
+
+		if err != nil {
+			fmt.Fprintln(j.Writer, "cache error:", errors.Cause(err))
+		}
+		cacheKey = key
+	}
 
-	script := strings.Replace(j.Name+".sh", " ", "-", -1)
+	script := driver.ScriptName(j)
 	buf := driver.CreateScript(j)
 
 	hdr := tar.Header{
@@ -259,7 +935,7 @@ func (d *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 	}
 
 	cfg.Cmd = []string{}
-	cfg.Env = d.env
+	cfg.Env = append(append([]string{}, d.env...), j.Env...)
 	cfg.Entrypoint = []string{script}
 
 	ctr, err = d.client.ContainerCreate(ctx, &cfg, &hostCfg, nil, nil, "")
@@ -268,12 +944,14 @@ func (d *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 		return err
 	}
 
-	d.containers = append(d.containers, ctr.ID)
+	d.addContainer(ctr.ID)
 
 	if err := d.client.ContainerStart(ctx, ctr.ID, types.ContainerStartOptions{}); err != nil {
 		return err
 	}
 
+	defer d.workspaceChanged(ctx, ctr.ID)
+
 	logOpts := types.ContainerLogsOptions{
 		ShowStderr: true,
 		ShowStdout: true,
@@ -287,6 +965,12 @@ func (d *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 	select {
 	case err := <-errs:
 		if err != nil {
+			if ctx.Err() != nil {
+				d.client.ContainerKill(context.Background(), ctr.ID, "KILL")
+				d.copyLogs(j.Writer, ctr.ID, logOpts)
+
+				return ctx.Err()
+			}
 			return err
 		}
 	case resp := <-status:
@@ -302,24 +986,27 @@ func (d *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 	defer rc.Close()
 	stdcopy.StdCopy(j.Writer, io.Discard, rc)
 
+	if code == 0 && cacheKey != "" {
+		if err := driver.SaveCache(j.Writer, j.Cache, cacheKey, d.cacheEnv(ctx, ctr.ID)); err != nil {
+			fmt.Fprintln(j.Writer, "cache error:", errors.Cause(err))
+		}
+	}
+
 	if len(j.Artifacts) > 0 {
 		fmt.Fprintln(j.Writer)
 	}
 
-	for src, dst := range j.Artifacts {
-		if err := d.collectArtifact(ctx, j.Writer, artifacts, ctr.ID, src, dst); err != nil {
-			fmt.Fprintln(j.Writer, "artifact error:", errors.Cause(err))
-		}
-	}
+	driver.CollectArtifacts(j, artifacts, d.walker(ctx, ctr.ID))
 
 	if code != 0 {
-		return runner.ErrFailed
+		return &runner.ExitError{Code: code}
 	}
 	return nil
 }
 
-// Destroy will remove all containers created during job execution, and the
-// volume. All of these operations are forced.
+// Destroy will remove all containers created during job execution, including
+// the services, the volume, and the network. All of these operations are
+// forced.
 func (d *Driver) Destroy() {
 	if d.client == nil {
 		return
@@ -336,13 +1023,60 @@ func (d *Driver) Destroy() {
 		d.client.ContainerRemove(ctx, ctr, opts)
 	}
 	d.client.VolumeRemove(ctx, d.volume.Name, true)
+
+	if d.network != "" {
+		d.client.NetworkRemove(ctx, d.network)
+	}
 }
 
-func (d *Driver) placeObjects(pt runner.Passthrough, objects fs.FS) error {
-	if len(pt) == 0 {
-		return nil
+// place copies the given file from the filestore into the workspace of the
+// given container.
+func (d *Driver) place(ctx context.Context, id, src, dst string, objects fs.FS) error {
+	f, err := objects.Open(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return err
 	}
 
+	hdr, err := tar.FileInfoHeader(info, info.Name())
+
+	if err != nil {
+		return err
+	}
+
+	hdr.Name = strings.TrimPrefix(dst, d.Workspace)
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	tw := tar.NewWriter(pw)
+
+	go func() {
+		err := tw.WriteHeader(hdr)
+
+		if err == nil {
+			_, err = io.Copy(tw, f)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return d.client.CopyToContainer(ctx, id, d.Workspace, pr, types.CopyToContainerOptions{})
+}
+
+// placer creates a container with the workspace mounted for placing files
+// into. The returned function removes the container.
+func (d *Driver) placer(ctx context.Context) (string, func(), error) {
 	cfg := &container.Config{
 		Image: d.Image,
 		Cmd:   []string{"true"},
@@ -358,68 +1092,61 @@ func (d *Driver) placeObjects(pt runner.Passthrough, objects fs.FS) error {
 		},
 	}
 
-	ctx := context.Background()
-
 	ctr, err := d.client.ContainerCreate(ctx, cfg, hostCfg, nil, nil, "")
 
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
-	for src, dst := range pt {
-		func(src, dst string) {
-			fmt.Fprintln(d.Writer, "Placing object", src, "=>", dst)
-
-			info, err := objects.Stat(src)
-
-			if err != nil {
-				fmt.Fprintln(d.Writer, "object error:", err)
-				return
-			}
-
-			hdr, err := tar.FileInfoHeader(info, info.Name())
-
-			if err != nil {
-				fmt.Fprintln(d.Writer, "object error:", err)
-				return
-			}
+	return ctr.ID, func() {
+		d.client.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{})
+	}, nil
+}
 
-			hdr.Name = strings.TrimPrefix(dst, d.Workspace)
+// Place copies the given files from the filestore into the workspace. Docker
+// creates the parent directories of each destination as the files are copied.
+func (d *Driver) Place(ctx context.Context, w io.Writer, pt runner.Passthrough, objects fs.FS) error {
+	id, remove, err := d.placer(ctx)
 
-			pr, pw := io.Pipe()
-			defer pr.Close()
+	if err != nil {
+		return err
+	}
 
-			tw := tar.NewWriter(pw)
+	defer remove()
+	defer d.workspaceChanged(ctx, id)
 
-			go func(src string) {
-				defer tw.Close()
-				defer pw.Close()
+	for src, dst := range pt {
+		fmt.Fprintln(w, "Placing input", src, "=>", dst)
 
-				tw.WriteHeader(hdr)
+		if err := d.place(ctx, id, src, dst, objects); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-				f, err := objects.Open(src)
+func (d *Driver) placeObjects(pt runner.Passthrough, objects fs.FS) error {
+	if len(pt) == 0 {
+		return nil
+	}
 
-				if err != nil {
-					fmt.Fprintln(d.Writer, "object error:", errors.Cause(err))
-					return
-				}
+	ctx := context.Background()
 
-				defer f.Close()
+	id, remove, err := d.placer(ctx)
 
-				if _, err := io.Copy(tw, f); err != nil {
-					fmt.Fprintln(d.Writer, "object error:", errors.Cause(err))
-				}
-			}(src)
+	if err != nil {
+		return err
+	}
 
-			err = d.client.CopyToContainer(ctx, ctr.ID, d.Workspace, pr, types.CopyToContainerOptions{})
+	for src, dst := range pt {
+		fmt.Fprintln(d.Writer, "Placing object", src, "=>", dst)
 
-			if err != nil {
-				fmt.Fprintln(d.Writer, "object error:", errors.Cause(err))
-			}
-		}(src, dst)
+		if err := d.place(ctx, id, src, dst, objects); err != nil {
+			fmt.Fprintln(d.Writer, "object error:", errors.Cause(err))
+		}
 	}
 
-	d.client.ContainerRemove(ctx, ctr.ID, types.ContainerRemoveOptions{})
+	remove()
 	fmt.Fprintln(d.Writer)
 
 	return nil
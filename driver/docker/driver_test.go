@@ -0,0 +1,521 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/andrewpillar/fs"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+func Test_MergeLimits(t *testing.T) {
+	cfg := &Config{
+		CPUs:      2,
+		Memory:    4 << 30,
+		PidsLimit: 1024,
+		CapAdd:    []string{"NET_ADMIN"},
+	}
+
+	tests := []struct {
+		m    map[string]string
+		fail bool
+	}{
+		{map[string]string{}, false},
+		{map[string]string{"cpus": "1.5", "memory": "1GB", "pids_limit": "512"}, false},
+		{map[string]string{"network": "none", "cap_add": "cap_net_admin"}, false},
+		{map[string]string{"cpus": "4"}, true},
+		{map[string]string{"memory": "8GB"}, true},
+		{map[string]string{"memory": "1XB"}, true},
+		{map[string]string{"pids_limit": "2048"}, true},
+		{map[string]string{"network": "host"}, true},
+		{map[string]string{"privileged": "true"}, true},
+		{map[string]string{"cap_add": "SYS_ADMIN"}, true},
+	}
+
+	for i, test := range tests {
+		cfg1 := cfg.Merge(test.m).(*Config)
+
+		if test.fail {
+			if cfg1.err == nil {
+				t.Errorf("tests[%d] - expected merge to fail\n", i)
+			}
+			continue
+		}
+
+		if cfg1.err != nil {
+			t.Errorf("tests[%d] - unexpected error: %s\n", i, cfg1.err)
+		}
+	}
+
+	cfg1 := cfg.Merge(map[string]string{"memory": "512MB", "cap_add": "net_admin"}).(*Config)
+
+	if cfg1.Memory != 512<<20 {
+		t.Errorf("unexpected memory, expected=%d, got=%d\n", 512<<20, cfg1.Memory)
+	}
+
+	if cfg1.CPUs != 2 {
+		t.Errorf("unexpected cpus, expected=%v, got=%v\n", 2, cfg1.CPUs)
+	}
+
+	if len(cfg1.CapAdd) != 1 || cfg1.CapAdd[0] != "NET_ADMIN" {
+		t.Errorf("unexpected cap_add, expected=%v, got=%v\n", []string{"NET_ADMIN"}, cfg1.CapAdd)
+	}
+
+	if cfg2 := cfg.Merge(map[string]string{}).(*Config); len(cfg2.CapAdd) != 0 {
+		t.Errorf("expected no capabilities to be added, got=%v\n", cfg2.CapAdd)
+	}
+}
+
+func Test_PrefixWriter(t *testing.T) {
+	var buf strings.Builder
+
+	w := &prefixWriter{
+		mu:     &sync.Mutex{},
+		w:      &buf,
+		prefix: "[postgres] ",
+	}
+
+	w.Write([]byte("database system is ready\nlistening on "))
+	w.Write([]byte("port 5432\nshutting"))
+	w.flush()
+
+	expected := "[postgres] database system is ready\n[postgres] listening on port 5432\n[postgres] shutting\n"
+
+	if s := buf.String(); s != expected {
+		t.Errorf("unexpected output, expected=%q, got=%q\n", expected, s)
+	}
+}
+
+func Test_RegistryAuth(t *testing.T) {
+	hosts := map[string]string{
+		"golang":                          "docker.io",
+		"library/golang:1.21":             "docker.io",
+		"index.docker.io/library/golang":  "docker.io",
+		"registry.example.com/ci/go:1.21": "registry.example.com",
+		"localhost:5000/ci/go":            "localhost:5000",
+		"localhost/ci/go":                 "localhost",
+	}
+
+	for image, expected := range hosts {
+		if host := registryHost(image); host != expected {
+			t.Errorf("unexpected registry host for %s, expected=%q, got=%q\n", image, expected, host)
+		}
+	}
+
+	// Record the credentials sent with each pull.
+	pulls := make(map[string]string)
+
+	var mu sync.Mutex
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/images/create") {
+			image := r.URL.Query().Get("fromImage") + ":" + r.URL.Query().Get("tag")
+
+			mu.Lock()
+			pulls[image] = r.Header.Get("X-Registry-Auth")
+			mu.Unlock()
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+srv.Listener.Addr().String()), client.WithVersion("1.40"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := (&Config{}).Merge(map[string]string{
+		"registry_auth": "registry.example.com=REGISTRY_AUTH",
+	}).(*Config)
+
+	if cfg.err != nil {
+		t.Fatal(cfg.err)
+	}
+
+	d := &Driver{
+		Writer: io.Discard,
+		client: cli,
+		env:    []string{"GOPROXY=direct", "REGISTRY_AUTH=ci:s3cr3t:pass"},
+	}
+
+	cfg.Apply(d)
+
+	// The job image is from the private registry, and the service image is
+	// from Docker Hub.
+	for _, image := range []string{"registry.example.com/ci/go:1.21", "postgres:15"} {
+		if err := d.pull(context.Background(), image); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := base64.URLEncoding.DecodeString(pulls["registry.example.com/ci/go:1.21"])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var auth types.AuthConfig
+
+	if err := json.Unmarshal(b, &auth); err != nil {
+		t.Fatal(err)
+	}
+
+	if auth.Username != "ci" || auth.Password != "s3cr3t:pass" || auth.ServerAddress != "registry.example.com" {
+		t.Errorf("unexpected auth config %+v\n", auth)
+	}
+
+	if s, ok := pulls["postgres:15"]; !ok || s != "" {
+		t.Errorf("expected service image to be pulled without credentials, got=%q\n", s)
+	}
+
+	d.env = []string{"GOPROXY=direct"}
+
+	if _, err := d.registryAuth(d.env, "registry.example.com/ci/go"); err == nil {
+		t.Errorf("expected error for unset registry auth variable\n")
+	}
+
+	d.env = []string{"REGISTRY_AUTH=token"}
+
+	if _, err := d.registryAuth(d.env, "registry.example.com/ci/go"); err == nil {
+		t.Errorf("expected error for malformed registry auth variable\n")
+	}
+
+	// Credentials can also be read from a key.
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "registry"), []byte("ci:k3y\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	d.RegistryAuth = map[string]string{"registry.example.com": "key:registry"}
+	d.objects = fs.New(dir)
+
+	s, err := d.registryAuth(nil, "registry.example.com/ci/go")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = base64.URLEncoding.DecodeString(s)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth = types.AuthConfig{}
+
+	if err := json.Unmarshal(b, &auth); err != nil {
+		t.Fatal(err)
+	}
+
+	if auth.Username != "ci" || auth.Password != "k3y" {
+		t.Errorf("unexpected auth config from key %+v\n", auth)
+	}
+
+	d.RegistryAuth["registry.example.com"] = "key:missing"
+
+	if _, err := d.registryAuth(nil, "registry.example.com/ci/go"); err == nil {
+		t.Errorf("expected error for missing registry auth key\n")
+	}
+}
+
+func Test_MergePull(t *testing.T) {
+	tests := []struct {
+		worker string
+		pull   string
+		fail   bool
+	}{
+		{"", "always", false},
+		{"", "if-not-present", true},
+		{"always", "never", true},
+		{"if-not-present", "always", false},
+		{"if-not-present", "if-not-present", false},
+		{"if-not-present", "never", true},
+		{"never", "if-not-present", false},
+		{"never", "sometimes", true},
+	}
+
+	for i, test := range tests {
+		cfg := (&Config{Pull: test.worker}).Merge(map[string]string{"pull": test.pull}).(*Config)
+
+		if test.fail {
+			if cfg.err == nil {
+				t.Errorf("tests[%d] - expected merge to fail\n", i)
+			}
+			continue
+		}
+
+		if cfg.err != nil {
+			t.Errorf("tests[%d] - unexpected error: %s\n", i, cfg.err)
+		}
+
+		if cfg.Pull != test.pull {
+			t.Errorf("tests[%d] - unexpected pull policy, expected=%q, got=%q\n", i, test.pull, cfg.Pull)
+		}
+	}
+}
+
+type contextFile struct {
+	name    string
+	mode    int64
+	content string
+}
+
+func contextTar(t *testing.T, files ...contextFile) []byte {
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+
+	for _, f := range files {
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.name,
+			Mode:     f.mode,
+			Size:     int64(len(f.content)),
+		}
+
+		if strings.HasSuffix(f.name, "/") {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Size = 0
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := io.WriteString(tw, f.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_CopyContext(t *testing.T) {
+	files := []contextFile{
+		{"build/", 0755, ""},
+		{"build/Dockerfile", 0644, "FROM golang:1.21\nCOPY main.go .\n"},
+		{"build/main.go", 0644, "package main\n"},
+	}
+
+	var buf bytes.Buffer
+
+	hash, dockerfile, err := copyContext(&buf, bytes.NewReader(contextTar(t, files...)), "Dockerfile")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(dockerfile) != files[1].content {
+		t.Errorf("unexpected dockerfile, expected=%q, got=%q\n", files[1].content, string(dockerfile))
+	}
+
+	tr := tar.NewReader(&buf)
+
+	names := make([]string, 0, len(files))
+
+	for {
+		hdr, err := tr.Next()
+
+		if err != nil {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+
+	expected := []string{"Dockerfile", "main.go"}
+
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("unexpected names, expected=%v, got=%v\n", expected, names)
+	}
+
+	tests := []struct {
+		files []contextFile
+		same  bool
+	}{
+		{files, true},
+		{[]contextFile{{"other/", 0755, ""}, {"other/Dockerfile", 0644, files[1].content}, {"other/main.go", 0644, files[2].content}}, true},
+		{[]contextFile{files[0], {"build/Dockerfile", 0644, "FROM golang:1.22\nCOPY main.go .\n"}, files[2]}, false},
+		{[]contextFile{files[0], files[1], {"build/main.go", 0755, "package main\n"}}, false},
+		{[]contextFile{files[0], files[1], {"build/main.go", 0644, "package main\n\nfunc main() {}\n"}}, false},
+		{[]contextFile{files[0], files[1], {"build/main_test.go", 0644, "package main\n"}}, false},
+	}
+
+	for i, test := range tests {
+		h, _, err := copyContext(io.Discard, bytes.NewReader(contextTar(t, test.files...)), "Dockerfile")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if (h == hash) != test.same {
+			t.Errorf("tests[%d] - unexpected hash, expected same=%v, got=%q\n", i, test.same, h)
+		}
+	}
+}
+
+func Test_BaseImages(t *testing.T) {
+	dockerfile := `# syntax=docker/dockerfile:1
+FROM --platform=linux/amd64 golang:1.21 AS build
+RUN go build -o /app .
+
+FROM build AS test
+RUN go test ./...
+
+from scratch
+FROM golang:${GO_VERSION}
+FROM registry.example.com/ci/alpine:3.18
+COPY --from=build /app /app
+`
+
+	expected := []string{"golang:1.21", "registry.example.com/ci/alpine:3.18"}
+
+	if images := baseImages([]byte(dockerfile)); !reflect.DeepEqual(images, expected) {
+		t.Errorf("unexpected base images, expected=%v, got=%v\n", expected, images)
+	}
+}
+
+// fakeDocker is a Docker API that serves the workspace of a single container
+// for building an image from a Dockerfile.
+type fakeDocker struct {
+	mu         sync.Mutex
+	creates    int
+	stats      int
+	dockerfile bool
+	context    []byte
+	baseID     string
+}
+
+func (f *fakeDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stat := base64.StdEncoding.EncodeToString([]byte(`{"name":"workspace","mode":2147484141}`))
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/containers/create"):
+		f.creates++
+		w.Write([]byte(`{"Id":"placer"}`))
+	case strings.HasSuffix(r.URL.Path, "/archive") && r.Method == http.MethodHead:
+		f.stats++
+
+		if !f.dockerfile {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("X-Docker-Container-Path-Stat", stat)
+	case strings.HasSuffix(r.URL.Path, "/archive"):
+		w.Header().Set("X-Docker-Container-Path-Stat", stat)
+		w.Write(f.context)
+	case strings.HasSuffix(r.URL.Path, "/json"):
+		w.Write([]byte(`{"Id":"` + f.baseID + `"}`))
+	default:
+		w.Write([]byte("{}"))
+	}
+}
+
+func Test_DriverImage(t *testing.T) {
+	fake := &fakeDocker{
+		context: contextTar(t,
+			contextFile{"workspace/", 0755, ""},
+			contextFile{"workspace/Dockerfile", 0644, "FROM golang:1.21\n"},
+		),
+		baseID: "sha256:1",
+	}
+
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+srv.Listener.Addr().String()), client.WithVersion("1.40"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Driver{
+		Writer:     io.Discard,
+		client:     cli,
+		Image:      "golang",
+		Workspace:  "/workspace",
+		Dockerfile: "Dockerfile",
+	}
+
+	ctx := context.Background()
+
+	// The Dockerfile is only looked for once, until the workspace changes.
+	for i := 0; i < 3; i++ {
+		image, err := d.image(ctx, io.Discard)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if image != d.Image {
+			t.Errorf("unexpected image, expected=%q, got=%q\n", d.Image, image)
+		}
+	}
+
+	if fake.creates != 1 {
+		t.Errorf("unexpected number of containers created, expected=%d, got=%d\n", 1, fake.creates)
+	}
+
+	d.workspaceChanged(ctx, "job")
+
+	if _, err := d.image(ctx, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	if fake.creates != 1 || fake.stats != 2 {
+		t.Errorf("unexpected number of containers created, and stats, expected=%d, %d, got=%d, %d\n", 1, 2, fake.creates, fake.stats)
+	}
+
+	// The Dockerfile now exists, so it is looked for again once a job has
+	// changed the workspace.
+	fake.dockerfile = true
+
+	d.workspaceChanged(ctx, "job")
+
+	image, err := d.image(ctx, io.Discard)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(image, "djinn-build:") {
+		t.Fatalf("unexpected image, expected built image, got=%q\n", image)
+	}
+
+	// A change to the base image changes the tag of the built image.
+	d.built = ""
+	fake.baseID = "sha256:2"
+
+	rebuilt, err := d.image(ctx, io.Discard)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rebuilt == image {
+		t.Errorf("expected a different tag for a changed base image, got=%q\n", rebuilt)
+	}
+}
@@ -0,0 +1,231 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"djinn-ci.com/errors"
+
+	"github.com/andrewpillar/fs"
+	"github.com/docker/docker/api/types"
+)
+
+// The policies for pulling the images used by the driver.
+const (
+	PullAlways       = "always"         // PullAlways pulls the image each time the driver is created.
+	PullIfNotPresent = "if-not-present" // PullIfNotPresent only pulls the image if it does not exist.
+	PullNever        = "never"          // PullNever never pulls the image, so it must already exist.
+)
+
+// pullPolicies orders the pull policies from the most strict to the most
+// lenient.
+var pullPolicies = map[string]int{
+	"":               0,
+	PullAlways:       0,
+	PullIfNotPresent: 1,
+	PullNever:        2,
+}
+
+// dockerHub is the host of the registry images without a registry in their
+// name are pulled from.
+const dockerHub = "docker.io"
+
+// registryHost returns the host of the registry for the given image. Images
+// without a registry in their name are pulled from Docker Hub.
+func registryHost(image string) string {
+	host, _, ok := strings.Cut(image, "/")
+
+	if !ok || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		return dockerHub
+	}
+
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		return dockerHub
+	}
+	return host
+}
+
+// parseRegistryAuth parses the registry credentials from the given manifest
+// value. This is a comma separated list of host=VARIABLE pairs, where the
+// variable contains the credentials for the registry at that host. A pair of
+// host=key:NAME refers to the key containing the credentials instead.
+func parseRegistryAuth(s string) (map[string]string, error) {
+	auths := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+
+		if pair == "" {
+			continue
+		}
+
+		host, name, ok := strings.Cut(pair, "=")
+
+		if !ok || host == "" || name == "" {
+			return nil, errors.New("invalid registry_auth " + pair + ", expected host=VARIABLE or host=key:NAME")
+		}
+		auths[registryHost(host+"/")] = name
+	}
+	return auths, nil
+}
+
+// mergeRegistry merges in the pull policy, and registry credentials from the
+// given manifest driver configuration. The pull policy cannot be more lenient
+// than the one configured.
+func (cfg *Config) mergeRegistry(m map[string]string) error {
+	if pull := m["pull"]; pull != "" {
+		policy, ok := pullPolicies[pull]
+
+		if !ok {
+			return errors.New("invalid pull policy " + pull)
+		}
+
+		if policy > pullPolicies[cfg.Pull] {
+			return errors.New("pull policy " + pull + " is not allowed")
+		}
+		cfg.Pull = pull
+	}
+
+	cfg.auths = nil
+
+	if s := m["registry_auth"]; s != "" {
+		auths, err := parseRegistryAuth(s)
+
+		if err != nil {
+			return err
+		}
+		cfg.auths = auths
+	}
+	return nil
+}
+
+// registryKey returns the content of the key with the given name from the
+// objects of the driver, with surrounding whitespace removed.
+func (d *Driver) registryKey(name string) (string, error) {
+	if d.objects == nil {
+		return "", errors.New("registry auth key " + name + " does not exist")
+	}
+
+	f, err := d.objects.Open(name)
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", errors.New("registry auth key " + name + " does not exist")
+		}
+		return "", err
+	}
+
+	defer f.Close()
+
+	b, err := io.ReadAll(f)
+
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// registryAuth returns the encoded credentials for the registry of the given
+// image. If no credentials are configured for the registry then nothing is
+// returned. The variable containing the credentials is looked up in the given
+// environment, or if they are in a key then the key is read from the objects
+// of the driver. The credentials are expected to be in the format of
+// username:password.
+func (d *Driver) registryAuth(env []string, image string) (string, error) {
+	host := registryHost(image)
+
+	name, ok := d.RegistryAuth[host]
+
+	if !ok {
+		return "", nil
+	}
+
+	var val string
+
+	kind := "variable"
+
+	if strings.HasPrefix(name, "key:") {
+		var err error
+
+		kind, name = "key", strings.TrimPrefix(name, "key:")
+
+		if val, err = d.registryKey(name); err != nil {
+			return "", err
+		}
+	} else {
+		ok = false
+
+		for _, kv := range env {
+			if key, v, found := strings.Cut(kv, "="); found && key == name {
+				val, ok = v, true
+			}
+		}
+
+		if !ok {
+			return "", errors.New("registry auth variable " + name + " is not set")
+		}
+	}
+
+	username, password, ok := strings.Cut(val, ":")
+
+	if !ok {
+		return "", errors.New("registry auth " + kind + " " + name + " must be in the format username:password")
+	}
+
+	addr := host
+
+	if addr == dockerHub {
+		addr = "https://index.docker.io/v1/"
+	}
+
+	b, err := json.Marshal(types.AuthConfig{
+		Username:      username,
+		Password:      password,
+		ServerAddress: addr,
+	})
+
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
+
+// pull pulls the given image according to the pull policy of the driver. The
+// image is pulled with the credentials for its registry, if any.
+func (d *Driver) pull(ctx context.Context, image string) error {
+	switch d.Pull {
+	case PullNever:
+		return nil
+	case PullIfNotPresent:
+		if _, _, err := d.client.ImageInspectWithRaw(ctx, image); err == nil {
+			fmt.Fprintf(d.Writer, "Using existing image %s...\n", image)
+			return nil
+		}
+	}
+
+	auth, err := d.registryAuth(d.env, image)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(d.Writer, "Pulling image %s...\n", image)
+
+	rc, err := d.client.ImagePull(ctx, image, types.ImagePullOptions{
+		RegistryAuth: auth,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	defer rc.Close()
+
+	_, err = io.Copy(io.Discard, rc)
+	return err
+}
@@ -0,0 +1,242 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"djinn-ci.com/errors"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Service is a container that is run alongside the jobs, such as a database.
+// Each job container can reach the service via its Alias on the network that
+// is created for the driver.
+type Service struct {
+	Image   string
+	Alias   string
+	Env     []string
+	Command []string
+}
+
+// prefixWriter writes each line to the underlying io.Writer with a prefix.
+// Partial lines are buffered until they are complete, or the writer is
+// flushed. The mutex is shared between writers so lines are not interleaved.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+
+		if i < 0 {
+			break
+		}
+
+		w.mu.Lock()
+		fmt.Fprintf(w.w, "%s%s", w.prefix, w.buf[:i+1])
+		w.mu.Unlock()
+
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *prefixWriter) flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	fmt.Fprintf(w.w, "%s%s\n", w.prefix, w.buf)
+	w.mu.Unlock()
+
+	w.buf = w.buf[:0]
+}
+
+// startService pulls the image for the given service according to the pull
+// policy, and starts it on the driver's network.
+func (d *Driver) startService(ctx context.Context, s Service) (string, error) {
+	if err := d.pull(ctx, s.Image); err != nil {
+		return "", err
+	}
+
+	cfg := &container.Config{
+		Image: s.Image,
+		Env:   s.Env,
+		Cmd:   s.Command,
+	}
+
+	hostCfg := d.hostConfig()
+	hostCfg.Mounts = nil
+
+	netCfg := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			d.network: {
+				Aliases: []string{s.Alias},
+			},
+		},
+	}
+
+	ctr, err := d.client.ContainerCreate(ctx, cfg, &hostCfg, netCfg, nil, "")
+
+	if err != nil {
+		return "", err
+	}
+
+	d.addContainer(ctr.ID)
+
+	fmt.Fprintf(d.Writer, "Starting service %s as %s...\n", s.Image, s.Alias)
+
+	if err := d.client.ContainerStart(ctx, ctr.ID, types.ContainerStartOptions{}); err != nil {
+		return "", err
+	}
+	return ctr.ID, nil
+}
+
+// waitService waits for the given service container to become healthy. If
+// the container has no health check then it is considered healthy once it is
+// running.
+func (d *Driver) waitService(ctx context.Context, alias, id string) error {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	for {
+		info, err := d.client.ContainerInspect(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		state := info.State
+
+		if state.Dead || state.Status == "exited" {
+			return errors.New("service " + alias + " exited with code " + strconv.Itoa(state.ExitCode))
+		}
+
+		if state.Running {
+			if state.Health == nil || state.Health.Status == types.Healthy {
+				return nil
+			}
+
+			if state.Health.Status == types.Unhealthy {
+				return errors.New("service " + alias + " is unhealthy")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// startServices creates the network for the driver, and starts each of the
+// services on it. The logs of each service are streamed to the driver's
+// io.Writer until every service is healthy.
+func (d *Driver) startServices(ctx context.Context) error {
+	if len(d.Services) == 0 {
+		return nil
+	}
+
+	name := "djinn-" + d.volume.Name
+
+	if len(d.volume.Name) > 12 {
+		name = "djinn-" + d.volume.Name[:12]
+	}
+
+	// Containers on an internal network can only reach each other, this
+	// keeps jobs without network access, whilst still letting them reach the
+	// services.
+	resp, err := d.client.NetworkCreate(ctx, name, types.NetworkCreate{
+		CheckDuplicate: true,
+		Internal:       d.Network == "none",
+	})
+
+	if err != nil {
+		return err
+	}
+
+	d.network = resp.ID
+
+	ids := make([]string, 0, len(d.Services))
+
+	for _, s := range d.Services {
+		id, err := d.startService(ctx, s)
+
+		if err != nil {
+			return errors.New("service " + s.Alias + ": " + err.Error())
+		}
+		ids = append(ids, id)
+	}
+
+	logctx, cancel := context.WithCancel(ctx)
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for i, id := range ids {
+		wg.Add(1)
+
+		go func(alias, id string) {
+			defer wg.Done()
+
+			rc, err := d.client.ContainerLogs(logctx, id, types.ContainerLogsOptions{
+				ShowStdout: true,
+				ShowStderr: true,
+				Follow:     true,
+			})
+
+			if err != nil {
+				return
+			}
+
+			defer rc.Close()
+
+			w := &prefixWriter{
+				mu:     &mu,
+				w:      d.Writer,
+				prefix: "[" + alias + "] ",
+			}
+
+			stdcopy.StdCopy(w, w, rc)
+			w.flush()
+		}(d.Services[i].Alias, id)
+	}
+
+	for i, id := range ids {
+		if err = d.waitService(ctx, d.Services[i].Alias, id); err != nil {
+			break
+		}
+	}
+
+	cancel()
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	for _, s := range d.Services {
+		fmt.Fprintf(d.Writer, "Service %s is healthy\n", s.Alias)
+	}
+	fmt.Fprintln(d.Writer)
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"djinn-ci.com/errors"
 	"djinn-ci.com/runner"
@@ -56,20 +58,176 @@ func (e *Error) Error() string {
 // io.Writer, and configuration passed in via the map.
 type Init func(io.Writer, Config) runner.Driver
 
-var preamble = "#!/bin/sh\nexec 2>&1\nset -ex\n\n"
+// shell describes how the script for a job is written for a shell.
+type shell struct {
+	// ext is the file extension of the script, some interpreters refuse to
+	// execute scripts without the extension they expect.
+	ext string
+
+	// preamble is written at the start of the script, before the environment
+	// variables are set.
+	preamble string
+
+	// export returns the statement for setting an environment variable.
+	export func(key, val string) string
+
+	// errexit is written after the environment variables are set, this
+	// configures the script to exit on the first failed command. If echo is
+	// true then the script also echoes each command as it is executed.
+	errexit func(echo bool) string
+
+	// cd returns the statement for changing into the given directory.
+	cd func(dir string) string
+
+	// mark returns the statement for writing the given command marker to
+	// the output of the script, without the statement itself being echoed.
+	mark func(marker string) string
+}
+
+// quote returns the given string quoted for use in a shell script. The string
+// is double quoted so that any variables referenced in it are expanded.
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`")
+	return `"` + r.Replace(s) + `"`
+}
+
+// pwshQuote returns the given string single quoted for use in a PowerShell
+// script, this means the string is taken literally.
+func pwshQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+var (
+	sh = shell{
+		ext:      ".sh",
+		preamble: "#!/bin/sh\nexec 2>&1\n",
+		export: func(key, val string) string {
+			return "export " + key + "=" + quote(val)
+		},
+		errexit: func(echo bool) string {
+			if echo {
+				return "set -ex"
+			}
+			return "set -e"
+		},
+		cd: func(dir string) string { return "cd " + quote(dir) },
+		mark: func(marker string) string {
+			return "{ echo '" + marker + "'; } 2>/dev/null"
+		},
+	}
+
+	bash = shell{
+		ext:      ".sh",
+		preamble: "#!/usr/bin/env bash\nexec 2>&1\n",
+		export:   sh.export,
+		errexit: func(echo bool) string {
+			if echo {
+				return "set -exo pipefail"
+			}
+			return "set -eo pipefail"
+		},
+		cd:   sh.cd,
+		mark: sh.mark,
+	}
+
+	// python3 has no way of echoing the statements of a script without
+	// tracing every call made, so commands are never echoed.
+	python3 = shell{
+		ext:      ".py",
+		preamble: "#!/usr/bin/env python3\nimport os, sys\nos.dup2(sys.stdout.fileno(), sys.stderr.fileno())\n",
+		export: func(key, val string) string {
+			return "os.environ[" + strconv.Quote(key) + "] = os.path.expandvars(" + strconv.Quote(val) + ")"
+		},
+		errexit: func(bool) string { return "" },
+		cd:      func(dir string) string { return "os.chdir(" + strconv.Quote(dir) + ")" },
+		mark:    func(marker string) string { return "print(" + strconv.Quote(marker) + ", flush=True)" },
+	}
+
+	pwsh = shell{
+		ext:      ".ps1",
+		preamble: "#!/usr/bin/env pwsh\n",
+		export: func(key, val string) string {
+			return "$env:" + key + " = " + pwshQuote(val)
+		},
+		errexit: func(echo bool) string {
+			s := "$ErrorActionPreference = 'Stop'\n$PSNativeCommandUseErrorActionPreference = $true"
+
+			if echo {
+				s += "\nSet-PSDebug -Trace 1"
+			}
+			return s
+		},
+		cd: func(dir string) string { return "Set-Location " + pwshQuote(dir) },
+
+		// Tracing cannot be suppressed for a single statement, the traced
+		// marker is removed from the output along with the marker itself.
+		mark: func(marker string) string { return "[Console]::WriteLine(" + pwshQuote(marker) + ")" },
+	}
+
+	shells = map[string]shell{
+		"sh":      sh,
+		"bash":    bash,
+		"python3": python3,
+		"pwsh":    pwsh,
+	}
+)
+
+func getShell(name string) shell {
+	if s, ok := shells[name]; ok {
+		return s
+	}
+	return sh
+}
+
+// ScriptName returns the name of the script file that the given job is
+// written to, this has the file extension expected by the shell of the job.
+func ScriptName(j *runner.Job) string {
+	return strings.Replace(j.Name, " ", "-", -1) + getShell(j.Shell).ext
+}
 
 // CreateScript returns a bytes.Buffer that contains a concatenation of the
-// given runner.Job commands into a shell script. Each shell script is
-// prepended with the given header,
+// given runner.Job commands into a script for the shell of the job. If the job
+// has no shell then sh is used, and each script is prepended with the header,
 //
 //	#!/bin/sh
 //	exec 2>&1
 //	set -ex
+//
+// The environment variables of the job are exported before tracing is turned
+// on, so their values are not written to the output of the job. If the job is
+// quiet then its commands are not echoed. If the job has a working directory
+// then the script changes into it before executing the commands. Each command
+// is preceded by a statement writing the runner.CommandMarker for it, so the
+// result of each command can be recorded.
 func CreateScript(j *runner.Job) *bytes.Buffer {
-	buf := bytes.NewBufferString(preamble)
+	s := getShell(j.Shell)
+
+	buf := bytes.NewBufferString(s.preamble)
+
+	for _, kv := range j.Env {
+		parts := strings.SplitN(kv, "=", 2)
+
+		if len(parts) < 2 {
+			continue
+		}
+		fmt.Fprintln(buf, s.export(parts[0], parts[1]))
+	}
+
+	if errexit := s.errexit(!j.Quiet); errexit != "" {
+		buf.WriteString(errexit + "\n")
+	}
+	buf.WriteString("\n")
+
+	if j.Workdir != "" {
+		fmt.Fprintln(buf, s.cd(j.Workdir))
+	}
+
+	for i, cmd := range j.Commands {
+		fmt.Fprintf(buf, "%s\n%s\n", s.mark(runner.CommandMarker(i)), cmd)
+	}
 
-	for _, cmd := range j.Commands {
-		fmt.Fprintf(buf, "%s\n", cmd)
+	if len(j.Commands) > 0 {
+		fmt.Fprintln(buf, s.mark(runner.CommandMarker(-1)))
 	}
 	return buf
 }
@@ -0,0 +1,77 @@
+package driver
+
+import (
+	"testing"
+
+	"djinn-ci.com/runner"
+)
+
+func Test_CreateScript(t *testing.T) {
+	j := &runner.Job{
+		Env:      []string{"GOFLAGS=-mod=vendor", `MSG=say "hi" to $USER`},
+		Workdir:  "src/djinn",
+		Commands: []string{"go build", "go test ./..."},
+	}
+
+	expected := `#!/bin/sh
+exec 2>&1
+export GOFLAGS="-mod=vendor"
+export MSG="say \"hi\" to $USER"
+set -ex
+
+cd "src/djinn"
+{ echo '##djinn:cmd:1'; } 2>/dev/null
+go build
+{ echo '##djinn:cmd:2'; } 2>/dev/null
+go test ./...
+{ echo '##djinn:cmd:end'; } 2>/dev/null
+`
+
+	if s := CreateScript(j).String(); s != expected {
+		t.Errorf("unexpected script, expected=\n%s\ngot=\n%s\n", expected, s)
+	}
+
+	j = &runner.Job{
+		Commands: []string{"make"},
+	}
+
+	expected = "#!/bin/sh\nexec 2>&1\nset -ex\n\n{ echo '##djinn:cmd:1'; } 2>/dev/null\nmake\n{ echo '##djinn:cmd:end'; } 2>/dev/null\n"
+
+	if s := CreateScript(j).String(); s != expected {
+		t.Errorf("unexpected script, expected=\n%s\ngot=\n%s\n", expected, s)
+	}
+}
+
+func Test_CreateScriptShell(t *testing.T) {
+	tests := []struct {
+		job      *runner.Job
+		name     string
+		expected string
+	}{
+		{
+			&runner.Job{Name: "build", Shell: "bash", Quiet: true, Commands: []string{"make | tee make.log"}},
+			"build.sh",
+			"#!/usr/bin/env bash\nexec 2>&1\nset -eo pipefail\n\n{ echo '##djinn:cmd:1'; } 2>/dev/null\nmake | tee make.log\n{ echo '##djinn:cmd:end'; } 2>/dev/null\n",
+		},
+		{
+			&runner.Job{Name: "report", Shell: "python3", Env: []string{"OUT=$HOME/out"}, Workdir: "src", Commands: []string{"print(os.getcwd())"}},
+			"report.py",
+			"#!/usr/bin/env python3\nimport os, sys\nos.dup2(sys.stdout.fileno(), sys.stderr.fileno())\nos.environ[\"OUT\"] = os.path.expandvars(\"$HOME/out\")\n\nos.chdir(\"src\")\nprint(\"##djinn:cmd:1\", flush=True)\nprint(os.getcwd())\nprint(\"##djinn:cmd:end\", flush=True)\n",
+		},
+		{
+			&runner.Job{Name: "test win", Shell: "pwsh", Env: []string{"MSG=it's"}, Commands: []string{"Write-Output $env:MSG"}},
+			"test-win.ps1",
+			"#!/usr/bin/env pwsh\n$env:MSG = 'it''s'\n$ErrorActionPreference = 'Stop'\n$PSNativeCommandUseErrorActionPreference = $true\nSet-PSDebug -Trace 1\n\n[Console]::WriteLine('##djinn:cmd:1')\nWrite-Output $env:MSG\n[Console]::WriteLine('##djinn:cmd:end')\n",
+		},
+	}
+
+	for i, test := range tests {
+		if name := ScriptName(test.job); name != test.name {
+			t.Errorf("tests[%d] - unexpected script name, expected=%q, got=%q\n", i, test.name, name)
+		}
+
+		if s := CreateScript(test.job).String(); s != test.expected {
+			t.Errorf("tests[%d] - unexpected script, expected=\n%s\ngot=\n%s\n", i, test.expected, s)
+		}
+	}
+}
@@ -4,12 +4,14 @@
 package os
 
 import (
+	"archive/tar"
 	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"djinn-ci.com/driver"
@@ -38,7 +40,10 @@ func Tmpdir() error {
 	return os.Chdir(dir)
 }
 
-var _ runner.Driver = (*Driver)(nil)
+var (
+	_ runner.Driver = (*Driver)(nil)
+	_ runner.Placer = (*Driver)(nil)
+)
 
 func Init(w io.Writer, cfg driver.Config) runner.Driver {
 	return &Driver{
@@ -76,35 +81,155 @@ func (d *Driver) Create(_ context.Context, env []string, pt runner.Passthrough,
 	}
 
 	for src, dst := range pt {
-		func(src, dst string) {
-			fmt.Fprintln(d.Writer, "Placing object", src, "=>", dst)
+		fmt.Fprintln(d.Writer, "Placing object", src, "=>", dst)
+
+		if err := place(src, dst, objects); err != nil {
+			fmt.Fprintln(d.Writer, "object error:", errors.Cause(err))
+		}
+	}
+	return nil
+}
+
+// place copies the given file from the filestore to the destination on the
+// host.
+func place(src, dst string, objects fs.FS) error {
+	object, err := objects.Open(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer object.Close()
 
-			f, err := os.Create(dst)
+	f, err := os.Create(dst)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = io.Copy(f, object)
+	return err
+}
+
+// Place copies the given files from the filestore onto the host, creating
+// the parent directories of each destination.
+func (d *Driver) Place(_ context.Context, w io.Writer, pt runner.Passthrough, objects fs.FS) error {
+	for src, dst := range pt {
+		fmt.Fprintln(w, "Placing input", src, "=>", dst)
+
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+
+		if err := place(src, dst, objects); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walk walks the files on the host from the given root for collecting
+// artifacts, and saving caches.
+func walk(root string, fn driver.WalkFunc) error {
+	return filepath.WalkDir(root, func(name string, ent os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !ent.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := ent.Info()
+
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(name)
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		return fn(filepath.ToSlash(name), info, f)
+	})
+}
+
+var cacheEnv = driver.CacheEnv{
+	Read: func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.FromSlash(name))
+	},
+	Extract: func(r io.Reader) error {
+		return driver.ExtractTar(r, func(hdr *tar.Header, r io.Reader) error {
+			name := filepath.FromSlash(hdr.Name)
+
+			if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				return err
+			}
+
+			f, err := os.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(hdr.Mode).Perm())
 
 			if err != nil {
-				fmt.Fprintln(d.Writer, "object error:", errors.Cause(err))
-				return
+				return err
 			}
 
 			defer f.Close()
 
-			object, err := objects.Open(src)
+			_, err = io.Copy(f, r)
+			return err
+		})
+	},
+	Walk: walk,
+}
 
-			if err != nil {
-				fmt.Fprintln(d.Writer, "object error:", errors.Cause(err))
-				return
-			}
+// command runs the given command for the job in the given directory.
+func command(ctx context.Context, j *runner.Job, dir string, args []string) error {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), j.Env...)
+	cmd.Dir = dir
+	cmd.Stdout = j.Writer
+	cmd.Stderr = j.Writer
 
-			if _, err := io.Copy(f, object); err != nil {
-				fmt.Fprintln(d.Writer, "object error:", errors.Cause(err))
-			}
-		}(src, dst)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+
+		if errors.As(err, &exitErr) && ctx.Err() == nil {
+			return &runner.ExitError{Code: exitErr.ExitCode()}
+		}
+		return err
 	}
 	return nil
 }
 
-func (d *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
-	for _, cmdline := range j.Commands {
+// run runs the commands of the given job. If the job has a shell then the
+// commands are written to a script that is executed with that shell,
+// otherwise each command is executed directly.
+func (d *Driver) run(ctx context.Context, j *runner.Job) error {
+	if j.Shell != "" {
+		dir, err := os.MkdirTemp("", "driver-os-script-*")
+
+		if err != nil {
+			return err
+		}
+
+		defer os.RemoveAll(dir)
+
+		script := filepath.Join(dir, driver.ScriptName(j))
+
+		if err := os.WriteFile(script, driver.CreateScript(j).Bytes(), 0700); err != nil {
+			return err
+		}
+
+		// The script changes into the working directory of the job itself.
+		return command(ctx, j, "", []string{script})
+	}
+
+	for i, cmdline := range j.Commands {
 		r := csv.NewReader(strings.NewReader(cmdline))
 		r.Comma = ' '
 
@@ -114,41 +239,41 @@ func (d *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 			continue
 		}
 
-		cmd := exec.Command(args[0], args[1:]...)
-		cmd.Stdout = j.Writer
-		cmd.Stderr = j.Writer
+		fmt.Fprintln(j.Writer, runner.CommandMarker(i))
 
-		if err := cmd.Run(); err != nil {
+		if err := command(ctx, j, j.Workdir, args); err != nil {
 			return err
 		}
 	}
 
-	for src, dst := range j.Artifacts {
-		func(src, dst string) {
-			fmt.Fprintln(j.Writer, "Collecting artifact", src, "=>", dst)
-
-			f, err := os.Open(src)
+	fmt.Fprintln(j.Writer, runner.CommandMarker(-1))
+	return nil
+}
 
-			if err != nil {
-				fmt.Fprintln(j.Writer, "artifact error:", errors.Cause(err))
-				return
-			}
+func (d *Driver) Execute(ctx context.Context, j *runner.Job, artifacts fs.FS) error {
+	var cacheKey string
 
-			defer f.Close()
+	if j.Cache != nil {
+		key, err := driver.RestoreCache(j.Writer, j.Cache, cacheEnv)
 
-			artifact, err := fs.ReadFile(dst, f)
+		if err != nil {
+			fmt.Fprintln(j.Writer, "cache error:", errors.Cause(err))
+		}
+		cacheKey = key
+	}
 
-			if err != nil {
-				fmt.Fprintln(j.Writer, "artifact error:", errors.Cause(err))
-				return
-			}
+	if err := d.run(ctx, j); err != nil {
+		return err
+	}
 
-			defer artifact.Close()
+	if cacheKey != "" {
+		if err := driver.SaveCache(j.Writer, j.Cache, cacheKey, cacheEnv); err != nil {
+			fmt.Fprintln(j.Writer, "cache error:", errors.Cause(err))
+		}
+	}
 
-			if _, err := artifacts.Put(artifact); err != nil {
-				fmt.Fprintln(d.Writer, "artifact error:", errors.Cause(err))
-			}
-		}(src, dst)
+	if len(j.Artifacts) > 0 {
+		driver.CollectArtifacts(j, artifacts, walk)
 	}
 	return nil
 }
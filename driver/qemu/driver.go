@@ -61,6 +61,7 @@ type Driver struct {
 
 var (
 	_ runner.Driver = (*Driver)(nil)
+	_ runner.Placer = (*Driver)(nil)
 	_ driver.Config = (*Config)(nil)
 
 	tcpMaxPort int64 = 65535
@@ -268,7 +269,15 @@ func (q *Driver) Create(c context.Context, env []string, pt runner.Passthrough,
 
 // Execute will perform the given job on the Driver machine via a call to the
 // underlying SSH driver.
-func (q *Driver) Execute(j *runner.Job, artifacts fs.FS) error { return q.ssh.Execute(j, artifacts) }
+func (q *Driver) Execute(ctx context.Context, j *runner.Job, artifacts fs.FS) error {
+	return q.ssh.Execute(ctx, j, artifacts)
+}
+
+// Place copies the given files onto the Driver machine via a call to the
+// underlying SSH driver.
+func (q *Driver) Place(ctx context.Context, w io.Writer, pt runner.Passthrough, objects fs.FS) error {
+	return q.ssh.Place(ctx, w, pt, objects)
+}
 
 // Destroy will terminate the SSH connection to the Driver machine, and kill the
 // underlying OS process, then will remove the PIDFILE for that process.
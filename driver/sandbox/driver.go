@@ -0,0 +1,513 @@
+// Package sandbox provides an implementation of a Driver for job execution.
+// Jobs are executed in a root filesystem unpacked from a tarball, isolated
+// from the host via user, mount, PID, and network namespaces. The root of each
+// job is pivoted into the root filesystem, and the root of the host detached.
+// This provides isolation on Linux hosts without the need for a Docker daemon,
+// or KVM.
+package sandbox
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"djinn-ci.com/driver"
+	"djinn-ci.com/errors"
+	"djinn-ci.com/runner"
+
+	"github.com/andrewpillar/fs"
+)
+
+// RealpathFunc is the function used for deriving the underlying path of the
+// rootfs tarball for the given image.
+type RealpathFunc func(image string) (string, error)
+
+// BaseImage returns the path of the rootfs tarball for the given base image in
+// the given directory of images. An error is returned if the name of the image
+// is absolute, or contains any .. elements, so the path never refers to
+// anything outside of the base images.
+func BaseImage(dir, image string) (string, error) {
+	if image == "" || path.IsAbs(image) {
+		return "", errors.New("invalid image name " + image)
+	}
+
+	for _, part := range strings.Split(image, "/") {
+		if part == ".." {
+			return "", errors.New("invalid image name " + image)
+		}
+	}
+
+	base := filepath.Join(dir, "_base", "sandbox")
+	name := filepath.Join(base, filepath.FromSlash(image))
+
+	if !strings.HasPrefix(name, base+string(filepath.Separator)) {
+		return "", errors.New("invalid image name " + image)
+	}
+	return name, nil
+}
+
+// Config is the struct used for initializing a new sandbox driver for build
+// execution.
+type Config struct {
+	Images    string // The location to look for rootfs tarballs.
+	Network   bool   // Whether the sandbox shares the network of the host.
+	Image     string // The rootfs tarball to unpack for the sandbox.
+	Workspace string // The directory in the sandbox jobs are executed in.
+}
+
+// Driver provides an implementation of the runner.Driver interface for
+// running jobs in a sandbox. The rootfs of the sandbox is unpacked into a
+// temporary directory on the host, and each job is executed in a new set of
+// namespaces with that directory as its root.
+type Driver struct {
+	io.Writer
+
+	rootfs string
+	root   *os.File
+	env    []string
+
+	Image     string // Image is the name of the rootfs to unpack for the sandbox.
+	Workspace string // Workspace is the directory in the sandbox jobs are executed in.
+
+	// Network specifies whether the jobs share the network of the host. If
+	// false, then jobs are executed in a network namespace of their own with
+	// no access to the network.
+	Network bool
+
+	// Realpath is a function callback that will return the full path of the
+	// rootfs tarball to unpack for the sandbox.
+	Realpath RealpathFunc
+}
+
+var (
+	_ runner.Driver = (*Driver)(nil)
+	_ runner.Placer = (*Driver)(nil)
+	_ driver.Config = (*Config)(nil)
+)
+
+// DefaultWorkspace is the directory jobs are executed in if no workspace is
+// given.
+const DefaultWorkspace = "/root"
+
+// devices are the devices of the host that are bind mounted into the sandbox.
+var devices = []string{"null", "zero", "full", "random", "urandom", "tty"}
+
+// Init initializes a new sandbox driver using the given io.Writer, and
+// applying the given driver.Config.
+func Init(w io.Writer, cfg driver.Config) runner.Driver {
+	d := &Driver{
+		Writer: w,
+	}
+
+	cfg.Apply(d)
+	return d
+}
+
+func (cfg *Config) Merge(m map[string]string) driver.Config {
+	cfg2 := (*cfg)
+	cfg2.Image = m["image"]
+	cfg2.Workspace = m["workspace"]
+
+	return &cfg2
+}
+
+func (cfg *Config) Apply(d runner.Driver) {
+	v, ok := d.(*Driver)
+
+	if !ok {
+		return
+	}
+
+	v.Image = cfg.Image
+	v.Network = cfg.Network
+	v.Workspace = cfg.Workspace
+
+	if v.Workspace == "" {
+		v.Workspace = DefaultWorkspace
+	}
+
+	v.Realpath = func(image string) (string, error) {
+		path, err := BaseImage(cfg.Images, image)
+
+		if err != nil {
+			return "", err
+		}
+
+		info, err := os.Stat(path)
+
+		if err != nil {
+			return "", err
+		}
+		if info.IsDir() {
+			return "", errors.New("image is not a file")
+		}
+		return path, nil
+	}
+}
+
+// abs returns the given path in the sandbox as an absolute path, relative
+// paths are relative to the workspace.
+func (d *Driver) abs(name string) string {
+	if !path.IsAbs(name) {
+		return path.Join(d.Workspace, name)
+	}
+	return name
+}
+
+// extractRootfs unpacks the given tarball into the rootfs of the sandbox. The
+// tarball may be compressed with gzip. Only directories, regular files, and
+// links are unpacked, device files cannot be created without privileges, and
+// are expected to be bind mounted if needed. Each entry is unpacked beneath
+// the root of the sandbox, so links in the tarball cannot lead outside of it.
+func (d *Driver) extractRootfs(r io.Reader) error {
+	br := bufio.NewReader(r)
+
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+
+		if err != nil {
+			return err
+		}
+
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		name := path.Clean("/" + hdr.Name)
+
+		if name == "/" {
+			continue
+		}
+
+		// The permissions are masked so the setuid, and setgid bits are
+		// dropped, and the owner can always write to a directory.
+		mode := os.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := d.mkdir(name, mode|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			d.remove(name)
+
+			f, err := d.create(name, mode, true)
+
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(f, tr)
+			f.Close()
+
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			d.remove(name)
+
+			if err := d.symlink(hdr.Linkname, name); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			d.remove(name)
+
+			if err := d.link(hdr.Linkname, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mountpoints creates the directories, and files in the rootfs that the proc
+// filesystem, and devices are mounted to.
+func (d *Driver) mountpoints() error {
+	for _, dir := range []string{"/proc", "/dev"} {
+		if err := d.mkdirAll(dir); err != nil {
+			return err
+		}
+	}
+
+	for _, dev := range devices {
+		name := "/dev/" + dev
+
+		d.remove(name)
+
+		f, err := d.create(name, 0666, true)
+
+		if err != nil {
+			return err
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// Create unpacks the rootfs of the configured image into a temporary
+// directory, and places the given objects into it.
+func (d *Driver) Create(ctx context.Context, env []string, pt runner.Passthrough, objects fs.FS) error {
+	if d.Writer == nil {
+		return errors.New("cannot create driver with nil io.Writer")
+	}
+
+	fmt.Fprintln(d.Writer, "Running with driver sandbox...")
+
+	if err := supported(); err != nil {
+		return err
+	}
+
+	image, err := d.Realpath(d.Image)
+
+	if err != nil {
+		fmt.Fprintln(d.Writer, "couldn't find image", d.Image)
+		fmt.Fprintln(d.Writer, "make sure your image exists in the namespace the build is being run from")
+		return err
+	}
+
+	f, err := os.Open(image)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	d.rootfs, err = os.MkdirTemp("", "djinn-sandbox-*")
+
+	if err != nil {
+		return err
+	}
+
+	d.root, err = openRoot(d.rootfs)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(d.Writer, "Unpacking image %s...\n", d.Image)
+
+	if err := d.extractRootfs(f); err != nil {
+		return err
+	}
+
+	if err := d.mountpoints(); err != nil {
+		return err
+	}
+
+	if err := d.mkdirAll(d.Workspace); err != nil {
+		return err
+	}
+
+	if d.Network {
+		fmt.Fprintf(d.Writer, "Using sandbox with host network...\n\n")
+	} else {
+		fmt.Fprintf(d.Writer, "Using sandbox with no network...\n\n")
+	}
+
+	d.env = env
+	return d.placeObjects(pt, objects)
+}
+
+// place copies the given file from the filestore to the destination in the
+// sandbox, creating the parent directories of the destination.
+func (d *Driver) place(src, dst string, objects fs.FS) error {
+	object, err := objects.Open(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer object.Close()
+
+	f, err := d.create(d.abs(dst), 0644, false)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = io.Copy(f, object)
+	return err
+}
+
+func (d *Driver) placeObjects(pt runner.Passthrough, objects fs.FS) error {
+	for src, dst := range pt {
+		fmt.Fprintln(d.Writer, "Placing object", src, "=>", dst)
+
+		if err := d.place(src, dst, objects); err != nil {
+			fmt.Fprintln(d.Writer, "object error:", errors.Cause(err))
+		}
+	}
+	return nil
+}
+
+// Place copies the given files from the filestore into the sandbox.
+func (d *Driver) Place(_ context.Context, w io.Writer, pt runner.Passthrough, objects fs.FS) error {
+	for src, dst := range pt {
+		fmt.Fprintln(w, "Placing input", src, "=>", dst)
+
+		if err := d.place(src, dst, objects); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Driver) cacheEnv() driver.CacheEnv {
+	return driver.CacheEnv{
+		Read: func(name string) ([]byte, error) {
+			f, err := d.open(d.abs(name))
+
+			if err != nil {
+				return nil, err
+			}
+
+			defer f.Close()
+
+			return io.ReadAll(f)
+		},
+		Extract: func(r io.Reader) error {
+			return driver.ExtractTar(r, func(hdr *tar.Header, r io.Reader) error {
+				f, err := d.create(d.abs(hdr.Name), os.FileMode(hdr.Mode), false)
+
+				if err != nil {
+					return err
+				}
+
+				defer f.Close()
+
+				_, err = io.Copy(f, r)
+				return err
+			})
+		},
+		Walk: d.walk,
+	}
+}
+
+// Execute writes the given job to a script in the sandbox, and executes it in
+// a new set of namespaces with the rootfs of the sandbox as its root. The
+// current binary is executed as the sandbox init, which pivots the root into
+// the rootfs before executing the script. The rootfs is expected to have the
+// shell of the job.
+func (d *Driver) Execute(ctx context.Context, j *runner.Job, artifacts fs.FS) error {
+	var cacheKey string
+
+	if j.Cache != nil {
+		key, err := driver.RestoreCache(j.Writer, j.Cache, d.cacheEnv())
+
+		if err != nil {
+			fmt.Fprintln(j.Writer, "cache error:", errors.Cause(err))
+		}
+		cacheKey = key
+	}
+
+	// The script is given an unpredictable name, and is created exclusively,
+	// since concurrently executing jobs can write to the same directory.
+	b := make([]byte, 8)
+
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+
+	script := path.Join("/tmp", "djinn-"+hex.EncodeToString(b)+"-"+driver.ScriptName(j))
+
+	f, err := d.create(script, 0700, true)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = driver.CreateScript(j).WriteTo(f)
+	f.Close()
+
+	if err != nil {
+		return err
+	}
+
+	defer d.remove(script)
+
+	env := []string{
+		"HOME=" + d.Workspace,
+		"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+	}
+
+	cmd := exec.CommandContext(ctx, "/proc/self/exe")
+	cmd.Args = []string{initName, d.rootfs, d.Workspace, script}
+	cmd.Env = append(append(env, d.env...), j.Env...)
+	cmd.Stdout = j.Writer
+	cmd.Stderr = j.Writer
+	cmd.SysProcAttr = sysProcAttr(d.Network)
+
+	code := 0
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+
+		if !errors.As(err, &exitErr) || ctx.Err() != nil {
+			return err
+		}
+		code = exitErr.ExitCode()
+	}
+
+	if code == 0 && cacheKey != "" {
+		if err := driver.SaveCache(j.Writer, j.Cache, cacheKey, d.cacheEnv()); err != nil {
+			fmt.Fprintln(j.Writer, "cache error:", errors.Cause(err))
+		}
+	}
+
+	if len(j.Artifacts) > 0 {
+		driver.CollectArtifacts(j, artifacts, d.walk)
+	}
+
+	if code != 0 {
+		return &runner.ExitError{Code: code}
+	}
+	return nil
+}
+
+// Destroy removes the rootfs of the sandbox.
+func (d *Driver) Destroy() {
+	if d.root != nil {
+		d.root.Close()
+	}
+
+	if d.rootfs == "" {
+		return
+	}
+
+	// Make sure every directory can be removed, as jobs may have created
+	// directories without write permissions.
+	filepath.WalkDir(d.rootfs, func(name string, ent os.DirEntry, err error) error {
+		if err == nil && ent.IsDir() {
+			os.Chmod(name, 0700)
+		}
+		return nil
+	})
+	os.RemoveAll(d.rootfs)
+}
@@ -0,0 +1,44 @@
+package sandbox
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_BaseImage(t *testing.T) {
+	dir := "/var/lib/djinn/images"
+
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"debian.tar.gz", "_base/sandbox/debian.tar.gz"},
+		{"debian/bookworm.tar.gz", "_base/sandbox/debian/bookworm.tar.gz"},
+		{"../../../../etc/shadow", ""},
+		{"debian/../../qemu/x86_64/disk.qcow2", ""},
+		{"/etc/shadow", ""},
+		{"..", ""},
+		{".", ""},
+		{"", ""},
+	}
+
+	for i, test := range tests {
+		name, err := BaseImage(dir, test.image)
+
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("tests[%d] - expected error for image %q, got=%q\n", i, test.image, name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("tests[%d] - unexpected error: %s\n", i, err)
+			continue
+		}
+
+		if expected := filepath.Join(dir, test.expected); name != expected {
+			t.Errorf("tests[%d] - unexpected path, expected=%q, got=%q\n", i, expected, name)
+		}
+	}
+}
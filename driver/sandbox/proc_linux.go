@@ -0,0 +1,125 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// initName is the name the sandbox init is executed as. The driver executes
+// the current binary with this name in a new set of namespaces, so the root
+// can be pivoted into the rootfs before the job is executed.
+const initName = "djinn-sandbox-init"
+
+// oldroot is the directory in the rootfs the root of the host is moved to when
+// the root is pivoted, before it is detached.
+const oldroot = ".djinn-oldroot"
+
+// dropCaps are the capabilities dropped from the bounding set before the job is
+// executed, so the job cannot change its root, or mount filesystems.
+var dropCaps = []uintptr{unix.CAP_SYS_CHROOT, unix.CAP_SYS_ADMIN}
+
+func init() {
+	if len(os.Args) != 4 || os.Args[0] != initName {
+		return
+	}
+
+	if err := sandboxInit(os.Args[1], os.Args[2], os.Args[3]); err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox:", err)
+		os.Exit(125)
+	}
+}
+
+// sandboxInit sets up the sandbox in the new set of namespaces, and executes
+// the given script. The proc filesystem is mounted for the new PID namespace,
+// and the devices are bind mounted from the host, neither are fatal since they
+// are not permitted on every host. The root is then pivoted into the rootfs,
+// and the old root detached, so nothing of the host filesystem is reachable
+// from the sandbox.
+func sandboxInit(rootfs, workspace, script string) error {
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return os.NewSyscallError("mount", err)
+	}
+
+	// The new root has to be a mount point to be pivoted into.
+	if err := unix.Mount(rootfs, rootfs, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return os.NewSyscallError("mount", err)
+	}
+
+	unix.Mount("proc", filepath.Join(rootfs, "proc"), "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, "")
+
+	for _, dev := range devices {
+		unix.Mount(filepath.Join("/dev", dev), filepath.Join(rootfs, "dev", dev), "", unix.MS_BIND, "")
+	}
+
+	if err := unix.Chdir(rootfs); err != nil {
+		return os.NewSyscallError("chdir", err)
+	}
+
+	if err := unix.Mkdir(oldroot, 0700); err != nil && err != unix.EEXIST {
+		return os.NewSyscallError("mkdir", err)
+	}
+
+	if err := unix.PivotRoot(".", oldroot); err != nil {
+		return os.NewSyscallError("pivot_root", err)
+	}
+
+	if err := unix.Chdir("/"); err != nil {
+		return os.NewSyscallError("chdir", err)
+	}
+
+	if err := unix.Unmount("/"+oldroot, unix.MNT_DETACH); err != nil {
+		return os.NewSyscallError("umount", err)
+	}
+
+	unix.Rmdir("/" + oldroot)
+
+	for _, c := range dropCaps {
+		if err := unix.Prctl(unix.PR_CAPBSET_DROP, c, 0, 0, 0); err != nil {
+			return os.NewSyscallError("prctl", err)
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return os.NewSyscallError("prctl", err)
+	}
+
+	if err := unix.Chdir(workspace); err != nil {
+		return os.NewSyscallError("chdir", err)
+	}
+
+	if err := unix.Exec(script, []string{script}, os.Environ()); err != nil {
+		return &os.PathError{Op: "exec", Path: script, Err: err}
+	}
+	return nil
+}
+
+// supported reports whether the sandbox driver is supported on the host.
+func supported() error { return nil }
+
+// sysProcAttr returns the attributes for executing a job in new user, mount,
+// and PID namespaces. The user, and group of the worker are mapped to root in
+// the user namespace. If network is false then the job is also executed in a
+// new network namespace.
+func sysProcAttr(network bool) *syscall.SysProcAttr {
+	flags := syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWUTS | syscall.CLONE_NEWIPC
+
+	if !network {
+		flags |= syscall.CLONE_NEWNET
+	}
+
+	return &syscall.SysProcAttr{
+		Cloneflags: uintptr(flags),
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getuid(), Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getgid(), Size: 1},
+		},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+}
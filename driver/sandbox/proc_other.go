@@ -0,0 +1,20 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"runtime"
+	"syscall"
+
+	"djinn-ci.com/errors"
+)
+
+// supported reports whether the sandbox driver is supported on the host.
+func supported() error {
+	return errors.New("sandbox driver is not supported on " + runtime.GOOS)
+}
+
+func sysProcAttr(bool) *syscall.SysProcAttr { return nil }
+
+// initName is the name the sandbox init is executed as.
+const initName = "djinn-sandbox-init"
@@ -0,0 +1,323 @@
+package sandbox
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"djinn-ci.com/driver"
+
+	"golang.org/x/sys/unix"
+)
+
+// The functions in this file operate on files in the rootfs whilst jobs may be
+// executing in it. Every path is resolved with openat2 as if the rootfs was the
+// root of the filesystem, and each file is operated on via the descriptor it
+// was resolved to, so a job swapping a directory for a symbolic link cannot
+// lead the driver outside of the rootfs.
+
+// openRoot opens the given directory for resolving paths in the sandbox.
+func openRoot(dir string) (*os.File, error) {
+	fd, err := unix.Open(dir, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: dir, Err: err}
+	}
+	return os.NewFile(uintptr(fd), dir), nil
+}
+
+// rel returns the given name in the sandbox relative to its root.
+func rel(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// openat opens the given name relative to the given directory. Symbolic links
+// are resolved beneath the root of the sandbox.
+func openat(dir *os.File, name string, flags int, mode uint32) (*os.File, error) {
+	fd, err := unix.Openat2(int(dir.Fd()), name, &unix.OpenHow{
+		Flags:   uint64(flags | unix.O_CLOEXEC),
+		Mode:    uint64(mode),
+		Resolve: unix.RESOLVE_IN_ROOT | unix.RESOLVE_NO_MAGICLINKS,
+	})
+
+	if err != nil {
+		return nil, &os.PathError{Op: "openat2", Path: name, Err: err}
+	}
+	return os.NewFile(uintptr(fd), name), nil
+}
+
+// mkdirAll creates the given directory in the sandbox along with any parents.
+// Each directory is created beneath the descriptor of its parent.
+func (d *Driver) mkdirAll(name string) error {
+	dir := "."
+
+	for _, part := range strings.Split(rel(name), "/") {
+		if part == "." {
+			continue
+		}
+
+		parent, err := openat(d.root, dir, unix.O_PATH|unix.O_DIRECTORY, 0)
+
+		if err != nil {
+			return err
+		}
+
+		err = unix.Mkdirat(int(parent.Fd()), part, 0755)
+		parent.Close()
+
+		if err != nil && err != unix.EEXIST {
+			return &os.PathError{Op: "mkdirat", Path: path.Join(dir, part), Err: err}
+		}
+		dir = path.Join(dir, part)
+	}
+	return nil
+}
+
+// create creates the given file in the sandbox, along with its parent
+// directories. If excl is true then the file must not already exist. The file
+// is never opened through a symbolic link.
+func (d *Driver) create(name string, mode os.FileMode, excl bool) (*os.File, error) {
+	name = rel(name)
+
+	if err := d.mkdirAll(path.Dir(name)); err != nil {
+		return nil, err
+	}
+
+	flags := unix.O_CREAT | unix.O_WRONLY | unix.O_NOFOLLOW
+
+	if excl {
+		flags |= unix.O_EXCL
+	} else {
+		flags |= unix.O_TRUNC
+	}
+
+	f, err := openat(d.root, name, flags, uint32(mode.Perm()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, &os.PathError{Op: "create", Path: name, Err: unix.EINVAL}
+	}
+	return f, nil
+}
+
+// open opens the given file in the sandbox for reading.
+func (d *Driver) open(name string) (*os.File, error) {
+	return openat(d.root, rel(name), unix.O_RDONLY|unix.O_NONBLOCK, 0)
+}
+
+// mkdir creates the given directory in the sandbox along with any parents, and
+// sets its permissions. A symbolic link in place of the directory is replaced,
+// as tar would, so the permissions are never set through it.
+func (d *Driver) mkdir(name string, mode os.FileMode) error {
+	if err := d.mkdirAll(path.Dir(rel(name))); err != nil {
+		return err
+	}
+
+	parent, base, err := d.openParent(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer parent.Close()
+
+	var stat unix.Stat_t
+
+	if err := unix.Fstatat(int(parent.Fd()), base, &stat, unix.AT_SYMLINK_NOFOLLOW); err == nil && stat.Mode&unix.S_IFMT == unix.S_IFLNK {
+		if err := unix.Unlinkat(int(parent.Fd()), base, 0); err != nil {
+			return &os.PathError{Op: "unlinkat", Path: name, Err: err}
+		}
+	}
+
+	if err := unix.Mkdirat(int(parent.Fd()), base, 0700); err != nil && err != unix.EEXIST {
+		return &os.PathError{Op: "mkdirat", Path: name, Err: err}
+	}
+
+	fd, err := unix.Openat(int(parent.Fd()), base, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+
+	if err != nil {
+		return &os.PathError{Op: "openat", Path: name, Err: err}
+	}
+
+	defer unix.Close(fd)
+
+	if err := unix.Fchmod(fd, uint32(mode.Perm())); err != nil {
+		return &os.PathError{Op: "fchmod", Path: name, Err: err}
+	}
+	return nil
+}
+
+// openParent opens the parent directory of the given name in the sandbox, and
+// returns it along with the last element of the name.
+func (d *Driver) openParent(name string) (*os.File, string, error) {
+	name = rel(name)
+
+	parent, err := openat(d.root, path.Dir(name), unix.O_PATH|unix.O_DIRECTORY, 0)
+
+	if err != nil {
+		return nil, "", err
+	}
+	return parent, path.Base(name), nil
+}
+
+// remove removes the given file from the sandbox.
+func (d *Driver) remove(name string) error {
+	parent, base, err := d.openParent(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer parent.Close()
+
+	if err := unix.Unlinkat(int(parent.Fd()), base, 0); err != nil {
+		return &os.PathError{Op: "unlinkat", Path: name, Err: err}
+	}
+	return nil
+}
+
+// symlink creates a symbolic link with the given name in the sandbox pointing
+// to the given target, along with its parent directories. The target is left
+// as is, it is only ever resolved beneath the root.
+func (d *Driver) symlink(target, name string) error {
+	if err := d.mkdirAll(path.Dir(rel(name))); err != nil {
+		return err
+	}
+
+	parent, base, err := d.openParent(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer parent.Close()
+
+	if err := unix.Symlinkat(target, int(parent.Fd()), base); err != nil {
+		return &os.PathError{Op: "symlinkat", Path: name, Err: err}
+	}
+	return nil
+}
+
+// link creates a hard link with the given name in the sandbox to the file at
+// oldname in the sandbox, along with its parent directories. The last element
+// of oldname is not followed if it is a symbolic link.
+func (d *Driver) link(oldname, name string) error {
+	if err := d.mkdirAll(path.Dir(rel(name))); err != nil {
+		return err
+	}
+
+	oldparent, oldbase, err := d.openParent(oldname)
+
+	if err != nil {
+		return err
+	}
+
+	defer oldparent.Close()
+
+	parent, base, err := d.openParent(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer parent.Close()
+
+	if err := unix.Linkat(int(oldparent.Fd()), oldbase, int(parent.Fd()), base, 0); err != nil {
+		return &os.PathError{Op: "linkat", Path: name, Err: err}
+	}
+	return nil
+}
+
+// walkdir calls fn for each regular file beneath the given directory. Entries
+// are opened beneath the descriptor of their directory without following
+// symbolic links.
+func walkdir(dir *os.File, name string, fn driver.WalkFunc) error {
+	ents, err := dir.ReadDir(-1)
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(ents, func(i, j int) bool { return ents[i].Name() < ents[j].Name() })
+
+	for _, ent := range ents {
+		if !ent.IsDir() && !ent.Type().IsRegular() {
+			continue
+		}
+
+		fd, err := unix.Openat(int(dir.Fd()), ent.Name(), unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+
+		if err != nil {
+			if err == unix.ELOOP || err == unix.ENOENT {
+				continue
+			}
+			return &os.PathError{Op: "openat", Path: path.Join(name, ent.Name()), Err: err}
+		}
+
+		f := os.NewFile(uintptr(fd), ent.Name())
+
+		if err := walkfile(f, path.Join(name, ent.Name()), fn); err != nil {
+			f.Close()
+			return err
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// walkfile calls fn for the given file if it is regular, or for each regular
+// file beneath it if it is a directory.
+func walkfile(f *os.File, name string, fn driver.WalkFunc) error {
+	info, err := f.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return walkdir(f, name, fn)
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	return fn(name, info, f)
+}
+
+// walk walks the files in the sandbox from the given root for collecting
+// artifacts, and saving caches. The names given to the driver.WalkFunc are
+// the names in the sandbox, relative paths are relative to the workspace.
+func (d *Driver) walk(root string, fn driver.WalkFunc) error {
+	name := root
+
+	if !path.IsAbs(name) {
+		name = path.Join(d.Workspace, name)
+	}
+
+	f, err := d.open(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return walkfile(f, root, fn)
+}
@@ -0,0 +1,232 @@
+package sandbox
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_RootResolve(t *testing.T) {
+	host := t.TempDir()
+	rootfs := filepath.Join(host, "rootfs")
+
+	if err := os.MkdirAll(filepath.Join(rootfs, "root"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(host, "secret"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Links a job could swap in to point the driver at the host.
+	links := map[string]string{
+		"root/out":    host,
+		"root/parent": "../../..",
+		"root/secret": filepath.Join(host, "secret"),
+	}
+
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(rootfs, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root, err := openRoot(rootfs)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer root.Close()
+
+	d := &Driver{
+		rootfs:    rootfs,
+		root:      root,
+		Workspace: DefaultWorkspace,
+	}
+
+	// The absolute link resolves to a directory in the rootfs that does not
+	// exist, so this is expected to fail.
+	if f, err := d.create(d.abs("out/file"), 0644, false); err == nil {
+		f.Close()
+	}
+
+	f, err := d.create(d.abs("parent/file"), 0644, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if _, err := os.Stat(filepath.Join(host, "file")); err == nil {
+		t.Fatalf("file was created outside of the rootfs\n")
+	}
+
+	if _, err := os.Stat(filepath.Join(rootfs, "file")); err != nil {
+		t.Fatalf("expected file to be created in the rootfs: %s\n", err)
+	}
+
+	if _, err := d.create("/root/secret", 0644, false); err == nil {
+		t.Fatalf("expected error creating file through symbolic link\n")
+	}
+
+	if f, err := d.open("/root/secret"); err == nil {
+		b, _ := io.ReadAll(f)
+		f.Close()
+
+		if string(b) == "secret" {
+			t.Fatalf("read file outside of the rootfs\n")
+		}
+	}
+
+	if _, err := d.create("/tmp/script.sh", 0700, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := d.create("/tmp/script.sh", 0700, true); err == nil {
+		t.Fatalf("expected error creating existing script\n")
+	}
+
+	var walked []string
+
+	err = d.walk("/", func(name string, _ fs.FileInfo, _ io.Reader) error {
+		walked = append(walked, name)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"/file", "/tmp/script.sh"}
+
+	if len(walked) != len(expected) {
+		t.Fatalf("unexpected walked files, expected=%v, got=%v\n", expected, walked)
+	}
+
+	for i := range expected {
+		if walked[i] != expected[i] {
+			t.Errorf("walked[%d] - expected=%q, got=%q\n", i, expected[i], walked[i])
+		}
+	}
+
+	if err := d.remove("/tmp/script.sh"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_ExtractRootfs(t *testing.T) {
+	host := t.TempDir()
+	rootfs := filepath.Join(host, "rootfs")
+
+	if err := os.Mkdir(rootfs, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	target := filepath.Join(host, "etc")
+
+	if err := os.Mkdir(target, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(host, "secret"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+
+	// A symbolic link out of the rootfs, followed by entries that would be
+	// unpacked through it.
+	hdrs := []*tar.Header{
+		{Typeflag: tar.TypeSymlink, Name: "etc", Linkname: target},
+		{Typeflag: tar.TypeDir, Name: "etc/", Mode: 0700},
+		{Typeflag: tar.TypeReg, Name: "etc/passwd", Mode: 0644, Size: 4},
+		{Typeflag: tar.TypeSymlink, Name: "secret", Linkname: filepath.Join(host, "secret")},
+		{Typeflag: tar.TypeLink, Name: "hardlink", Linkname: "secret"},
+		{Typeflag: tar.TypeReg, Name: "../../escape", Mode: 0644, Size: 4},
+	}
+
+	for _, hdr := range hdrs {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+
+		if hdr.Size > 0 {
+			io.WriteString(tw, "root")
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := openRoot(rootfs)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer root.Close()
+
+	d := &Driver{
+		rootfs: rootfs,
+		root:   root,
+	}
+
+	if err := d.extractRootfs(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(target)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("unexpected mode of host directory, expected=%v, got=%v\n", os.FileMode(0755), info.Mode().Perm())
+	}
+
+	if _, err := os.Stat(filepath.Join(target, "passwd")); err == nil {
+		t.Errorf("file was unpacked outside of the rootfs\n")
+	}
+
+	if _, err := os.Stat(filepath.Join(host, "escape")); err == nil {
+		t.Errorf("file was unpacked outside of the rootfs\n")
+	}
+
+	// The directory replaces the symbolic link, as it would with tar.
+	if info, err = os.Lstat(filepath.Join(rootfs, "etc")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !info.IsDir() || info.Mode().Perm() != 0700 {
+		t.Errorf("unexpected mode of directory, expected=%v, got=%v\n", os.ModeDir|0700, info.Mode())
+	}
+
+	b, err := os.ReadFile(filepath.Join(rootfs, "etc", "passwd"))
+
+	if err != nil {
+		t.Fatalf("expected file to be unpacked in the rootfs: %s\n", err)
+	}
+
+	if string(b) != "root" {
+		t.Errorf("unexpected file content, expected=%q, got=%q\n", "root", string(b))
+	}
+
+	info, err = os.Lstat(filepath.Join(rootfs, "hardlink"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected hard link to the symbolic link, got=%v\n", info.Mode())
+	}
+}
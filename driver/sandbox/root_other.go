@@ -0,0 +1,27 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"os"
+
+	"djinn-ci.com/driver"
+)
+
+func openRoot(string) (*os.File, error) { return nil, supported() }
+
+func (d *Driver) mkdirAll(string) error { return supported() }
+
+func (d *Driver) create(string, os.FileMode, bool) (*os.File, error) { return nil, supported() }
+
+func (d *Driver) open(string) (*os.File, error) { return nil, supported() }
+
+func (d *Driver) mkdir(string, os.FileMode) error { return supported() }
+
+func (d *Driver) remove(string) error { return supported() }
+
+func (d *Driver) symlink(string, string) error { return supported() }
+
+func (d *Driver) link(string, string) error { return supported() }
+
+func (d *Driver) walk(string, driver.WalkFunc) error { return supported() }
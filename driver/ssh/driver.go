@@ -2,10 +2,12 @@
 package ssh
 
 import (
+	"archive/tar"
 	"context"
 	"fmt"
 	"io"
-	"path/filepath"
+	"os"
+	"path"
 	"strings"
 	"time"
 
@@ -43,6 +45,7 @@ type Driver struct {
 
 var (
 	_ runner.Driver = (*Driver)(nil)
+	_ runner.Placer = (*Driver)(nil)
 	_ driver.Config = (*Config)(nil)
 )
 
@@ -135,8 +138,9 @@ func (s *Driver) Create(c context.Context, env []string, pt runner.Passthrough,
 // that is executed on the remote machine once placed via SFTP. Before the job
 // is executed however, the environment variables given via Create are set for
 // the Driver session being used to invoke the script. The stderr, and stdout
-// streams are forwarded to the underlying io.Writer.
-func (s *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
+// streams are forwarded to the underlying io.Writer. If the given context is
+// cancelled during execution, then the script is killed.
+func (s *Driver) Execute(ctx context.Context, j *runner.Job, artifacts fs.FS) error {
 	sess, err := s.client.NewSession()
 
 	if err != nil {
@@ -145,7 +149,7 @@ func (s *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 
 	defer sess.Close()
 
-	script := strings.Replace(j.Name+".sh", " ", "-", -1)
+	script := driver.ScriptName(j)
 	buf := driver.CreateScript(j)
 
 	cli, err := sftp.NewClient(s.client)
@@ -159,6 +163,17 @@ func (s *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 
 	defer cli.Close()
 
+	var cacheKey string
+
+	if j.Cache != nil {
+		key, err := driver.RestoreCache(j.Writer, j.Cache, cacheEnv(cli))
+
+		if err != nil {
+			fmt.Fprintln(j.Writer, "cache error:", errors.Cause(err))
+		}
+		cacheKey = key
+	}
+
 	f, err := cli.Create(script)
 
 	if err != nil {
@@ -173,7 +188,9 @@ func (s *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 
 	f.Close()
 
-	for _, e := range s.env {
+	env := append(append([]string{}, s.env...), j.Env...)
+
+	for _, e := range env {
 		parts := strings.SplitN(e, "=", 2)
 
 		if len(parts) > 1 {
@@ -186,9 +203,31 @@ func (s *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 	sess.Stdout = j.Writer
 	sess.Stderr = j.Writer
 
-	if err := sess.Run("./" + script); err != nil {
-		if _, ok := err.(*ssh.ExitError); !ok {
-			return err
+	done := make(chan error, 1)
+
+	go func() {
+		done <- sess.Run("./" + script)
+	}()
+
+	select {
+	case <-ctx.Done():
+		sess.Signal(ssh.SIGKILL)
+		sess.Close()
+
+		cli.Remove(script)
+		return ctx.Err()
+	case err = <-done:
+	}
+
+	var exitErr *ssh.ExitError
+
+	if err != nil && !errors.As(err, &exitErr) {
+		return err
+	}
+
+	if exitErr == nil && cacheKey != "" {
+		if err := driver.SaveCache(j.Writer, j.Cache, cacheKey, cacheEnv(cli)); err != nil {
+			fmt.Fprintln(j.Writer, "cache error:", errors.Cause(err))
 		}
 	}
 
@@ -197,6 +236,10 @@ func (s *Driver) Execute(j *runner.Job, artifacts fs.FS) error {
 	}
 
 	cli.Remove(script)
+
+	if exitErr != nil {
+		return &runner.ExitError{Code: exitErr.ExitStatus()}
+	}
 	return nil
 }
 
@@ -207,21 +250,80 @@ func (s *Driver) Destroy() {
 	}
 }
 
-func (s *Driver) collectArtifact(w io.Writer, cli *sftp.Client, src, dst string, artifacts fs.FS) error {
-	f, err := cli.Open(src)
+// walker returns a driver.Walker for walking the files on the remote machine
+// via SFTP.
+func walker(cli *sftp.Client) driver.Walker {
+	return func(root string, fn driver.WalkFunc) error {
+		w := cli.Walk(root)
 
-	if err != nil {
-		return err
+		for w.Step() {
+			if err := w.Err(); err != nil {
+				return err
+			}
+
+			info := w.Stat()
+
+			if !info.Mode().IsRegular() {
+				continue
+			}
+
+			err := func() error {
+				f, err := cli.Open(w.Path())
+
+				if err != nil {
+					return err
+				}
+
+				defer f.Close()
+
+				return fn(w.Path(), info, f)
+			}()
+
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	}
+}
 
-	defer f.Close()
+// cacheEnv returns the driver.CacheEnv for restoring, and saving caches on the
+// remote machine via SFTP.
+func cacheEnv(cli *sftp.Client) driver.CacheEnv {
+	return driver.CacheEnv{
+		Read: func(name string) ([]byte, error) {
+			f, err := cli.Open(name)
 
-	fmt.Fprintln(w, "Collecting artifact", src, "=>", dst)
+			if err != nil {
+				return nil, err
+			}
 
-	if _, err := artifacts.Put(fs.Rename(f, dst)); err != nil {
-		return err
+			defer f.Close()
+
+			return io.ReadAll(f)
+		},
+		Extract: func(r io.Reader) error {
+			return driver.ExtractTar(r, func(hdr *tar.Header, r io.Reader) error {
+				if err := cli.MkdirAll(path.Dir(hdr.Name)); err != nil {
+					return err
+				}
+
+				f, err := cli.Create(hdr.Name)
+
+				if err != nil {
+					return err
+				}
+
+				defer f.Close()
+
+				if _, err := io.Copy(f, r); err != nil {
+					return err
+				}
+				return f.Chmod(os.FileMode(hdr.Mode).Perm())
+			})
+		},
+		Walk: walker(cli),
 	}
-	return nil
 }
 
 func (s *Driver) collectArtifacts(j *runner.Job, artifacts fs.FS) error {
@@ -239,20 +341,57 @@ func (s *Driver) collectArtifacts(j *runner.Job, artifacts fs.FS) error {
 
 	fmt.Fprintln(j.Writer)
 
-	for src, dst := range j.Artifacts {
-		matches, err := cli.Glob(src)
+	driver.CollectArtifacts(j, artifacts, walker(cli))
+	return nil
+}
 
-		if err != nil {
-			fmt.Fprintln(j.Writer, "artifact error:", err)
-			continue
-		}
+// place copies the given file from the filestore to the destination on the
+// remote machine via SFTP.
+func place(cli *sftp.Client, src, dst string, objects fs.FS) error {
+	object, err := objects.Open(src)
 
-		for _, path := range matches {
-			dst := strings.Replace(dst, "*", filepath.Base(path), -1)
+	if err != nil {
+		return err
+	}
 
-			if err := s.collectArtifact(j.Writer, cli, path, dst, artifacts); err != nil {
-				fmt.Fprintln(j.Writer, "artifact error:", err)
-			}
+	defer object.Close()
+
+	f, err := cli.Create(dst)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if err := f.Chmod(0700); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, object)
+	return err
+}
+
+// Place copies the given files from the filestore onto the remote machine
+// via SFTP, creating the parent directories of each destination.
+func (s *Driver) Place(_ context.Context, w io.Writer, pt runner.Passthrough, objects fs.FS) error {
+	cli, err := sftp.NewClient(s.client)
+
+	if err != nil {
+		return err
+	}
+
+	defer cli.Close()
+
+	for src, dst := range pt {
+		fmt.Fprintln(w, "Placing input", src, "=>", dst)
+
+		if err := cli.MkdirAll(path.Dir(dst)); err != nil {
+			return err
+		}
+
+		if err := place(cli, src, dst, objects); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -274,36 +413,11 @@ func (s *Driver) PlaceObjects(pt runner.Passthrough, objects fs.FS) error {
 	defer cli.Close()
 
 	for src, dst := range pt {
-		func(src, dst string) {
-			fmt.Fprintln(s.Writer, "Placing object", src, "=>", dst)
-
-			f, err := cli.Create(dst)
-
-			if err != nil {
-				fmt.Fprintln(s.Writer, "object error:", errors.Cause(err))
-				return
-			}
-
-			defer f.Close()
-
-			if err := f.Chmod(0700); err != nil {
-				fmt.Fprintln(s.Writer, "object error:", errors.Cause(err))
-				return
-			}
-
-			object, err := objects.Open(src)
+		fmt.Fprintln(s.Writer, "Placing object", src, "=>", dst)
 
-			if err != nil {
-				fmt.Fprintln(s.Writer, "object error:", errors.Cause(err))
-				return
-			}
-
-			defer object.Close()
-
-			if _, err := io.Copy(f, object); err != nil {
-				fmt.Fprintln(s.Writer, "object error:", errors.Cause(err))
-			}
-		}(src, dst)
+		if err := place(cli, src, dst, objects); err != nil {
+			fmt.Fprintln(s.Writer, "object error:", errors.Cause(err))
+		}
 	}
 
 	fmt.Fprintln(s.Writer)
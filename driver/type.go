@@ -12,10 +12,11 @@ type Type uint8
 
 //go:generate stringer -type Type -linecomment
 const (
-	SSH    Type = iota // ssh
-	QEMU               // qemu
-	Docker             // docker
-	OS                 // os
+	SSH     Type = iota // ssh
+	QEMU                // qemu
+	Docker              // docker
+	OS                  // os
+	Sandbox             // sandbox
 )
 
 var (
@@ -28,6 +29,7 @@ var (
 		"qemu-x86_64": QEMU,
 		"docker":      Docker,
 		"os":          OS,
+		"sandbox":     Sandbox,
 	}
 )
 
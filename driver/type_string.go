@@ -12,11 +12,12 @@ func _() {
 	_ = x[QEMU-1]
 	_ = x[Docker-2]
 	_ = x[OS-3]
+	_ = x[Sandbox-4]
 }
 
-const _Type_name = "sshqemudockeros"
+const _Type_name = "sshqemudockerossandbox"
 
-var _Type_index = [...]uint8{0, 3, 7, 13, 15}
+var _Type_index = [...]uint8{0, 3, 7, 13, 15, 22}
 
 func (i Type) String() string {
 	if i >= Type(len(_Type_index)-1) {
@@ -11,6 +11,7 @@ func Test_Type(t *testing.T) {
 		{"ssh", SSH, false},
 		{"qemu", QEMU, false},
 		{"docker", Docker, false},
+		{"sandbox", Sandbox, false},
 		{"foo", Type(0), true},
 	}
 
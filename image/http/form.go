@@ -10,6 +10,7 @@ import (
 
 	"djinn-ci.com/auth"
 	"djinn-ci.com/database"
+	"djinn-ci.com/driver"
 	"djinn-ci.com/errors"
 	"djinn-ci.com/image"
 	"djinn-ci.com/namespace"
@@ -24,6 +25,7 @@ type Form struct {
 	Namespace   namespace.Path
 	File        multipart.File
 	Name        string
+	Driver      *driver.Type
 	DownloadURL image.DownloadURL `json:"download_url" schema:"download_url"`
 }
 
@@ -31,10 +33,29 @@ func (f Form) Fields() map[string]string {
 	return map[string]string{
 		"namespace":    f.Namespace.String(),
 		"name":         f.Name,
+		"driver":       f.driverType().String(),
 		"download_url": f.DownloadURL.String(),
 	}
 }
 
+// driverType returns the driver the image is for, this defaults to QEMU if
+// no driver was given.
+func (f Form) driverType() driver.Type {
+	if f.Driver == nil {
+		return driver.QEMU
+	}
+	return *f.Driver
+}
+
+func imageDriver(_ context.Context, val any) error {
+	switch val.(driver.Type) {
+	case driver.QEMU, driver.Sandbox:
+		return nil
+	default:
+		return errors.New("images are only supported for the qemu and sandbox drivers")
+	}
+}
+
 func fileExists(_ context.Context, val any) error {
 	if val == nil {
 		return webutil.ErrFieldRequired
@@ -63,6 +84,37 @@ func fileIsQCOW2(_ context.Context, val any) error {
 	return nil
 }
 
+// fileIsRootfs checks that the given file is a tar archive, optionally
+// compressed with gzip, for use as the root filesystem of the sandbox driver.
+func fileIsRootfs(_ context.Context, val any) error {
+	if val == nil {
+		return webutil.ErrFieldRequired
+	}
+
+	f := val.(multipart.File)
+
+	buf := make([]byte, 262)
+
+	n, err := io.ReadFull(f, buf)
+
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	f.Seek(0, io.SeekStart)
+
+	buf = buf[:n]
+
+	if bytes.HasPrefix(buf, []byte{0x1f, 0x8b}) {
+		return nil
+	}
+
+	if len(buf) == 262 && bytes.Equal(buf[257:], []byte("ustar")) {
+		return nil
+	}
+	return errors.New("not a valid tar archive")
+}
+
 func validScheme(_ context.Context, val any) error {
 	url := val.(*url.URL)
 
@@ -108,10 +160,26 @@ func (f Form) Validate(ctx context.Context) error {
 	v.Add("name", f.Name, webutil.FieldMatches(reName))
 	v.Add("name", f.Name, namespace.ResourceUnique[*image.Image](image.NewStore(f.Pool), f.User, "name", f.Namespace))
 
+	typ := f.driverType()
+
+	v.Add("driver", typ, imageDriver)
+
 	if f.DownloadURL.URL == nil {
 		v.Add("file", f.File, fileExists)
-		v.Add("file", f.File, fileIsQCOW2)
+
+		switch typ {
+		case driver.QEMU:
+			v.Add("file", f.File, fileIsQCOW2)
+		case driver.Sandbox:
+			v.Add("file", f.File, fileIsRootfs)
+		}
 	} else {
+		if typ != driver.QEMU {
+			v.Add("download_url", f.DownloadURL.URL, func(context.Context, any) error {
+				return errors.New("download URL is only supported for qemu images")
+			})
+		}
+
 		v.Add("download_url", f.DownloadURL.URL, validScheme)
 		v.Add("download_url", f.DownloadURL.URL, sftpHasPassword)
 	}
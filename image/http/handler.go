@@ -7,7 +7,6 @@ import (
 
 	"djinn-ci.com/auth"
 	"djinn-ci.com/database"
-	"djinn-ci.com/driver"
 	"djinn-ci.com/errors"
 	"djinn-ci.com/image"
 	"djinn-ci.com/namespace"
@@ -120,7 +119,7 @@ func (h *Handler) Store(u *auth.User, r *http.Request) (*image.Image, *Form, err
 		User:      u,
 		Namespace: f.Namespace,
 		Name:      f.Name,
-		Driver:    driver.QEMU,
+		Driver:    f.driverType(),
 		Image:     f.File,
 	})
 
@@ -37,16 +37,20 @@ type Build struct {
 }
 
 type BuildJob struct {
-	ID         int64      `json:"id"`
-	BuildID    int64      `json:"id"`
-	Name       string     `json:"name"`
-	Commands   string     `json:"commands"`
-	Status     Status     `json:"status"`
-	Output     NullString `json:"output"`
-	CreatedAt  Time       `json:"created_at"`
-	StartedAt  NullTime   `json:"started_at"`
-	FinishedAt NullTime   `json:"finished_at"`
-	URL        URL        `json:"url"`
+	ID         int64             `json:"id"`
+	BuildID    int64             `json:"id"`
+	Parent     NullString        `json:"parent"`
+	Name       string            `json:"name"`
+	Matrix     map[string]string `json:"matrix"`
+	Needs      []string          `json:"needs"`
+	Commands   string            `json:"commands"`
+	Attempt    int               `json:"attempt"`
+	Status     Status            `json:"status"`
+	Output     NullString        `json:"output"`
+	CreatedAt  Time              `json:"created_at"`
+	StartedAt  NullTime          `json:"started_at"`
+	FinishedAt NullTime          `json:"finished_at"`
+	URL        URL               `json:"url"`
 }
 
 type Artifact struct {
@@ -90,6 +94,7 @@ const (
 	Failed                           // failed
 	Killed                           // killed
 	TimedOut                         // timed_out
+	Skipped                          // skipped
 )
 
 func (s *Status) UnmarshalJSON(p []byte) error {
@@ -114,6 +119,8 @@ func (s *Status) UnmarshalJSON(p []byte) error {
 		(*s) = Killed
 	case "timed_out":
 		(*s) = TimedOut
+	case "skipped":
+		(*s) = Skipped
 	default:
 		return errors.New("unknown build status " + str)
 	}
@@ -240,6 +247,8 @@ func (s *ManifestSource) UnmarshalYAML(unmarshal func(interface{}) error) error
 type ManifestJob struct {
 	Stage     string
 	Name      string
+	Needs     []string `yaml:",omitempty"`
+	Retry     int      `yaml:",omitempty"`
 	Commands  []string
 	Artifacts ManifestPassthrough
 }
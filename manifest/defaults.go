@@ -0,0 +1,150 @@
+package manifest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+
+	"djinn-ci.com/errors"
+	"djinn-ci.com/runner"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Defaults are the settings a namespace applies to every manifest that is
+// submitted to it. The Driver, Env, AllowFailures, and Objects are merged into
+// the manifest, taking precedence over what the manifest itself sets, so a
+// namespace can enforce them. The Before commands are executed at the start of
+// every job in the manifest.
+type Defaults struct {
+	Driver        Driver             `yaml:",omitempty"`
+	Env           []string           `yaml:",omitempty"`
+	AllowFailures []string           `yaml:"allow_failures,omitempty"`
+	Objects       runner.Passthrough `yaml:",omitempty"`
+	Before        []string           `yaml:",omitempty"`
+}
+
+var (
+	_ sql.Scanner   = (*Defaults)(nil)
+	_ driver.Valuer = (*Defaults)(nil)
+)
+
+func (d Defaults) IsZero() bool {
+	return len(d.Driver) == 0 &&
+		len(d.Env) == 0 &&
+		len(d.AllowFailures) == 0 &&
+		len(d.Objects) == 0 &&
+		len(d.Before) == 0
+}
+
+// Apply returns the given manifest with the defaults merged into it. If the
+// defaults specify a driver of a different type to the manifest, then the
+// driver of the manifest is replaced. The Before commands are prepended to the
+// commands of each job, including the always, and after failure jobs.
+func (d Defaults) Apply(m Manifest) Manifest {
+	m = m.merge(Manifest{
+		Driver:        d.Driver,
+		Env:           d.Env,
+		AllowFailures: d.AllowFailures,
+		Objects:       d.Objects,
+	})
+
+	if len(d.Before) == 0 {
+		return m
+	}
+
+	before := func(jobs []Job) []Job {
+		if jobs == nil {
+			return nil
+		}
+
+		prepended := make([]Job, 0, len(jobs))
+
+		for _, j := range jobs {
+			j.Commands = append(append([]string{}, d.Before...), j.Commands...)
+			prepended = append(prepended, j)
+		}
+		return prepended
+	}
+
+	m.Jobs = before(m.Jobs)
+	m.Always = before(m.Always)
+	m.AfterFailure = before(m.AfterFailure)
+	return m
+}
+
+// Validate checks the defaults for any problems, and returns them as Errors.
+func (d Defaults) Validate() error {
+	var errs Errors
+
+	if typ, ok := d.Driver["type"]; ok {
+		switch typ {
+		case "docker", "qemu", "ssh", "os", "sandbox":
+		default:
+			errs.add("invalid driver specified "+typ, "driver", "type")
+		}
+	}
+
+	for i, kv := range d.Env {
+		key, _, ok := strings.Cut(kv, "=")
+
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			errs.add("invalid environment variable "+kv+", expected KEY=VALUE", "env", i)
+		}
+	}
+
+	for i, cmd := range d.Before {
+		if strings.TrimSpace(cmd) == "" {
+			errs.add("before command cannot be empty", "before", i)
+		}
+	}
+	return errs.Err()
+}
+
+func (d *Defaults) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	b, err := yaml.Marshal(d)
+
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// UnmarshalText parses the defaults from the given YAML.
+func (d *Defaults) UnmarshalText(b []byte) error {
+	var tmp Defaults
+
+	if err := yaml.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+
+	(*d) = tmp
+	return nil
+}
+
+func (d *Defaults) Scan(val any) error {
+	if val == nil {
+		return nil
+	}
+
+	str, err := driver.String.ConvertValue(val)
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	s, ok := str.(string)
+
+	if !ok {
+		return errors.New("expected string value for defaults")
+	}
+	return errors.Err(d.UnmarshalText([]byte(s)))
+}
+
+func (d Defaults) Value() (driver.Value, error) {
+	return driver.Value(d.String()), nil
+}
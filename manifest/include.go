@@ -0,0 +1,388 @@
+package manifest
+
+import (
+	"io"
+	"path"
+	"strings"
+
+	"djinn-ci.com/errors"
+	"djinn-ci.com/runner"
+)
+
+// Include is a file to include in a manifest. This is either the Path to a
+// file in the same repository as the manifest, or the name of a Template that
+// is stored in the namespace of the manifest. An Include can be given as a
+// plain string, in which case it is treated as a Path.
+type Include struct {
+	Path     string `yaml:",omitempty"`
+	Template string `yaml:",omitempty"`
+}
+
+// Resolver returns the content of the given Include. The given namespace is
+// the namespace of the manifest being resolved, this is used for resolving
+// templates.
+type Resolver func(namespace string, inc Include) ([]byte, error)
+
+// MaxIncludeDepth is the maximum depth to which includes are resolved.
+const MaxIncludeDepth = 10
+
+func (i Include) String() string {
+	if i.Template != "" {
+		return "template:" + i.Template
+	}
+	return i.Path
+}
+
+func (i Include) MarshalYAML() (interface{}, error) {
+	if i.Template == "" {
+		return i.Path, nil
+	}
+
+	type tmp Include
+
+	return tmp(i), nil
+}
+
+func (i *Include) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&i.Path); err == nil {
+		return nil
+	}
+
+	type tmp Include
+
+	var t tmp
+
+	if err := unmarshal(&t); err != nil {
+		return err
+	}
+
+	(*i) = Include(t)
+	return nil
+}
+
+// CleanPath returns the cleaned path of the include relative to the root of
+// the repository. An error is returned if the path is empty, or if it is
+// outside of the repository.
+func (i Include) CleanPath() (string, error) {
+	if i.Path == "" {
+		return "", errors.New("include has no path")
+	}
+
+	p := path.Clean(strings.TrimPrefix(i.Path, "/"))
+
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return "", errors.New("include " + i.Path + " is outside of the repository")
+	}
+	return p, nil
+}
+
+func (i Include) validate() error {
+	if (i.Path == "") == (i.Template == "") {
+		return errors.New("include requires either a path or a template")
+	}
+
+	if i.Path != "" {
+		if _, err := i.CleanPath(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeWith decodes the manifest from the given reader, and resolves its
+// includes with the given Resolver.
+func DecodeWith(r io.Reader, resolve Resolver) (Manifest, error) {
+	m, err := Decode(r)
+
+	if err != nil {
+		return m, err
+	}
+	return m.Resolve(resolve)
+}
+
+// Resolve returns the manifest with its includes resolved using the given
+// Resolver. Each include is resolved, along with any includes of its own, and
+// merged in the order they are given, the manifest itself is then merged on
+// top. The returned manifest has no includes. An error is returned if the
+// includes form a cycle, or are nested deeper than MaxIncludeDepth.
+func (m Manifest) Resolve(resolve Resolver) (Manifest, error) {
+	return m.resolve(resolve, m.Namespace, nil)
+}
+
+func (m Manifest) resolve(resolve Resolver, namespace string, chain []string) (Manifest, error) {
+	if len(m.Include) == 0 {
+		return m, nil
+	}
+
+	if resolve == nil {
+		return m, errors.New("cannot resolve manifest includes")
+	}
+
+	if len(chain) >= MaxIncludeDepth {
+		return m, errors.New("includes nested too deeply " + strings.Join(chain, " -> "))
+	}
+
+	var base Manifest
+
+	for _, inc := range m.Include {
+		if err := inc.validate(); err != nil {
+			return m, err
+		}
+
+		name := inc.String()
+
+		for _, s := range chain {
+			if s == name {
+				return m, errors.New("include cycle " + strings.Join(append(chain, name), " -> "))
+			}
+		}
+
+		b, err := resolve(namespace, inc)
+
+		if err != nil {
+			return m, errors.New("include " + name + ": " + err.Error())
+		}
+
+		included, err := Unmarshal(b)
+
+		if err != nil {
+			return m, errors.New("include " + name + ": " + errors.Cause(err).Error())
+		}
+
+		next := make([]string, 0, len(chain)+1)
+		next = append(next, chain...)
+		next = append(next, name)
+
+		included, err = included.resolve(resolve, namespace, next)
+
+		if err != nil {
+			return m, err
+		}
+		base = base.merge(included)
+	}
+
+	m.Include = nil
+	return base.merge(m), nil
+}
+
+func unionStrings(a, b []string) []string {
+	set := make(map[string]struct{}, len(a)+len(b))
+	ss := make([]string, 0, len(a)+len(b))
+
+	for _, s := range append(append([]string{}, a...), b...) {
+		if _, ok := set[s]; ok {
+			continue
+		}
+		set[s] = struct{}{}
+		ss = append(ss, s)
+	}
+	return ss
+}
+
+// mergeEnv merges the two slices of environment variables. Variables are kept
+// in the order they first appear, and take the last value they are given.
+func mergeEnv(a, b []string) []string {
+	idx := make(map[string]int, len(a)+len(b))
+	env := make([]string, 0, len(a)+len(b))
+
+	for _, kv := range append(append([]string{}, a...), b...) {
+		key := kv
+
+		if i := strings.Index(kv, "="); i >= 0 {
+			key = kv[:i]
+		}
+
+		if i, ok := idx[key]; ok {
+			env[i] = kv
+			continue
+		}
+
+		idx[key] = len(env)
+		env = append(env, kv)
+	}
+	return env
+}
+
+// mergeParams merges the two slices of params. Params are kept in the order
+// they first appear, and a param in b replaces the param in a with the same
+// name.
+func mergeParams(a, b []Param) []Param {
+	idx := make(map[string]int, len(a)+len(b))
+	params := make([]Param, 0, len(a)+len(b))
+
+	for _, p := range append(append([]Param{}, a...), b...) {
+		if i, ok := idx[p.Name]; ok {
+			params[i] = p
+			continue
+		}
+
+		idx[p.Name] = len(params)
+		params = append(params, p)
+	}
+	return params
+}
+
+// mergeServices merges the two slices of services. Services are kept in the
+// order they first appear, and a service in b replaces the service in a with
+// the same name.
+func mergeServices(a, b []Service) []Service {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+
+	idx := make(map[string]int, len(a)+len(b))
+	services := make([]Service, 0, len(a)+len(b))
+
+	for _, s := range append(append([]Service{}, a...), b...) {
+		if i, ok := idx[s.Name()]; ok {
+			services[i] = s
+			continue
+		}
+
+		idx[s.Name()] = len(services)
+		services = append(services, s)
+	}
+	return services
+}
+
+// mergeJobs merges the two slices of jobs. A job in b replaces the job in a
+// with the same stage and name, all other jobs are appended. Jobs without a
+// name are always appended.
+func mergeJobs(a, b []Job) []Job {
+	idx := make(map[string]int, len(a))
+	jobs := make([]Job, 0, len(a)+len(b))
+
+	for _, j := range append(append([]Job{}, a...), b...) {
+		if j.Name == "" {
+			jobs = append(jobs, j)
+			continue
+		}
+
+		key := j.Stage + "/" + j.Name
+
+		if i, ok := idx[key]; ok {
+			jobs[i] = j
+			continue
+		}
+
+		idx[key] = len(jobs)
+		jobs = append(jobs, j)
+	}
+	return jobs
+}
+
+// merge returns the manifest with the given manifest merged on top of it.
+// Scalar values in o replace those in m if set, maps are merged by key, and
+// slices are merged such that the result is the same for the same inputs.
+func (m Manifest) merge(o Manifest) Manifest {
+	if o.Namespace != "" {
+		m.Namespace = o.Namespace
+	}
+
+	if len(o.Driver) > 0 {
+		if typ := o.Driver["type"]; typ != "" && typ != m.Driver["type"] {
+			m.Driver = nil
+		}
+
+		driver := make(Driver, len(m.Driver)+len(o.Driver))
+
+		for k, v := range m.Driver {
+			driver[k] = v
+		}
+		for k, v := range o.Driver {
+			driver[k] = v
+		}
+		m.Driver = driver
+	}
+
+	if o.Shell != "" {
+		m.Shell = o.Shell
+	}
+
+	if len(o.Objects) > 0 {
+		objects := make(runner.Passthrough, len(m.Objects)+len(o.Objects))
+
+		for k, v := range m.Objects {
+			objects[k] = v
+		}
+		for k, v := range o.Objects {
+			objects[k] = v
+		}
+		m.Objects = objects
+	}
+
+	if len(o.StageRules) > 0 {
+		rules := make(map[string]Rules, len(m.StageRules)+len(o.StageRules))
+
+		for k, v := range m.StageRules {
+			rules[k] = v
+		}
+		for k, v := range o.StageRules {
+			rules[k] = v
+		}
+		m.StageRules = rules
+	}
+
+	sources := make([]Source, 0, len(m.Sources)+len(o.Sources))
+	dirs := make(map[string]int)
+
+	for _, src := range append(append([]Source{}, m.Sources...), o.Sources...) {
+		if i, ok := dirs[src.Dir]; ok {
+			sources[i] = src
+			continue
+		}
+
+		dirs[src.Dir] = len(sources)
+		sources = append(sources, src)
+	}
+
+	if o.Timeout != 0 {
+		m.Timeout = o.Timeout
+	}
+
+	if !o.Matrix.IsZero() {
+		m.Matrix = o.Matrix
+	}
+
+	if !o.Cache.IsZero() {
+		m.Cache = o.Cache
+	}
+
+	m.Env = mergeEnv(m.Env, o.Env)
+	m.Params = mergeParams(m.Params, o.Params)
+	m.Services = mergeServices(m.Services, o.Services)
+	m.Sources = sources
+	m.Stages = unionStrings(m.Stages, o.Stages)
+	m.AllowFailures = unionStrings(m.AllowFailures, o.AllowFailures)
+	m.Jobs = mergeJobs(m.Jobs, o.Jobs)
+	m.Always = mergeJobs(m.Always, o.Always)
+	m.AfterFailure = mergeJobs(m.AfterFailure, o.AfterFailure)
+
+	// Keep empty slices nil so the merged manifest encodes the same as one
+	// that was written by hand.
+	if len(m.Env) == 0 {
+		m.Env = nil
+	}
+	if len(m.Params) == 0 {
+		m.Params = nil
+	}
+	if len(m.Sources) == 0 {
+		m.Sources = nil
+	}
+	if len(m.Stages) == 0 {
+		m.Stages = nil
+	}
+	if len(m.AllowFailures) == 0 {
+		m.AllowFailures = nil
+	}
+	if len(m.Jobs) == 0 {
+		m.Jobs = nil
+	}
+	if len(m.Always) == 0 {
+		m.Always = nil
+	}
+	if len(m.AfterFailure) == 0 {
+		m.AfterFailure = nil
+	}
+	return m
+}
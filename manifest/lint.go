@@ -0,0 +1,273 @@
+package manifest
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// locator finds the position of values in YAML source, from the nodes the
+// source is parsed into.
+type locator struct {
+	root  *yamlv3.Node
+	lines []string
+}
+
+func newLocator(b []byte) locator {
+	loc := locator{
+		lines: strings.Split(string(b), "\n"),
+	}
+
+	var doc yamlv3.Node
+
+	// Source that cannot be parsed is reported when decoded, so there is
+	// nothing to locate values in.
+	if err := yamlv3.Unmarshal(b, &doc); err == nil && len(doc.Content) > 0 {
+		loc.root = doc.Content[0]
+	}
+	return loc
+}
+
+// find returns the line, and column of the value at the given path. The path
+// is made up of mapping keys, and sequence indexes. If the full path cannot be
+// found then the position of the deepest value found is returned, or zero if
+// nothing was found.
+func (loc locator) find(path []interface{}) (int, int) {
+	var line, col int
+
+	n := loc.root
+
+	for _, p := range path {
+		for n != nil && n.Kind == yamlv3.AliasNode {
+			n = n.Alias
+		}
+
+		if n == nil {
+			break
+		}
+
+		var next *yamlv3.Node
+
+		switch v := p.(type) {
+		case string:
+			if n.Kind != yamlv3.MappingNode {
+				break
+			}
+
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if key := n.Content[i]; key.Value == v {
+					line, col = key.Line, key.Column
+					next = n.Content[i+1]
+					break
+				}
+			}
+		case int:
+			if n.Kind != yamlv3.SequenceNode || v < 0 || v >= len(n.Content) {
+				break
+			}
+
+			next = n.Content[v]
+			line, col = next.Line, next.Column
+		}
+		n = next
+	}
+	return line, col
+}
+
+var (
+	reYAMLError    = regexp.MustCompile("^(?:yaml: )?line ([0-9]+): (.*)$")
+	reUnknownField = regexp.MustCompile("^field (.+) not found in type .+$")
+)
+
+func (loc locator) yamlError(msg string) *Error {
+	e := &Error{Msg: strings.TrimPrefix(msg, "yaml: ")}
+
+	parts := reYAMLError.FindStringSubmatch(msg)
+
+	if parts == nil {
+		return e
+	}
+
+	e.Line, _ = strconv.Atoi(parts[1])
+	e.Msg = parts[2]
+
+	if parts := reUnknownField.FindStringSubmatch(e.Msg); parts != nil {
+		e.Msg = "unknown key " + parts[1]
+	}
+
+	if e.Line > 0 && e.Line <= len(loc.lines) {
+		s := loc.lines[e.Line-1]
+		e.Col = len(s) - len(strings.TrimLeft(s, " ")) + 1
+	}
+	return e
+}
+
+// lintSources checks each source string in the manifest is in the format of,
+//
+//	[url] [ref] => [dir]
+//
+// this cannot be checked once decoded, since the whitespace that separates
+// each part is discarded.
+func lintSources(b []byte, errs *Errors) {
+	var tmp struct {
+		Sources []interface{}
+	}
+
+	if err := yaml.Unmarshal(b, &tmp); err != nil {
+		return
+	}
+
+	for i, v := range tmp.Sources {
+		s, ok := v.(string)
+
+		if !ok {
+			errs.add("source must be a string", "sources", i)
+			continue
+		}
+
+		parts := strings.SplitN(s, "=>", 2)
+
+		if n := len(strings.Fields(parts[0])); n == 0 || n > 2 {
+			errs.add("malformed source "+s+", expected [url] [ref] => [dir]", "sources", i)
+			continue
+		}
+
+		if len(parts) > 1 && len(strings.Fields(parts[1])) != 1 {
+			errs.add("malformed source "+s+", expected [url] [ref] => [dir]", "sources", i)
+		}
+	}
+}
+
+// remap returns the given path into the resolved manifest as a path into the
+// local manifest that was resolved. This is needed for paths into sequences,
+// since the values from included files are merged into them. If the value is
+// not in the local manifest then false is returned.
+func remap(path []interface{}, local, resolved *Manifest) ([]interface{}, bool) {
+	if len(local.Include) == 0 || len(path) < 2 {
+		return path, true
+	}
+
+	sect, _ := path[0].(string)
+	idx, ok := path[1].(int)
+
+	if !ok {
+		return path, true
+	}
+
+	var from, to reflect.Value
+
+	switch sect {
+	case "jobs":
+		from, to = reflect.ValueOf(resolved.Jobs), reflect.ValueOf(local.Jobs)
+	case AlwaysStage:
+		from, to = reflect.ValueOf(resolved.Always), reflect.ValueOf(local.Always)
+	case AfterFailureStage:
+		from, to = reflect.ValueOf(resolved.AfterFailure), reflect.ValueOf(local.AfterFailure)
+	case "sources":
+		from, to = reflect.ValueOf(resolved.Sources), reflect.ValueOf(local.Sources)
+	case "stages":
+		from, to = reflect.ValueOf(resolved.Stages), reflect.ValueOf(local.Stages)
+	case "allow_failures":
+		from, to = reflect.ValueOf(resolved.AllowFailures), reflect.ValueOf(local.AllowFailures)
+	default:
+		return path, true
+	}
+
+	if idx >= from.Len() {
+		return nil, false
+	}
+
+	val := from.Index(idx).Interface()
+
+	for i := 0; i < to.Len(); i++ {
+		if reflect.DeepEqual(to.Index(i).Interface(), val) {
+			remapped := make([]interface{}, len(path))
+			copy(remapped, path)
+			remapped[1] = i
+
+			return remapped, true
+		}
+	}
+	return nil, false
+}
+
+// Lint decodes the manifest in the given source and returns every problem
+// found with it, ordered by their position in the source. Unknown keys, and
+// values of the wrong type are reported along with the problems reported by
+// Validate. Includes are resolved with the given Resolver before the manifest
+// is validated, problems with values from included files are reported without
+// a position.
+func Lint(b []byte, resolve Resolver) Errors {
+	var (
+		errs Errors
+		m    Manifest
+	)
+
+	loc := newLocator(b)
+
+	if err := yaml.UnmarshalStrict(b, &m); err != nil {
+		terr, ok := err.(*yaml.TypeError)
+
+		if !ok {
+			return append(errs, loc.yamlError(err.Error()))
+		}
+
+		for _, msg := range terr.Errors {
+			errs = append(errs, loc.yamlError(msg))
+		}
+	}
+
+	lintSources(b, &errs)
+
+	local := m
+
+	if len(m.Include) > 0 {
+		var err error
+
+		m.checkIncludes(&errs)
+
+		if m, err = m.Resolve(resolve); err != nil {
+			errs.add(err.Error(), "include")
+
+			// The local manifest is still validated, without the
+			// includes since they have already been checked.
+			local.Include = nil
+			m = local
+		}
+	}
+
+	for _, err := range m.validate() {
+		path, ok := remap(err.path, &local, &m)
+
+		if !ok {
+			path = nil
+		}
+
+		err.path = path
+		errs = append(errs, err)
+	}
+
+	for _, err := range errs {
+		if err.path != nil {
+			err.Line, err.Col = loc.find(err.path)
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, b := errs[i], errs[j]
+
+		if a.Line == 0 || b.Line == 0 {
+			return a.Line != 0 && b.Line == 0
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+	return errs
+}
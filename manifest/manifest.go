@@ -6,7 +6,12 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"djinn-ci.com/errors"
 	"djinn-ci.com/runner"
@@ -14,21 +19,106 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+var (
+	reSlug = regexp.MustCompile("[^a-zA-Z0-9.]")
+	reDup  = regexp.MustCompile("-{2,}")
+)
+
+// Slug returns the slugified version of the given job name.
+func Slug(name string) string {
+	name = strings.TrimSpace(name)
+	name = reSlug.ReplaceAllString(name, "-")
+	name = reDup.ReplaceAllString(name, "-")
+
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(name, "-"), "-"))
+}
+
 // Driver is used for the driver block in the manifest YAML.
 type Driver map[string]string
 
+// Duration is used for the timeouts in the manifest YAML. This is specified as
+// a string that is understood by time.ParseDuration, for example "1h30m".
+type Duration time.Duration
+
 // Manifest is the type that represents a manifest for a build. This details the
 // driver to use, variables to set, objects to place, VCS repositories to clone
 // and the actual commands to run and in what order.
 type Manifest struct {
-	Namespace     string             `yaml:",omitempty"`
-	Driver        Driver             `yaml:",omitempty"`
-	Env           []string           `yaml:",omitempty"`
-	Objects       runner.Passthrough `yaml:",omitempty"`
-	Sources       []Source           `yaml:",omitempty"`
-	Stages        []string           `yaml:",omitempty"`
-	AllowFailures []string           `yaml:"allow_failures,omitempty"`
-	Jobs          []Job              `yaml:",omitempty"`
+	// Include is the files merged into the manifest when it is resolved.
+	Include []Include `yaml:",omitempty"`
+
+	Namespace string `yaml:",omitempty"`
+	Driver    Driver `yaml:",omitempty"`
+
+	// Shell is the shell jobs are executed with, unless a job has a shell of
+	// its own.
+	Shell string `yaml:",omitempty"`
+
+	Env []string `yaml:",omitempty"`
+
+	// Params are given values when the build is submitted.
+	Params []Param `yaml:",omitempty"`
+
+	Objects runner.Passthrough `yaml:",omitempty"`
+	Sources []Source           `yaml:",omitempty"`
+
+	// Services are run alongside the jobs when using the docker driver.
+	Services []Service `yaml:",omitempty"`
+
+	Stages        []string         `yaml:",omitempty"`
+	AllowFailures []string         `yaml:"allow_failures,omitempty"`
+	StageRules    map[string]Rules `yaml:"stage_rules,omitempty"`
+	Timeout       Duration         `yaml:",omitempty"`
+	Matrix        Matrix           `yaml:",omitempty"`
+
+	// Cache is restored before each job is executed, and saved after each
+	// job passes.
+	Cache Cache `yaml:",omitempty"`
+
+	Jobs []Job `yaml:",omitempty"`
+
+	// Always is the jobs run once every stage has finished, regardless of the
+	// outcome of the build.
+	Always []Job `yaml:",omitempty"`
+
+	// AfterFailure is the jobs run once every stage has finished, only if the
+	// build failed, was killed, or timed out.
+	AfterFailure []Job `yaml:"after_failure,omitempty"`
+
+	// src is the text the manifest was unmarshalled from, if any. This is
+	// used for reporting the position of problems found in the manifest.
+	src []byte
+}
+
+const (
+	AlwaysStage       = "always"        // AlwaysStage is the name of the stage for the always jobs.
+	AfterFailureStage = "after_failure" // AfterFailureStage is the name of the stage for the after failure jobs.
+)
+
+// Cache is the type that represents the cache block in a manifest. The Key is
+// a template that is resolved in the environment of each job, see
+// runner.Cache for what is available to the template. The Paths are relative
+// to the directory the build is executed in.
+//
+// A cache is shared by every build in the namespace, or by every build of the
+// user outside of a namespace, regardless of the branch being built. Any of
+// these builds can overwrite the cache, so the Key should include the branch,
+// for example {{ .Branch }}, if caches should not be shared between branches.
+type Cache struct {
+	Key   string   `yaml:",omitempty"`
+	Paths []string `yaml:",omitempty"`
+}
+
+func (c Cache) IsZero() bool { return c.Key == "" && len(c.Paths) == 0 }
+
+// Matrix is the type that represents the matrix block in a manifest. Each job
+// in the manifest is expanded into a job for every combination of the values
+// in Vars. Combinations matching an entry in Exclude are dropped, and each
+// entry in Include is added as a combination of its own.
+type Matrix struct {
+	Vars    map[string][]string `yaml:",inline"`
+	Exclude []map[string]string `yaml:",omitempty"`
+	Include []map[string]string `yaml:",omitempty"`
 }
 
 // Source is the type that represents a VCS repository in a manifest.
@@ -40,15 +130,51 @@ type Source struct {
 
 // Job is the type that represents a single job to be executed in a build.
 type Job struct {
-	Stage     string             `yaml:",omitempty"`
-	Name      string             `yaml:",omitempty"`
+	Stage string `yaml:",omitempty"`
+	Name  string `yaml:",omitempty"`
+
+	// Needs is the names of the jobs that must pass before this job can be
+	// executed, these can be jobs in the same stage, or in an earlier stage.
+	Needs []string `yaml:",omitempty"`
+
+	// Timeout is the maximum amount of time the job can run for before it is
+	// timed out.
+	Timeout Duration `yaml:",omitempty"`
+
+	// Retry is how many times the job is attempted if it fails.
+	Retry Retry `yaml:",omitempty"`
+
+	// Env is the environment variables set for the job only.
+	Env []string `yaml:",omitempty"`
+
+	// Workdir is the directory the commands are executed in, relative to the
+	// directory the build is executed in if not absolute.
+	Workdir string `yaml:",omitempty"`
+
+	// Shell is the shell the commands are executed with.
+	Shell string `yaml:",omitempty"`
+
+	// Quiet turns off the echoing of each command.
+	Quiet bool `yaml:",omitempty"`
+
 	Commands  []string           `yaml:",omitempty"`
 	Artifacts runner.Passthrough `yaml:",omitempty"`
+
+	// Inputs are the artifacts of earlier jobs to place before the job is
+	// executed.
+	Inputs runner.Passthrough `yaml:",omitempty"`
+
+	// Rules determine whether the job is executed for a build at all, and
+	// whether it has to be approved before it is executed.
+	Rules `yaml:",inline"`
 }
 
 var (
 	_ sql.Scanner   = (*Driver)(nil)
 	_ driver.Valuer = (*Driver)(nil)
+
+	_ sql.Scanner   = (*Retry)(nil)
+	_ driver.Valuer = (*Retry)(nil)
 )
 
 func base(s string) string {
@@ -105,6 +231,123 @@ func (d *Driver) String() string {
 	return buf.String()
 }
 
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	dur, err := time.ParseDuration(s)
+
+	if err != nil {
+		return err
+	}
+
+	if dur < 0 {
+		return errors.New("duration cannot be negative " + s)
+	}
+
+	(*d) = Duration(dur)
+	return nil
+}
+
+// Retry is the configuration for retrying a failed job. This can either be
+// given as an integer for the maximum number of attempts, or as a block
+// specifying the maximum number of attempts, along with the exit codes, and
+// output patterns that would count as retryable. If no exit codes or output
+// patterns are given, then any failure is retryable.
+type Retry struct {
+	Max       int      `yaml:",omitempty" json:"max"`
+	ExitCodes []int    `yaml:"exit_codes,omitempty" json:"exit_codes"`
+	Output    []string `yaml:",omitempty" json:"output"`
+}
+
+// IsZero reports whether the job has no retries configured. This is used by
+// the YAML encoder to omit empty retry configuration.
+func (r Retry) IsZero() bool {
+	return r.Max == 0 && len(r.ExitCodes) == 0 && len(r.Output) == 0
+}
+
+func (r *Retry) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&r.Max); err == nil {
+		return nil
+	}
+
+	type tmp Retry
+
+	var t tmp
+
+	if err := unmarshal(&t); err != nil {
+		return err
+	}
+
+	(*r) = Retry(t)
+	return nil
+}
+
+func (r Retry) Value() (driver.Value, error) {
+	b, err := json.Marshal(r)
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return driver.Value(string(b)), nil
+}
+
+func (r *Retry) Scan(val interface{}) error {
+	v, err := driver.String.ConvertValue(val)
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	b, ok := v.([]byte)
+
+	if !ok {
+		return errors.New("manifest: could not type assert Retry to byte slice")
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(b, r); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
+// Patterns compiles the output patterns of the retry configuration.
+func (r Retry) Patterns() ([]*regexp.Regexp, error) {
+	pats := make([]*regexp.Regexp, 0, len(r.Output))
+
+	for _, s := range r.Output {
+		re, err := regexp.Compile(s)
+
+		if err != nil {
+			return nil, err
+		}
+		pats = append(pats, re)
+	}
+	return pats, nil
+}
+
+// Cap returns the duration capped to the given maximum. If the duration is
+// zero then the maximum is returned.
+func (d Duration) Cap(max time.Duration) time.Duration {
+	dur := time.Duration(d)
+
+	if dur == 0 || (max > 0 && dur > max) {
+		return max
+	}
+	return dur
+}
+
 func (m *Manifest) Scan(val interface{}) error {
 	if val == nil {
 		return nil
@@ -148,28 +391,49 @@ func (m *Manifest) String() string {
 
 func (m *Manifest) UnmarshalText(b []byte) error {
 	tmp := struct {
+		Include       []Include          `yaml:",omitempty"`
 		Namespace     string             `yaml:",omitempty"`
 		Driver        map[string]string  `yaml:",omitempty"`
+		Shell         string             `yaml:",omitempty"`
 		Env           []string           `yaml:",omitempty"`
+		Params        []Param            `yaml:",omitempty"`
 		Objects       runner.Passthrough `yaml:",omitempty"`
 		Sources       []Source           `yaml:",omitempty"`
+		Services      []Service          `yaml:",omitempty"`
 		Stages        []string           `yaml:",omitempty"`
 		AllowFailures []string           `yaml:"allow_failures,omitempty"`
+		StageRules    map[string]Rules   `yaml:"stage_rules,omitempty"`
+		Timeout       Duration           `yaml:",omitempty"`
+		Matrix        Matrix             `yaml:",omitempty"`
+		Cache         Cache              `yaml:",omitempty"`
 		Jobs          []Job              `yaml:",omitempty"`
+		Always        []Job              `yaml:",omitempty"`
+		AfterFailure  []Job              `yaml:"after_failure,omitempty"`
 	}{}
 
 	if err := yaml.Unmarshal(b, &tmp); err != nil {
 		return err
 	}
 
+	m.src = b
+	m.Include = tmp.Include
 	m.Namespace = tmp.Namespace
 	m.Driver = tmp.Driver
+	m.Shell = tmp.Shell
 	m.Env = tmp.Env
+	m.Params = tmp.Params
 	m.Objects = tmp.Objects
 	m.Sources = tmp.Sources
+	m.Services = tmp.Services
 	m.Stages = tmp.Stages
 	m.AllowFailures = tmp.AllowFailures
+	m.StageRules = tmp.StageRules
+	m.Timeout = tmp.Timeout
+	m.Matrix = tmp.Matrix
+	m.Cache = tmp.Cache
 	m.Jobs = tmp.Jobs
+	m.Always = tmp.Always
+	m.AfterFailure = tmp.AfterFailure
 
 	if m.Driver["type"] == "qemu" {
 		// Set the default arch for now.
@@ -187,29 +451,210 @@ func (m *Manifest) UnmarshalJSON(b []byte) error {
 	return m.UnmarshalText([]byte(s))
 }
 
-func (m *Manifest) Validate() error {
-	switch m.Driver["type"] {
-	case "docker":
-		if m.Driver["image"] == "" {
-			return errors.New("driver docker requies image")
+// jobNames returns the names of the jobs in the manifest, in the order in
+// which they are declared. Jobs without a name are given one derived from
+// their stage in the same way as runner.Stage.
+func (m *Manifest) jobNames() []string {
+	names := make([]string, 0, len(m.Jobs))
+	counts := make(map[string]int)
+
+	for _, j := range m.Jobs {
+		counts[j.Stage]++
+
+		name := j.Name
+
+		if name == "" {
+			name = j.Stage + "." + strconv.Itoa(counts[j.Stage])
 		}
-		if m.Driver["workspace"] == "" {
-			return errors.New("driver docker requires workspace")
+		names = append(names, name)
+	}
+	return names
+}
+
+// JobShell returns the shell the given job is executed with, this is the shell
+// of the job, falling back to the shell of the manifest.
+func (m *Manifest) JobShell(j Job) string {
+	if j.Shell != "" {
+		return j.Shell
+	}
+	return m.Shell
+}
+
+// JobManual reports whether the given job has to be approved before it is
+// executed, either because the job is manual, or because its stage is.
+func (m *Manifest) JobManual(j Job) bool {
+	return j.Manual() || m.StageRules[j.Stage].Manual()
+}
+
+// PostJobs returns the always, and after failure jobs with their stage set to
+// AlwaysStage, and AfterFailureStage respectively. Jobs without a name are
+// named after their stage and position.
+func (m *Manifest) PostJobs() []Job {
+	jobs := make([]Job, 0, len(m.Always)+len(m.AfterFailure))
+
+	sections := []struct {
+		stage string
+		jobs  []Job
+	}{
+		{AlwaysStage, m.Always},
+		{AfterFailureStage, m.AfterFailure},
+	}
+
+	for _, sect := range sections {
+		for i, j := range sect.jobs {
+			j.Stage = sect.stage
+
+			if j.Name == "" {
+				j.Name = sect.stage + "." + strconv.Itoa(i+1)
+			}
+			jobs = append(jobs, j)
 		}
-	case "qemu":
-		if m.Driver["image"] == "" {
-			return errors.New("driver qemu requires image")
+	}
+	return jobs
+}
+
+// IsZero reports whether the matrix has no combinations to expand into. This
+// is also used by the YAML encoder to omit an empty matrix.
+func (m Matrix) IsZero() bool {
+	return len(m.Vars) == 0 && len(m.Include) == 0
+}
+
+func matchVars(vars, match map[string]string) bool {
+	for k, v := range match {
+		if vars[k] != v {
+			return false
 		}
-	case "ssh":
-		if m.Driver["address"] == "" {
-			return errors.New("driver ssh requires address")
+	}
+	return true
+}
+
+// Combinations returns every combination of the values in the matrix. The
+// keys are expanded in alphabetical order so the returned combinations are
+// always in the same order for the same matrix.
+func (m Matrix) Combinations() []map[string]string {
+	if m.IsZero() {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m.Vars))
+
+	for k := range m.Vars {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	combos := make([]map[string]string, 0)
+
+	if len(keys) > 0 {
+		combos = append(combos, map[string]string{})
+	}
+
+	for _, k := range keys {
+		next := make([]map[string]string, 0, len(combos)*len(m.Vars[k]))
+
+		for _, combo := range combos {
+			for _, v := range m.Vars[k] {
+				vars := make(map[string]string, len(combo)+1)
+
+				for k, v := range combo {
+					vars[k] = v
+				}
+				vars[k] = v
+
+				next = append(next, vars)
+			}
 		}
-	case "os":
-		break
-	default:
-		return errors.New("invalid driver specified " + m.Driver["type"])
+		combos = next
 	}
-	return nil
+
+	filtered := combos[:0]
+
+outer:
+	for _, combo := range combos {
+		for _, exclude := range m.Exclude {
+			if matchVars(combo, exclude) {
+				continue outer
+			}
+		}
+		filtered = append(filtered, combo)
+	}
+
+	combos = filtered
+
+outerInclude:
+	for _, include := range m.Include {
+		for _, combo := range combos {
+			if len(combo) == len(include) && matchVars(combo, include) {
+				continue outerInclude
+			}
+		}
+		combos = append(combos, include)
+	}
+	return combos
+}
+
+// MatrixName returns the name of the job with the given name expanded for the
+// given matrix combination.
+func MatrixName(name string, vars map[string]string) string {
+	keys := make([]string, 0, len(vars))
+
+	for k := range vars {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys)+1)
+	parts = append(parts, name)
+
+	for _, k := range keys {
+		parts = append(parts, k+"="+vars[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+// MatrixArtifact returns the name of the given artifact suffixed with the
+// given matrix combination, this ensures each expanded job collects its
+// artifacts under a unique name.
+func MatrixArtifact(name string, vars map[string]string) string {
+	ext := filepath.Ext(name)
+	suffix := Slug(MatrixName("", vars))
+
+	return strings.TrimSuffix(name, ext) + "-" + suffix + ext
+}
+
+// MatrixArtifacts returns the given artifacts with the destination of each
+// suffixed with the given matrix combination via MatrixArtifact. Destinations
+// that are patterns are left as is. If there is no combination then the
+// artifacts are returned as is.
+func MatrixArtifacts(artifacts runner.Passthrough, vars map[string]string) runner.Passthrough {
+	if len(vars) == 0 {
+		return artifacts
+	}
+
+	pt := make(runner.Passthrough, len(artifacts))
+
+	for src, dst := range artifacts {
+		if !strings.Contains(dst, "*") {
+			dst = MatrixArtifact(dst, vars)
+		}
+		pt[src] = dst
+	}
+	return pt
+}
+
+// MatrixEnv returns the given matrix combination as a slice of environment
+// variables.
+func MatrixEnv(vars map[string]string) []string {
+	env := make([]string, 0, len(vars))
+
+	for k, v := range vars {
+		env = append(env, k+"="+v)
+	}
+
+	sort.Strings(env)
+	return env
 }
 
 func (m Manifest) Value() (driver.Value, error) {
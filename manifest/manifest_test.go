@@ -3,8 +3,10 @@ package manifest
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"djinn-ci.com/errors"
+	"djinn-ci.com/runner"
 )
 
 func unmarshal(s string) func(interface{}) error {
@@ -70,3 +72,1045 @@ func Test_ManifestSource(t *testing.T) {
 		}
 	}
 }
+
+func Test_ManifestValidateNeeds(t *testing.T) {
+	tests := []struct {
+		manifest string
+		fails    bool
+	}{
+		{`driver:
+  type: os
+stages: [build, test]
+jobs:
+- stage: build
+  name: compile
+- stage: test
+  name: unit
+  needs: [compile]
+- stage: test
+  name: integration
+  needs: [compile, unit]`, false},
+		{`driver:
+  type: os
+stages: [build]
+jobs:
+- stage: build
+  needs: [build.2]
+- stage: build`, false},
+		{`driver:
+  type: os
+stages: [build]
+jobs:
+- stage: build
+  name: compile
+  needs: [lint]`, true},
+		{`driver:
+  type: os
+stages: [build, test]
+jobs:
+- stage: build
+  name: compile
+  needs: [unit]
+- stage: test
+  name: unit`, true},
+		{`driver:
+  type: os
+stages: [test]
+jobs:
+- stage: test
+  name: a
+  needs: [c]
+- stage: test
+  name: b
+  needs: [a]
+- stage: test
+  name: c
+  needs: [b]`, true},
+		{`driver:
+  type: os
+stages: [test]
+jobs:
+- stage: test
+  name: a
+  needs: [a]`, true},
+	}
+
+	for i, test := range tests {
+		m, err := Unmarshal([]byte(test.manifest))
+
+		if err != nil {
+			t.Fatalf("tests[%d] - %s\n", i, err)
+		}
+
+		err = m.Validate()
+
+		if test.fails && err == nil {
+			t.Errorf("tests[%d] - expected validation to fail\n", i)
+		}
+		if !test.fails && err != nil {
+			t.Errorf("tests[%d] - unexpected error: %s\n", i, err)
+		}
+	}
+}
+
+func Test_MatrixCombinations(t *testing.T) {
+	m, err := Unmarshal([]byte(`driver:
+  type: os
+matrix:
+  go: [1.20, 1.21]
+  db: [pg14, pg15]
+  exclude:
+  - go: 1.20
+    db: pg15
+  include:
+  - go: 1.22
+    db: pg15
+  - go: 1.21
+    db: pg14`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"test db=pg14 go=1.20",
+		"test db=pg14 go=1.21",
+		"test db=pg15 go=1.21",
+		"test db=pg15 go=1.22",
+	}
+
+	combos := m.Matrix.Combinations()
+
+	if len(combos) != len(expected) {
+		t.Fatalf("unexpected number of combinations, expected=%d, got=%d\n", len(expected), len(combos))
+	}
+
+	for i, vars := range combos {
+		if name := MatrixName("test", vars); name != expected[i] {
+			t.Errorf("combos[%d] - unexpected name, expected=%q, got=%q\n", i, expected[i], name)
+		}
+	}
+
+	var zero Matrix
+
+	if combos := zero.Combinations(); combos != nil {
+		t.Errorf("expected no combinations for empty matrix, got=%v\n", combos)
+	}
+}
+
+func Test_MatrixArtifacts(t *testing.T) {
+	vars := map[string]string{"go": "1.21", "db": "pg15"}
+
+	artifacts := runner.Passthrough{
+		"bin/app":         "app",
+		"coverage.out":    "coverage.out",
+		"reports/*.xml":   "*.xml",
+		"dist/app.tar.gz": "app.tar.gz",
+	}
+
+	expected := runner.Passthrough{
+		"bin/app":         "app-db-pg15-go-1.21",
+		"coverage.out":    "coverage-db-pg15-go-1.21.out",
+		"reports/*.xml":   "*.xml",
+		"dist/app.tar.gz": "app.tar-db-pg15-go-1.21.gz",
+	}
+
+	pt := MatrixArtifacts(artifacts, vars)
+
+	for src, dst := range expected {
+		if pt[src] != dst {
+			t.Errorf("unexpected artifact for %q, expected=%q, got=%q\n", src, dst, pt[src])
+		}
+	}
+
+	if pt := MatrixArtifacts(artifacts, nil); pt["coverage.out"] != "coverage.out" {
+		t.Errorf("unexpected artifact without matrix, expected=%q, got=%q\n", "coverage.out", pt["coverage.out"])
+	}
+}
+
+func Test_ManifestTimeout(t *testing.T) {
+	m, err := Unmarshal([]byte(`driver:
+  type: os
+timeout: 1h
+jobs:
+- name: test
+  timeout: 90m`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		timeout  Duration
+		max      time.Duration
+		expected time.Duration
+	}{
+		{m.Timeout, time.Minute * 30, time.Minute * 30},
+		{m.Timeout, time.Hour * 2, time.Hour},
+		{m.Jobs[0].Timeout, time.Hour * 2, time.Minute * 90},
+		{0, time.Hour, time.Hour},
+	}
+
+	for i, test := range tests {
+		if dur := test.timeout.Cap(test.max); dur != test.expected {
+			t.Errorf("tests[%d] - unexpected timeout, expected=%s, got=%s\n", i, test.expected, dur)
+		}
+	}
+
+	if _, err := Unmarshal([]byte("timeout: -1m")); err == nil {
+		t.Errorf("expected error for negative timeout\n")
+	}
+}
+
+func Test_ManifestRules(t *testing.T) {
+	m, err := Unmarshal([]byte(`driver:
+  type: os
+stages:
+- test
+- deploy
+stage_rules:
+  deploy:
+    only:
+      triggers: [push]
+      branches: [main, release/*]
+jobs:
+- stage: test
+  name: test
+- stage: test
+  name: lint
+  except:
+    variables:
+      SKIP_LINT: "true"
+- stage: deploy
+  name: publish
+  only:
+    tags: [v*]`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ctx      Context
+		expected map[string]bool
+	}{
+		{
+			Context{Trigger: "pull", Branch: "main"},
+			map[string]bool{"test": true, "lint": true, "deploy": false, "publish": false},
+		},
+		{
+			Context{Trigger: "push", Branch: "release/1.0"},
+			map[string]bool{"test": true, "lint": true, "deploy": true, "publish": false},
+		},
+		{
+			Context{Trigger: "push", Tag: "v1.0.0", Variables: map[string]string{"SKIP_LINT": "true"}},
+			map[string]bool{"test": true, "lint": false, "deploy": false, "publish": true},
+		},
+	}
+
+	for i, test := range tests {
+		got := map[string]bool{
+			"deploy": m.StageRules["deploy"].Allow(test.ctx),
+		}
+
+		for _, j := range m.Jobs {
+			got[j.Name] = j.Rules.Allow(test.ctx)
+		}
+
+		for name, allow := range test.expected {
+			if got[name] != allow {
+				t.Errorf("tests[%d] - unexpected rule result for %q, expected=%v, got=%v\n", i, name, allow, got[name])
+			}
+		}
+	}
+
+	invalid := []string{
+		`driver:
+  type: os
+stages: [test]
+stage_rules:
+  deploy:
+    only:
+      triggers: [push]`,
+		`driver:
+  type: os
+stages: [test]
+jobs:
+- stage: test
+  only:
+    triggers: [commit]`,
+		`driver:
+  type: os
+stages: [test]
+jobs:
+- stage: test
+  except:
+    branches: ["[main"]`,
+	}
+
+	for i, src := range invalid {
+		m, err := Unmarshal([]byte(src))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := m.Validate(); err == nil {
+			t.Errorf("invalid[%d] - expected validation error\n", i)
+		}
+	}
+}
+
+func Test_ManifestResolve(t *testing.T) {
+	files := map[string]string{
+		"ci/base.yml": `driver:
+  type: docker
+  image: golang
+  workspace: /go/src
+env:
+- CGO_ENABLED=0
+- GOFLAGS=-mod=mod
+stages:
+- test
+- build
+jobs:
+- stage: test
+  name: test
+  commands:
+  - go test ./...
+- stage: build
+  name: build
+  commands:
+  - go build ./...`,
+		"ci/lint.yml": `include:
+- ci/base.yml
+stages:
+- lint
+jobs:
+- stage: lint
+  name: vet
+  commands:
+  - go vet ./...`,
+		"ci/a.yml": `include: [ci/b.yml]`,
+		"ci/b.yml": `include: [ci/a.yml]`,
+	}
+
+	templates := map[string]string{
+		"release": `stages:
+- release
+jobs:
+- stage: release
+  name: release
+  commands:
+  - ./release.sh`,
+	}
+
+	resolve := func(namespace string, inc Include) ([]byte, error) {
+		if inc.Template != "" {
+			if namespace != "acme" {
+				return nil, errors.New("unexpected namespace " + namespace)
+			}
+
+			s, ok := templates[inc.Template]
+
+			if !ok {
+				return nil, errors.New("unknown template")
+			}
+			return []byte(s), nil
+		}
+
+		path, err := inc.CleanPath()
+
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := files[path]
+
+		if !ok {
+			return nil, errors.New("file not found")
+		}
+		return []byte(s), nil
+	}
+
+	m, err := Unmarshal([]byte(`namespace: acme
+include:
+- ci/lint.yml
+- template: release
+driver:
+  image: golang:1.18
+env:
+- GOFLAGS=-mod=vendor
+- GOOS=linux
+jobs:
+- stage: test
+  name: test
+  commands:
+  - go test -race ./...`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = m.Resolve(resolve)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m.Include) != 0 {
+		t.Errorf("expected includes to be cleared, got=%v\n", m.Include)
+	}
+
+	if m.Driver["type"] != "docker" || m.Driver["image"] != "golang:1.18" {
+		t.Errorf("unexpected driver, got=%v\n", m.Driver)
+	}
+
+	expectedEnv := []string{"CGO_ENABLED=0", "GOFLAGS=-mod=vendor", "GOOS=linux"}
+
+	if !reflect.DeepEqual(m.Env, expectedEnv) {
+		t.Errorf("unexpected env, expected=%v, got=%v\n", expectedEnv, m.Env)
+	}
+
+	expectedStages := []string{"test", "build", "lint", "release"}
+
+	if !reflect.DeepEqual(m.Stages, expectedStages) {
+		t.Errorf("unexpected stages, expected=%v, got=%v\n", expectedStages, m.Stages)
+	}
+
+	expectedJobs := []string{"test", "build", "vet", "release"}
+
+	jobs := make([]string, 0, len(m.Jobs))
+
+	for _, j := range m.Jobs {
+		jobs = append(jobs, j.Name)
+	}
+
+	if !reflect.DeepEqual(jobs, expectedJobs) {
+		t.Errorf("unexpected jobs, expected=%v, got=%v\n", expectedJobs, jobs)
+	}
+
+	if cmd := m.Jobs[0].Commands[0]; cmd != "go test -race ./..." {
+		t.Errorf("expected job test to be overridden, got=%q\n", cmd)
+	}
+
+	invalid := []string{
+		"include: [ci/a.yml]",
+		"include: [../outside.yml]",
+		"include: [ci/missing.yml]",
+		"include:\n- template: release",
+	}
+
+	for i, src := range invalid {
+		m, err := Unmarshal([]byte(src))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := m.Resolve(resolve); err == nil {
+			t.Errorf("invalid[%d] - expected error resolving includes\n", i)
+		}
+	}
+}
+
+func Test_Lint(t *testing.T) {
+	src := `driver:
+  type: docker
+  image: golang
+stages:
+- test
+allow_failures:
+- lint
+sources:
+- https://github.com/djinn-ci/djinn main extra => djinn
+- git@github.com:djinn-ci/config.git
+jobs:
+- stage: test
+  name: test
+  commands:
+  - go test ./...
+- stage: build
+  name: build
+  comands:
+  - go build
+- stage: test
+  name: test`
+
+	expected := []struct {
+		line int
+		msg  string
+	}{
+		{1, "driver docker requires workspace"},
+		{7, "allow_failures has unknown stage lint"},
+		{9, "malformed source https://github.com/djinn-ci/djinn main extra => djinn, expected [url] [ref] => [dir]"},
+		{16, "job build has undeclared stage build"},
+		{18, "unknown key comands"},
+		{21, "duplicate job name test"},
+	}
+
+	errs := Lint([]byte(src), nil)
+
+	if len(errs) != len(expected) {
+		t.Fatalf("unexpected number of errors, expected=%d, got=%d\n%s\n", len(expected), len(errs), errs)
+	}
+
+	for i, err := range errs {
+		if err.Line != expected[i].line {
+			t.Errorf("errs[%d] - unexpected line, expected=%d, got=%d\n", i, expected[i].line, err.Line)
+		}
+		if err.Msg != expected[i].msg {
+			t.Errorf("errs[%d] - unexpected message, expected=%q, got=%q\n", i, expected[i].msg, err.Msg)
+		}
+	}
+
+	if errs := Lint([]byte("driver:\n  type: os\n"), nil); len(errs) != 0 {
+		t.Errorf("expected no errors, got=%s\n", errs)
+	}
+
+	// Values in flow style collections are located exactly.
+	errs = Lint([]byte("driver: {type: os}\nstages: [test]\nallow_failures: [test, lint]\n"), nil)
+
+	if len(errs) != 1 {
+		t.Fatalf("unexpected number of errors, expected=%d, got=%d\n%s\n", 1, len(errs), errs)
+	}
+
+	if errs[0].Line != 3 || errs[0].Col != 24 {
+		t.Errorf("unexpected position, expected=%d:%d, got=%d:%d\n", 3, 24, errs[0].Line, errs[0].Col)
+	}
+
+	if errs := Lint([]byte("driver:\n  type: os\nsources:\n- github.com/djinn-ci/djinn\n"), nil); len(errs) != 0 {
+		t.Errorf("expected no errors, got=%s\n", errs)
+	}
+
+	// The local manifest is still validated if its includes cannot be
+	// resolved.
+	errs = Lint([]byte("include: [ci/base.yml]\ndriver:\n  type: qemu\n"), nil)
+
+	expectedMsgs := []string{
+		"cannot resolve manifest includes",
+		"driver qemu requires image",
+	}
+
+	if len(errs) != len(expectedMsgs) {
+		t.Fatalf("unexpected number of errors, expected=%d, got=%d\n%s\n", len(expectedMsgs), len(errs), errs)
+	}
+
+	for i, err := range errs {
+		if err.Msg != expectedMsgs[i] {
+			t.Errorf("errs[%d] - unexpected message, expected=%q, got=%q\n", i, expectedMsgs[i], err.Msg)
+		}
+	}
+}
+
+func Test_ManifestCache(t *testing.T) {
+	src := `driver:
+  type: os
+cache:
+  key: go-{{ .Branch
+  paths:
+  - vendor
+  - /root/go/pkg/mod
+  - ../node_modules`
+
+	expected := []string{
+		"invalid cache key: key:1: unclosed action",
+		"cache path must be relative /root/go/pkg/mod",
+		"cache path must be relative ../node_modules",
+	}
+
+	errs := Lint([]byte(src), nil)
+
+	if len(errs) != len(expected) {
+		t.Fatalf("unexpected number of errors, expected=%d, got=%d\n%s\n", len(expected), len(errs), errs)
+	}
+
+	for i, err := range errs {
+		if err.Msg != expected[i] {
+			t.Errorf("errs[%d] - unexpected message, expected=%q, got=%q\n", i, expected[i], err.Msg)
+		}
+	}
+}
+
+func Test_ManifestInputs(t *testing.T) {
+	src := `driver:
+  type: os
+stages:
+- build
+- package
+jobs:
+- stage: build
+  name: compile
+  commands:
+  - go build -o djinn
+  artifacts:
+  - djinn
+  - coverage/ => coverage.tar.gz
+- stage: package
+  name: package
+  commands:
+  - tar -czf djinn.tar.gz bin/djinn
+  inputs:
+  - djinn => bin/djinn
+  - coverage.tar.gz
+  - report.txt
+- stage: build
+  name: vet
+  commands:
+  - go vet ./...
+  inputs:
+  - djinn`
+
+	expected := []string{
+		"job package input report.txt is not an artifact of an earlier job",
+		"job vet input djinn is not an artifact of an earlier job",
+	}
+
+	errs := Lint([]byte(src), nil)
+
+	if len(errs) != len(expected) {
+		t.Fatalf("unexpected number of errors, expected=%d, got=%d\n%s\n", len(expected), len(errs), errs)
+	}
+
+	for i, err := range errs {
+		if err.Msg != expected[i] {
+			t.Errorf("errs[%d] - unexpected message, expected=%q, got=%q\n", i, expected[i], err.Msg)
+		}
+	}
+}
+
+func Test_ManifestShell(t *testing.T) {
+	src := `driver:
+  type: os
+shell: bash
+stages:
+- test
+jobs:
+- stage: test
+  name: test
+  shell: zsh
+  commands:
+  - go test ./...
+- stage: test
+  name: lint
+  commands:
+  - go vet ./...`
+
+	m, err := Unmarshal([]byte(src))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if shell := m.JobShell(m.Jobs[1]); shell != "bash" {
+		t.Errorf("unexpected shell for job lint, expected=%q, got=%q\n", "bash", shell)
+	}
+
+	errs := Lint([]byte(src), nil)
+
+	if len(errs) != 1 {
+		t.Fatalf("unexpected number of errors, expected=1, got=%d\n%s\n", len(errs), errs)
+	}
+
+	if errs[0].Line != 9 {
+		t.Errorf("unexpected line, expected=9, got=%d\n", errs[0].Line)
+	}
+}
+
+func Test_ManifestManual(t *testing.T) {
+	m, err := Unmarshal([]byte(`driver:
+  type: os
+stages:
+- test
+- deploy
+stage_rules:
+  deploy:
+    when: manual
+jobs:
+- stage: test
+  name: test
+- stage: test
+  name: bench
+  when: manual
+- stage: deploy
+  name: publish`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]bool{"test": false, "bench": true, "publish": true}
+
+	for _, j := range m.Jobs {
+		if manual := m.JobManual(j); manual != expected[j.Name] {
+			t.Errorf("unexpected manual for job %q, expected=%v, got=%v\n", j.Name, expected[j.Name], manual)
+		}
+	}
+
+	invalid := []string{
+		`driver:
+  type: os
+stages: [test]
+jobs:
+- stage: test
+  when: later`,
+		`driver:
+  type: os
+stages: [test]
+always:
+- name: cleanup
+  when: manual`,
+	}
+
+	for i, src := range invalid {
+		m, err := Unmarshal([]byte(src))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := m.Validate(); err == nil {
+			t.Errorf("invalid[%d] - expected validation error\n", i)
+		}
+	}
+}
+
+func Test_ManifestParams(t *testing.T) {
+	m, err := Unmarshal([]byte(`driver:
+  type: os
+params:
+- name: VERSION
+  description: The version to release
+- name: DRY_RUN
+  type: bool
+  default: true
+- name: CHANNEL
+  type: choice
+  default: beta
+  choices: [stable, beta]`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		vals     ParamValues
+		expected []string
+		err      bool
+	}{
+		{
+			ParamValues{"VERSION": "1.2.0"},
+			[]string{"VERSION=1.2.0", "DRY_RUN=true", "CHANNEL=beta"},
+			false,
+		},
+		{
+			ParamValues{"VERSION": "1.2.0", "DRY_RUN": "0", "CHANNEL": "stable"},
+			[]string{"VERSION=1.2.0", "DRY_RUN=false", "CHANNEL=stable"},
+			false,
+		},
+		{ParamValues{}, nil, true},
+		{ParamValues{"VERSION": "1.2.0", "DRY_RUN": "maybe"}, nil, true},
+		{ParamValues{"VERSION": "1.2.0", "CHANNEL": "nightly"}, nil, true},
+		{ParamValues{"VERSION": "1.2.0", "ARCH": "arm64"}, nil, true},
+	}
+
+	for i, test := range tests {
+		env, err := m.ParamEnv(test.vals)
+
+		if test.err {
+			if err == nil {
+				t.Errorf("tests[%d] - expected error, got env=%v\n", i, env)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("tests[%d] - unexpected error: %s\n", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(env, test.expected) {
+			t.Errorf("tests[%d] - unexpected env, expected=%v, got=%v\n", i, test.expected, env)
+		}
+	}
+
+	var vals ParamValues
+
+	if err := vals.UnmarshalJSON([]byte(`{"VERSION": "1.2.0", "DRY_RUN": false, "BUILD": 42}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := vals.String(); s != "BUILD=42\nDRY_RUN=false\nVERSION=1.2.0" {
+		t.Errorf("unexpected values from JSON, got=%q\n", s)
+	}
+
+	invalid := []string{
+		`driver:
+  type: os
+params:
+- name: 1VERSION`,
+		`driver:
+  type: os
+params:
+- name: CHANNEL
+  type: choice`,
+		`driver:
+  type: os
+params:
+- name: DRY_RUN
+  type: bool
+  default: maybe`,
+		`driver:
+  type: os
+params:
+- name: VERSION
+- name: VERSION`,
+	}
+
+	for i, src := range invalid {
+		m, err := Unmarshal([]byte(src))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := m.Validate(); err == nil {
+			t.Errorf("invalid[%d] - expected validation error\n", i)
+		}
+	}
+}
+
+func Test_DefaultsApply(t *testing.T) {
+	var d Defaults
+
+	err := d.UnmarshalText([]byte(`driver:
+  image: debian/stable
+env:
+- HTTP_PROXY=http://proxy:3128
+allow_failures: [lint]
+objects:
+- ca.crt => /usr/local/share/ca-certificates/ca.crt
+before:
+- update-ca-certificates`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Unmarshal([]byte(`driver:
+  type: qemu
+  image: alpine/edge
+env:
+- HTTP_PROXY=http://localhost
+- GOOS=linux
+stages: [lint, test]
+jobs:
+- stage: lint
+  commands: [make lint]
+- stage: test
+  commands: [make test]
+always:
+- name: notify
+  commands: [make notify]`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m = d.Apply(m)
+
+	if m.Driver["type"] != "qemu" || m.Driver["image"] != "debian/stable" {
+		t.Errorf("unexpected driver, got=%v\n", m.Driver)
+	}
+
+	env := []string{"HTTP_PROXY=http://proxy:3128", "GOOS=linux"}
+
+	if !reflect.DeepEqual(m.Env, env) {
+		t.Errorf("unexpected env, expected=%v, got=%v\n", env, m.Env)
+	}
+
+	if !reflect.DeepEqual(m.AllowFailures, []string{"lint"}) {
+		t.Errorf("unexpected allow_failures, got=%v\n", m.AllowFailures)
+	}
+
+	if m.Objects["ca.crt"] != "/usr/local/share/ca-certificates/ca.crt" {
+		t.Errorf("unexpected objects, got=%v\n", m.Objects)
+	}
+
+	for _, j := range append(m.Jobs, m.Always...) {
+		if len(j.Commands) != 2 || j.Commands[0] != "update-ca-certificates" {
+			t.Errorf("unexpected commands for job %s, got=%v\n", j.Stage, j.Commands)
+		}
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (Defaults{Driver: Driver{"type": "vm"}, Env: []string{"PROXY"}}).Validate(); err == nil {
+		t.Fatal("expected invalid defaults to fail validation")
+	}
+}
+
+func Test_ManifestDockerfile(t *testing.T) {
+	tests := []struct {
+		driver string
+		errs   int
+	}{
+		{"dockerfile: djinn/Dockerfile", 0},
+		{"dockerfile: Dockerfile\n  context: .", 0},
+		{"dockerfile: /Dockerfile", 1},
+		{"dockerfile: ../Dockerfile", 1},
+		{"dockerfile: Dockerfile\n  context: ./djinn/", 1},
+		{"pull: if-not-present\n  registry_auth: registry.example.com=REGISTRY_AUTH", 0},
+		{"registry_auth: registry.example.com=REGISTRY_AUTH, ghcr.io=GHCR_AUTH", 0},
+		{"registry_auth: registry.example.com=key:registry.example.com", 0},
+		{"registry_auth: \"registry.example.com=key:\"", 1},
+		{"pull: sometimes", 1},
+		{"registry_auth: REGISTRY_AUTH", 1},
+		{"registry_auth: registry.example.com=$REGISTRY_AUTH", 1},
+	}
+
+	for i, test := range tests {
+		src := `driver:
+  type: docker
+  image: alpine/git
+  workspace: /go
+  ` + test.driver + `
+stages:
+- test
+jobs:
+- stage: test
+  commands:
+  - go test ./...`
+
+		m, err := Unmarshal([]byte(src))
+
+		if err != nil {
+			t.Fatalf("tests[%d] - %s\n", i, err)
+		}
+
+		errs := m.validate()
+
+		if len(errs) != test.errs {
+			t.Errorf("tests[%d] - unexpected number of errors, expected=%d, got=%d\n%s\n", i, test.errs, len(errs), errs)
+		}
+	}
+}
+
+func Test_ManifestServices(t *testing.T) {
+	src := `driver:
+  type: docker
+  image: golang
+  workspace: /go
+services:
+- image: docker.io/library/postgres:15
+  env:
+  - POSTGRES_PASSWORD=secret
+- image: redis:7
+  alias: cache
+stages:
+- test
+jobs:
+- stage: test
+  commands:
+  - go test ./...`
+
+	m, err := Unmarshal([]byte(src))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{"postgres", "cache"}
+
+	for i, s := range m.Services {
+		if name := s.Name(); name != names[i] {
+			t.Errorf("services[%d] - unexpected name, expected=%q, got=%q\n", i, names[i], name)
+		}
+	}
+
+	merged := m.merge(Manifest{
+		Services: []Service{
+			{Image: "postgres:16", Alias: "postgres"},
+			{Image: "mysql:8"},
+		},
+	})
+
+	if len(merged.Services) != 3 {
+		t.Fatalf("unexpected number of services, expected=3, got=%d\n", len(merged.Services))
+	}
+
+	if merged.Services[0].Image != "postgres:16" {
+		t.Errorf("unexpected service image, expected=%q, got=%q\n", "postgres:16", merged.Services[0].Image)
+	}
+
+	invalid := []string{
+		`driver:
+  type: qemu
+  image: debian/stable
+services:
+- image: redis`,
+		`driver:
+  type: docker
+  image: golang
+  workspace: /go
+services:
+- alias: db`,
+		`driver:
+  type: docker
+  image: golang
+  workspace: /go
+services:
+- image: redis:6
+- image: redis:7`,
+		`driver:
+  type: docker
+  image: golang
+  workspace: /go
+services:
+- image: postgres
+  env:
+  - PASSWORD`,
+	}
+
+	for i, src := range invalid {
+		m, err := Unmarshal([]byte(src))
+
+		if err != nil {
+			t.Fatalf("invalid[%d] - %s\n", i, err)
+		}
+
+		if err := m.Validate(); err == nil {
+			t.Errorf("invalid[%d] - expected manifest validation to fail\n", i)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package manifest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"djinn-ci.com/errors"
+)
+
+// ParamType is the type of a Param, this determines the values the Param
+// accepts.
+type ParamType string
+
+const (
+	ParamString ParamType = "string" // ParamString accepts any value.
+	ParamBool   ParamType = "bool"   // ParamBool accepts either true, or false.
+	ParamChoice ParamType = "choice" // ParamChoice accepts one of the choices of the Param.
+)
+
+// Param is a parameter of a build, the value of which is given when the build
+// is submitted. The value of each Param is set as a variable in the build
+// with the name of the Param. If no Type is given then the Param is a string.
+// A Param without a Default must be given a value, except for a bool Param,
+// which defaults to false.
+type Param struct {
+	Name        string
+	Type        ParamType `yaml:",omitempty"`
+	Default     string    `yaml:",omitempty"`
+	Description string    `yaml:",omitempty"`
+	Choices     []string  `yaml:",omitempty"`
+}
+
+var reParamName = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+// value returns the given value for the Param as it should be set in the
+// build. An error is returned if the value is not valid for the Param's
+// type.
+func (p Param) value(s string) (string, error) {
+	switch p.Type {
+	case ParamBool:
+		b, err := strconv.ParseBool(s)
+
+		if err != nil {
+			return "", errors.New("param " + p.Name + " must be true or false")
+		}
+		return strconv.FormatBool(b), nil
+	case ParamChoice:
+		for _, choice := range p.Choices {
+			if s == choice {
+				return s, nil
+			}
+		}
+		return "", errors.New("param " + p.Name + " must be one of " + strings.Join(p.Choices, ", "))
+	}
+	return s, nil
+}
+
+// ParamValues are the values given for the Params of a build, mapping the
+// name of each Param to its value.
+type ParamValues map[string]string
+
+var (
+	_ sql.Scanner   = (*ParamValues)(nil)
+	_ driver.Valuer = (*ParamValues)(nil)
+)
+
+// String returns the values as name=value pairs, one per line, sorted by
+// name.
+func (v ParamValues) String() string {
+	lines := make([]string, 0, len(v))
+
+	for name, val := range v {
+		lines = append(lines, name+"="+val)
+	}
+
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n")
+}
+
+// UnmarshalText parses the values from name=value pairs, one per line. Blank
+// lines are ignored.
+func (v *ParamValues) UnmarshalText(b []byte) error {
+	vals := make(ParamValues)
+
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		name, val, ok := strings.Cut(line, "=")
+
+		if !ok {
+			return errors.New("invalid param " + line + ", expected name=value")
+		}
+		vals[strings.TrimSpace(name)] = strings.TrimSpace(val)
+	}
+
+	(*v) = vals
+	return nil
+}
+
+// UnmarshalJSON parses the values from a JSON object. The values in the
+// object can be strings, numbers, or booleans.
+func (v *ParamValues) UnmarshalJSON(b []byte) error {
+	raw := make(map[string]any)
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	vals := make(ParamValues, len(raw))
+
+	for name, val := range raw {
+		switch val := val.(type) {
+		case string:
+			vals[name] = val
+		case bool:
+			vals[name] = strconv.FormatBool(val)
+		case float64:
+			vals[name] = strconv.FormatFloat(val, 'f', -1, 64)
+		default:
+			return errors.New("invalid value for param " + name)
+		}
+	}
+
+	(*v) = vals
+	return nil
+}
+
+func (v *ParamValues) Scan(val any) error {
+	b, err := driver.String.ConvertValue(val)
+
+	if err != nil {
+		return errors.Err(err)
+	}
+
+	str, ok := b.([]byte)
+
+	if !ok {
+		return errors.New("manifest: could not type assert ParamValues to byte slice")
+	}
+
+	if len(str) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(str, (*map[string]string)(v)); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
+func (v ParamValues) Value() (driver.Value, error) {
+	if v == nil {
+		return driver.Value("{}"), nil
+	}
+
+	b, err := json.Marshal(map[string]string(v))
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return driver.Value(string(b)), nil
+}
+
+// ParamEnv returns the environment variables for the Params of the manifest
+// from the given values. Params without a value are given their default. An
+// error is returned if a value is given for an unknown Param, if a value is
+// invalid for its Param, or if a Param without a default is not given a
+// value.
+func (m *Manifest) ParamEnv(vals ParamValues) ([]string, error) {
+	params := make(map[string]Param, len(m.Params))
+
+	for _, p := range m.Params {
+		params[p.Name] = p
+	}
+
+	names := make([]string, 0, len(vals))
+
+	for name := range vals {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := params[name]; !ok {
+			return nil, errors.New("unknown param " + name)
+		}
+	}
+
+	env := make([]string, 0, len(m.Params))
+
+	for _, p := range m.Params {
+		val, ok := vals[p.Name]
+
+		if !ok {
+			val = p.Default
+
+			if val == "" {
+				if p.Type != ParamBool {
+					return nil, errors.New("param " + p.Name + " requires a value")
+				}
+				val = "false"
+			}
+		}
+
+		val, err := p.value(val)
+
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, p.Name+"="+val)
+	}
+	return env, nil
+}
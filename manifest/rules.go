@@ -0,0 +1,136 @@
+package manifest
+
+import (
+	"path"
+	"strings"
+)
+
+// Context is what the Rules of a job or stage are evaluated against when a
+// build is submitted.
+type Context struct {
+	Trigger   string            // Trigger is the type of trigger, for example "push".
+	Branch    string            // Branch is the branch the build was triggered on.
+	Tag       string            // Tag is the tag the build was triggered on, if any.
+	Variables map[string]string // Variables are the resolved variables of the build.
+}
+
+// Condition is a set of criteria that a Context is matched against. Each
+// criteria that is set must match for the Condition to match. Branches, Tags
+// and the values of Variables are glob patterns as understood by path.Match.
+type Condition struct {
+	Triggers  []string          `yaml:",omitempty"`
+	Branches  []string          `yaml:",omitempty"`
+	Tags      []string          `yaml:",omitempty"`
+	Variables map[string]string `yaml:",omitempty"`
+}
+
+// Rules determine whether a job or stage is executed for a build. If Only is
+// set then the Context must match it, and if Except is set then the Context
+// must not match it. When determines when a job or stage that is executed is
+// started, if WhenManual then it has to be approved first.
+type Rules struct {
+	Only   Condition `yaml:",omitempty"`
+	Except Condition `yaml:",omitempty"`
+	When   string    `yaml:",omitempty"`
+}
+
+// WhenManual is the When of the Rules for a job, or stage that has to be
+// approved before it is started.
+const WhenManual = "manual"
+
+// IsZero reports whether the Condition has no criteria set.
+func (c Condition) IsZero() bool {
+	return len(c.Triggers) == 0 && len(c.Branches) == 0 && len(c.Tags) == 0 && len(c.Variables) == 0
+}
+
+func globAny(patterns []string, s string) bool {
+	for _, pat := range patterns {
+		if ok, _ := path.Match(pat, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether the given Context matches the Condition. An empty
+// Condition matches nothing.
+func (c Condition) Match(ctx Context) bool {
+	if c.IsZero() {
+		return false
+	}
+
+	if len(c.Triggers) > 0 {
+		ok := false
+
+		for _, trigger := range c.Triggers {
+			if trigger == ctx.Trigger {
+				ok = true
+				break
+			}
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	if len(c.Branches) > 0 && (ctx.Branch == "" || !globAny(c.Branches, ctx.Branch)) {
+		return false
+	}
+
+	if len(c.Tags) > 0 && (ctx.Tag == "" || !globAny(c.Tags, ctx.Tag)) {
+		return false
+	}
+
+	for key, pat := range c.Variables {
+		val, ok := ctx.Variables[key]
+
+		if !ok {
+			return false
+		}
+
+		if match, _ := path.Match(pat, val); !match {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks the glob patterns in the Condition are well formed.
+func (c Condition) validate() error {
+	pats := append(append([]string{}, c.Branches...), c.Tags...)
+
+	for _, pat := range c.Variables {
+		pats = append(pats, pat)
+	}
+
+	for _, pat := range pats {
+		if _, err := path.Match(pat, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Manual reports whether the Rules require approval before starting.
+func (r Rules) Manual() bool { return r.When == WhenManual }
+
+// IsZero reports whether no Rules are set.
+func (r Rules) IsZero() bool { return r.Only.IsZero() && r.Except.IsZero() }
+
+// Allow reports whether the given Context is allowed by the Rules.
+func (r Rules) Allow(ctx Context) bool {
+	if !r.Only.IsZero() && !r.Only.Match(ctx) {
+		return false
+	}
+	return !r.Except.Match(ctx)
+}
+
+// ParseRef returns the branch, or tag from the given git ref. Refs that are
+// not fully qualified are treated as branch names.
+func ParseRef(ref string) (string, string) {
+	if strings.HasPrefix(ref, "refs/tags/") {
+		return "", strings.TrimPrefix(ref, "refs/tags/")
+	}
+	return strings.TrimPrefix(ref, "refs/heads/"), ""
+}
@@ -0,0 +1,69 @@
+package manifest
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Service is a container that is run alongside the jobs of a build when using
+// the docker driver, such as a database. Each job can reach the service on the
+// network via its Alias, if no Alias is given then the name of the Image is
+// used. The Command, if given, replaces the command of the Image.
+type Service struct {
+	Image   string
+	Alias   string   `yaml:",omitempty"`
+	Env     []string `yaml:",omitempty"`
+	Command []string `yaml:",omitempty"`
+}
+
+var reServiceName = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9_.\\-]*$")
+
+// Name returns the name the service is reachable by. This is either the Alias,
+// or the name of the Image without the registry, and tag, so postgres:15 is
+// reachable via postgres.
+func (s Service) Name() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+
+	name := path.Base(s.Image)
+
+	if i := strings.IndexAny(name, ":@"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func (m *Manifest) checkServices(errs *Errors) {
+	if len(m.Services) == 0 {
+		return
+	}
+
+	if typ := m.Driver["type"]; typ != "docker" {
+		errs.add("services are not supported by driver "+typ, "services")
+		return
+	}
+
+	names := make(map[string]struct{}, len(m.Services))
+
+	for i, s := range m.Services {
+		if s.Image == "" {
+			errs.add("service has no image", "services", i)
+			continue
+		}
+
+		name := s.Name()
+
+		if !reServiceName.MatchString(name) {
+			errs.add("service has invalid alias "+name, "services", i)
+			continue
+		}
+
+		if _, ok := names[name]; ok {
+			errs.add("duplicate service "+name, "services", i)
+			continue
+		}
+		names[name] = struct{}{}
+	}
+}
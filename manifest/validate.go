@@ -0,0 +1,756 @@
+package manifest
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"djinn-ci.com/runner"
+)
+
+// Error is a single problem found in a manifest. Line and Col are the position
+// of the problem in the manifest source, these are zero if the position is not
+// known.
+type Error struct {
+	Line int    `json:"line"`
+	Col  int    `json:"column"`
+	Msg  string `json:"message"`
+
+	// path is the path to the value in the manifest that the problem is
+	// with, this is used to find the position of the problem in the source.
+	path []interface{}
+}
+
+// Errors is the list of problems found in a manifest.
+type Errors []*Error
+
+func (e *Error) Error() string {
+	if e.Line > 0 {
+		return strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Col) + ": " + e.Msg
+	}
+	return e.Msg
+}
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Err returns the Errors as an error, if there are any, otherwise nil.
+func (e Errors) Err() error {
+	if len(e) > 0 {
+		return e
+	}
+	return nil
+}
+
+func (e *Errors) add(msg string, path ...interface{}) {
+	(*e) = append((*e), &Error{
+		Msg:  msg,
+		path: path,
+	})
+}
+
+// Validate checks the manifest for problems. If any are found then they are
+// returned as Errors.
+func (m *Manifest) Validate() error {
+	return m.validate().Err()
+}
+
+// Lint returns every problem found with the manifest once its includes have
+// been resolved with the given Resolver. If the manifest was unmarshalled from
+// text then the problems are reported with their position in that text.
+func (m *Manifest) Lint(resolve Resolver) Errors {
+	if len(m.src) > 0 {
+		return Lint(m.src, resolve)
+	}
+
+	resolved, err := m.Resolve(resolve)
+
+	if err != nil {
+		var errs Errors
+		errs.add(err.Error(), "include")
+
+		// The includes have already been checked, so the manifest is
+		// validated without them.
+		local := *m
+		local.Include = nil
+
+		return append(errs, local.validate()...)
+	}
+	return resolved.validate()
+}
+
+func (m *Manifest) validate() Errors {
+	var errs Errors
+
+	m.checkDriver(&errs)
+	m.checkIncludes(&errs)
+	m.checkSources(&errs)
+	m.checkServices(&errs)
+	m.checkStages(&errs)
+	m.checkJobs(&errs)
+	m.checkMatrix(&errs)
+	m.checkEnv(&errs)
+	m.checkParams(&errs)
+	m.checkShells(&errs)
+	m.checkArtifacts(&errs)
+	m.checkCache(&errs)
+	m.checkInputs(&errs)
+	m.checkRetries(&errs)
+	m.checkRules(&errs)
+	m.checkPostJobs(&errs)
+	m.checkNeeds(&errs)
+
+	return errs
+}
+
+func (m *Manifest) checkDriver(errs *Errors) {
+	typ := m.Driver["type"]
+
+	required := map[string][]string{
+		"docker":  {"image", "workspace"},
+		"qemu":    {"image"},
+		"ssh":     {"address"},
+		"os":      {},
+		"sandbox": {"image"},
+	}
+
+	keys, ok := required[typ]
+
+	if !ok {
+		errs.add("invalid driver specified "+typ, "driver", "type")
+		return
+	}
+
+	for _, key := range keys {
+		if m.Driver[key] == "" {
+			errs.add("driver "+typ+" requires "+key, "driver")
+		}
+	}
+
+	if typ == "docker" {
+		for _, key := range []string{"dockerfile", "context"} {
+			p := m.Driver[key]
+
+			if p == "" {
+				continue
+			}
+
+			if path.IsAbs(p) || path.Clean(p) != p || p == ".." || strings.HasPrefix(p, "../") {
+				errs.add("driver "+key+" must be a relative path within the workspace", "driver", key)
+			}
+		}
+
+		switch pull := m.Driver["pull"]; pull {
+		case "", "always", "if-not-present", "never":
+		default:
+			errs.add("invalid driver pull policy "+pull+", expected always, if-not-present, or never", "driver", "pull")
+		}
+
+		if auth := m.Driver["registry_auth"]; auth != "" {
+			for _, pair := range strings.Split(auth, ",") {
+				host, name, ok := strings.Cut(strings.TrimSpace(pair), "=")
+
+				// The credentials are either in a variable, or in the
+				// key with the name prefixed with key:.
+				valid := reParamName.MatchString(name)
+
+				if strings.HasPrefix(name, "key:") {
+					valid = strings.TrimPrefix(name, "key:") != ""
+				}
+
+				if !ok || host == "" || !valid {
+					errs.add("driver registry_auth must be a list of host=VARIABLE, or host=key:NAME", "driver", "registry_auth")
+					break
+				}
+			}
+		}
+	}
+}
+
+func (m *Manifest) checkIncludes(errs *Errors) {
+	for i, inc := range m.Include {
+		if err := inc.validate(); err != nil {
+			errs.add(err.Error(), "include", i)
+		}
+	}
+}
+
+var reSCPLike = regexp.MustCompile("^([a-zA-Z0-9._\\-]+@)?[a-zA-Z0-9.\\-]+:[^/]")
+
+// checkSources checks that each source has a URL that can be cloned, and a
+// directory that is relative to the build environment.
+func (m *Manifest) checkSources(errs *Errors) {
+	dirs := make(map[string]struct{}, len(m.Sources))
+
+	for i, src := range m.Sources {
+		if src.URL == "" {
+			errs.add("source has no url", "sources", i)
+			continue
+		}
+
+		// URLs without a scheme, such as github.com/user/repo, are passed
+		// to git as they are, so only URLs with a scheme need a host.
+		if !reSCPLike.MatchString(src.URL) {
+			url, err := url.Parse(src.URL)
+
+			if err != nil || (url.Scheme != "" && url.Scheme != "file" && url.Host == "") {
+				errs.add("source has invalid url "+src.URL, "sources", i)
+			}
+		}
+
+		if src.Dir == "" {
+			errs.add("source "+src.URL+" has no directory", "sources", i)
+			continue
+		}
+
+		dir := path.Clean(src.Dir)
+
+		if path.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, "../") {
+			errs.add("source "+src.URL+" directory must be relative "+src.Dir, "sources", i)
+		}
+
+		if _, ok := dirs[dir]; ok {
+			errs.add("source "+src.URL+" has duplicate directory "+src.Dir, "sources", i)
+		}
+		dirs[dir] = struct{}{}
+	}
+}
+
+func (m *Manifest) checkStages(errs *Errors) {
+	stages := make(map[string]struct{}, len(m.Stages))
+
+	for i, stage := range m.Stages {
+		if _, ok := stages[stage]; ok {
+			errs.add("duplicate stage "+stage, "stages", i)
+		}
+		stages[stage] = struct{}{}
+	}
+
+	for i, stage := range m.AllowFailures {
+		if _, ok := stages[stage]; !ok {
+			errs.add("allow_failures has unknown stage "+stage, "allow_failures", i)
+		}
+	}
+}
+
+// checkJobs checks that each job is in a declared stage, and that each job
+// name is unique.
+func (m *Manifest) checkJobs(errs *Errors) {
+	stages := make(map[string]struct{}, len(m.Stages))
+
+	for _, stage := range m.Stages {
+		stages[stage] = struct{}{}
+	}
+
+	names := m.jobNames()
+	seen := make(map[string]struct{}, len(names))
+
+	for i, j := range m.Jobs {
+		if j.Stage == "" {
+			errs.add("job "+names[i]+" has no stage", "jobs", i)
+		} else if _, ok := stages[j.Stage]; !ok {
+			errs.add("job "+names[i]+" has undeclared stage "+j.Stage, "jobs", i, "stage")
+		}
+
+		if _, ok := seen[names[i]]; ok {
+			errs.add("duplicate job name "+names[i], "jobs", i, "name")
+		}
+		seen[names[i]] = struct{}{}
+	}
+
+	m.eachPostJob(func(sect string, i int, j Job) {
+		if _, ok := seen[j.Name]; ok {
+			errs.add("duplicate job name "+j.Name, sect, i, "name")
+		}
+		seen[j.Name] = struct{}{}
+	})
+}
+
+// eachPostJob calls fn for each always, and after failure job, along with the
+// section of the manifest the job is in, and its position in that section.
+func (m *Manifest) eachPostJob(fn func(sect string, i int, j Job)) {
+	jobs := m.PostJobs()
+
+	for i := range m.Always {
+		fn(AlwaysStage, i, jobs[i])
+	}
+
+	for i := range m.AfterFailure {
+		fn(AfterFailureStage, i, jobs[len(m.Always)+i])
+	}
+}
+
+func (m *Manifest) checkMatrix(errs *Errors) {
+	keys := make([]string, 0, len(m.Matrix.Vars))
+
+	for k := range m.Matrix.Vars {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		vals := m.Matrix.Vars[k]
+
+		if k == "" || strings.ContainsAny(k, "= ") {
+			errs.add("invalid matrix variable "+k, "matrix", k)
+		}
+		if len(vals) == 0 {
+			errs.add("matrix variable "+k+" has no values", "matrix", k)
+		}
+	}
+
+	for i, exclude := range m.Matrix.Exclude {
+		for k := range exclude {
+			if _, ok := m.Matrix.Vars[k]; !ok {
+				errs.add("matrix exclude has unknown variable "+k, "matrix", "exclude", i)
+			}
+		}
+	}
+}
+
+// checkEnv checks that each environment variable in the manifest, and in each
+// job is in the format of KEY=VALUE.
+func (m *Manifest) checkEnv(errs *Errors) {
+	check := func(env []string, path ...interface{}) {
+		for i, kv := range env {
+			key := kv
+
+			if j := strings.Index(kv, "="); j >= 0 {
+				key = kv[:j]
+			}
+
+			if key == kv || key == "" || strings.ContainsAny(key, " \t") {
+				errs.add("invalid environment variable "+kv+", expected KEY=VALUE", append(path, i)...)
+			}
+		}
+	}
+
+	check(m.Env, "env")
+
+	for i, s := range m.Services {
+		check(s.Env, "services", i, "env")
+	}
+
+	names := m.jobNames()
+
+	for i, j := range m.Jobs {
+		check(j.Env, "jobs", i, "env")
+
+		if strings.Contains(j.Workdir, "\n") {
+			errs.add("job "+names[i]+" has invalid workdir", "jobs", i, "workdir")
+		}
+	}
+
+	m.eachPostJob(func(sect string, i int, j Job) {
+		check(j.Env, sect, i, "env")
+
+		if strings.Contains(j.Workdir, "\n") {
+			errs.add("job "+j.Name+" has invalid workdir", sect, i, "workdir")
+		}
+	})
+}
+
+// shells is the shells jobs can be executed with.
+var shells = map[string]struct{}{
+	"sh":      {},
+	"bash":    {},
+	"python3": {},
+	"pwsh":    {},
+}
+
+// checkShells checks that the manifest, and each job use a known shell.
+func (m *Manifest) checkShells(errs *Errors) {
+	check := func(shell string, path ...interface{}) {
+		if shell == "" {
+			return
+		}
+
+		if _, ok := shells[shell]; !ok {
+			errs.add("unknown shell "+shell+", expected one of sh, bash, python3, or pwsh", path...)
+		}
+	}
+
+	check(m.Shell, "shell")
+
+	for i, j := range m.Jobs {
+		check(j.Shell, "jobs", i, "shell")
+	}
+
+	m.eachPostJob(func(sect string, i int, j Job) {
+		check(j.Shell, sect, i, "shell")
+	})
+}
+
+// checkArtifacts checks that the source of each artifact is a valid pattern,
+// and that each artifact is named.
+func (m *Manifest) checkArtifacts(errs *Errors) {
+	check := func(name string, pt runner.Passthrough, path ...interface{}) {
+		for _, a := range runner.Artifacts(pt) {
+			if err := a.Validate(); err != nil {
+				errs.add("job "+name+" "+err.Error(), path...)
+			}
+		}
+	}
+
+	names := m.jobNames()
+
+	for i, j := range m.Jobs {
+		check(names[i], j.Artifacts, "jobs", i, "artifacts")
+	}
+
+	m.eachPostJob(func(sect string, i int, j Job) {
+		check(j.Name, j.Artifacts, sect, i, "artifacts")
+	})
+}
+
+// checkInputs checks that each input of a job is an artifact of a job that
+// will have finished before it, that is a job in an earlier stage, or a job it
+// needs. The always, and after failure jobs can take the artifacts of any job.
+// When the manifest has a matrix, artifact names depend on the combination of
+// the job, so only the names of the inputs themselves are checked.
+func (m *Manifest) checkInputs(errs *Errors) {
+	stageIdx := make(map[string]int, len(m.Stages))
+
+	for i, name := range m.Stages {
+		stageIdx[name] = i
+	}
+
+	names := m.jobNames()
+	matrix := !m.Matrix.IsZero()
+
+	produced := func(name string, jobs []int) bool {
+		for _, i := range jobs {
+			for _, a := range runner.Artifacts(m.Jobs[i].Artifacts) {
+				if a.Produces(name) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	check := func(name string, pt runner.Passthrough, earlier []int, path ...interface{}) {
+		for _, a := range runner.Artifacts(pt) {
+			if a.Source == "" || strings.Contains(a.Source, "/") || a.Name == "" {
+				errs.add("job "+name+" has invalid input "+a.Source+" => "+a.Name, path...)
+				continue
+			}
+
+			if !matrix && !produced(a.Source, earlier) {
+				errs.add("job "+name+" input "+a.Source+" is not an artifact of an earlier job", path...)
+			}
+		}
+	}
+
+	for i, j := range m.Jobs {
+		if len(j.Inputs) == 0 {
+			continue
+		}
+
+		needs := make(map[string]struct{}, len(j.Needs))
+
+		for _, need := range j.Needs {
+			needs[need] = struct{}{}
+		}
+
+		earlier := make([]int, 0, len(m.Jobs))
+
+		for k, dep := range m.Jobs {
+			_, ok := needs[names[k]]
+
+			if stageIdx[dep.Stage] < stageIdx[j.Stage] || (ok && k != i) {
+				earlier = append(earlier, k)
+			}
+		}
+		check(names[i], j.Inputs, earlier, "jobs", i, "inputs")
+	}
+
+	all := make([]int, len(m.Jobs))
+
+	for i := range all {
+		all[i] = i
+	}
+
+	m.eachPostJob(func(sect string, i int, j Job) {
+		check(j.Name, j.Inputs, all, sect, i, "inputs")
+	})
+}
+
+// checkCache checks that the cache has a valid key, and that each path is
+// relative to the directory the build is executed in.
+func (m *Manifest) checkCache(errs *Errors) {
+	if m.Cache.IsZero() {
+		return
+	}
+
+	if m.Cache.Key == "" {
+		errs.add("cache has no key", "cache")
+	} else if err := runner.ParseCacheKey(m.Cache.Key); err != nil {
+		errs.add(err.Error(), "cache", "key")
+	}
+
+	if len(m.Cache.Paths) == 0 {
+		errs.add("cache has no paths", "cache")
+	}
+
+	for i, p := range m.Cache.Paths {
+		dir := path.Clean(p)
+
+		if path.IsAbs(dir) || dir == "." || dir == ".." || strings.HasPrefix(dir, "../") {
+			errs.add("cache path must be relative "+p, "cache", "paths", i)
+		}
+	}
+}
+
+// checkRetries checks the retry configuration of each job in the manifest,
+// ensuring the maximum number of attempts is set, and that each output pattern
+// compiles.
+func (m *Manifest) checkRetries(errs *Errors) {
+	check := func(name string, r Retry, path ...interface{}) {
+		if r.IsZero() {
+			return
+		}
+
+		if r.Max < 1 {
+			errs.add("job "+name+" retry requires max of at least 1", path...)
+		}
+
+		if _, err := r.Patterns(); err != nil {
+			errs.add("job "+name+" invalid retry output pattern: "+err.Error(), path...)
+		}
+	}
+
+	names := m.jobNames()
+
+	for i, j := range m.Jobs {
+		check(names[i], j.Retry, "jobs", i, "retry")
+	}
+
+	m.eachPostJob(func(sect string, i int, j Job) {
+		check(j.Name, j.Retry, sect, i, "retry")
+	})
+}
+
+// checkParams checks the params of the manifest. Each param must have a
+// unique name that can be used as an environment variable, and a known type.
+// Choice params must have choices, and the default of a param must be valid
+// for its type.
+func (m *Manifest) checkParams(errs *Errors) {
+	seen := make(map[string]struct{}, len(m.Params))
+
+	for i, p := range m.Params {
+		if !reParamName.MatchString(p.Name) {
+			errs.add("invalid param name "+p.Name, "params", i, "name")
+			continue
+		}
+
+		if _, ok := seen[p.Name]; ok {
+			errs.add("duplicate param "+p.Name, "params", i, "name")
+		}
+		seen[p.Name] = struct{}{}
+
+		switch p.Type {
+		case "", ParamString, ParamBool:
+			if len(p.Choices) > 0 {
+				errs.add("param "+p.Name+" cannot have choices", "params", i, "choices")
+			}
+		case ParamChoice:
+			if len(p.Choices) == 0 {
+				errs.add("param "+p.Name+" has no choices", "params", i, "choices")
+			}
+		default:
+			errs.add("param "+p.Name+" has unknown type "+string(p.Type), "params", i, "type")
+			continue
+		}
+
+		if p.Default != "" {
+			if _, err := p.value(p.Default); err != nil {
+				errs.add("invalid default for "+err.Error(), "params", i, "default")
+			}
+		}
+	}
+}
+
+// checkRules checks the rules of each job, and stage in the manifest. Only
+// stages in the manifest can have rules, and the triggers given in the rules
+// must be known triggers. The always, and after failure jobs cannot be
+// manual, since nobody would be around to approve them.
+func (m *Manifest) checkRules(errs *Errors) {
+	triggers := map[string]struct{}{
+		"manual":   {},
+		"push":     {},
+		"pull":     {},
+		"schedule": {},
+	}
+
+	check := func(name string, r Rules, path ...interface{}) {
+		for _, c := range []Condition{r.Only, r.Except} {
+			for _, trigger := range c.Triggers {
+				if _, ok := triggers[trigger]; !ok {
+					errs.add(name+" has unknown trigger "+trigger, path...)
+				}
+			}
+
+			if err := c.validate(); err != nil {
+				errs.add(name+" has invalid pattern: "+err.Error(), path...)
+			}
+		}
+
+		if r.When != "" && r.When != WhenManual {
+			errs.add(name+" has unknown when "+r.When, append(path, "when")...)
+		}
+	}
+
+	stages := make(map[string]struct{}, len(m.Stages))
+
+	for _, stage := range m.Stages {
+		stages[stage] = struct{}{}
+	}
+
+	for stage, r := range m.StageRules {
+		if _, ok := stages[stage]; !ok {
+			errs.add("rules given for unknown stage "+stage, "stage_rules", stage)
+			continue
+		}
+		check("stage "+stage, r, "stage_rules", stage)
+	}
+
+	names := m.jobNames()
+
+	for i, j := range m.Jobs {
+		check("job "+names[i], j.Rules, "jobs", i)
+	}
+
+	m.eachPostJob(func(sect string, i int, j Job) {
+		check("job "+j.Name, j.Rules, sect, i)
+
+		if j.Manual() {
+			errs.add("job "+j.Name+" in "+j.Stage+" cannot be manual", sect, i, "when")
+		}
+	})
+}
+
+// checkPostJobs checks the always, and after failure jobs. These are run in
+// stages of their own, so the names of these stages cannot be used by the
+// manifest, and the jobs cannot need other jobs.
+func (m *Manifest) checkPostJobs(errs *Errors) {
+	if len(m.Always) == 0 && len(m.AfterFailure) == 0 {
+		return
+	}
+
+	for i, stage := range m.Stages {
+		if stage == AlwaysStage || stage == AfterFailureStage {
+			errs.add("stage name "+stage+" is reserved", "stages", i)
+		}
+	}
+
+	m.eachPostJob(func(sect string, i int, j Job) {
+		if len(j.Needs) > 0 {
+			errs.add("job "+j.Name+" in "+j.Stage+" cannot have needs", sect, i, "needs")
+		}
+	})
+}
+
+// checkNeeds checks the needs of each job in the manifest. A job can only need
+// a job that exists in the same stage, or an earlier stage, and the needs must
+// not form a cycle.
+func (m *Manifest) checkNeeds(errs *Errors) {
+	stageIdx := make(map[string]int, len(m.Stages))
+
+	for i, name := range m.Stages {
+		stageIdx[name] = i
+	}
+
+	names := m.jobNames()
+	jobtab := make(map[string]Job, len(m.Jobs))
+	idxtab := make(map[string]int, len(m.Jobs))
+
+	for i, j := range m.Jobs {
+		jobtab[names[i]] = j
+		idxtab[names[i]] = i
+	}
+
+	valid := true
+
+	for i, j := range m.Jobs {
+		for k, need := range j.Needs {
+			dep, ok := jobtab[need]
+
+			if !ok {
+				errs.add("job "+names[i]+" needs unknown job "+need, "jobs", i, "needs", k)
+				valid = false
+				continue
+			}
+
+			if need == names[i] {
+				errs.add("job "+names[i]+" cannot need itself", "jobs", i, "needs", k)
+				valid = false
+				continue
+			}
+
+			if stageIdx[dep.Stage] > stageIdx[j.Stage] {
+				errs.add("job "+names[i]+" needs job "+need+" in later stage "+dep.Stage, "jobs", i, "needs", k)
+			}
+		}
+	}
+
+	// Only look for cycles once every need is known to exist, otherwise the
+	// same problem would be reported twice.
+	if !valid {
+		return
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := make(map[string]int, len(names))
+	path := make([]string, 0, len(names))
+
+	var visit func(name string) bool
+
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			for i, s := range path {
+				if s == name {
+					cycle := strings.Join(append(path[i:], name), " -> ")
+					errs.add("job dependency cycle "+cycle, "jobs", idxtab[name], "needs")
+				}
+			}
+			return false
+		case visited:
+			return true
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, need := range jobtab[name].Needs {
+			if !visit(need) {
+				return false
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+		return true
+	}
+
+	for _, name := range names {
+		if !visit(name) {
+			return
+		}
+	}
+}
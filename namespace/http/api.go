@@ -182,6 +182,20 @@ func (h API) Show(u *auth.User, n *namespace.Namespace, w http.ResponseWriter, r
 			return
 		}
 		webutil.JSON(w, ww, http.StatusOK)
+	case "templates":
+		tt, err := h.Templates.All(
+			ctx, query.Where("namespace_id", "=", query.Arg(n.ID)), query.OrderAsc("name"),
+		)
+
+		if err != nil {
+			h.InternalServerError(w, r, errors.Wrap(err, "Failed to get templates"))
+			return
+		}
+
+		for _, t := range tt {
+			t.Namespace = n
+		}
+		webutil.JSON(w, tt, http.StatusOK)
 	default:
 		b, ok, err := h.Builds.Get(
 			ctx, query.Where("namespace_id", "=", query.Arg(n.ID)), query.OrderDesc("created_at"),
@@ -445,6 +459,94 @@ func (h WebhookAPI) Destroy(u *auth.User, n *namespace.Namespace, w http.Respons
 	w.WriteHeader(http.StatusNoContent)
 }
 
+type TemplateAPI struct {
+	*TemplateHandler
+}
+
+func (h TemplateAPI) template(n *namespace.Namespace, r *http.Request) (*namespace.Template, bool, error) {
+	t, ok, err := h.Templates.Get(
+		r.Context(),
+		query.Where("id", "=", query.Arg(mux.Vars(r)["template"])),
+		query.Where("namespace_id", "=", query.Arg(n.ID)),
+	)
+
+	if err != nil {
+		return nil, false, errors.Err(err)
+	}
+
+	if ok {
+		t.Namespace = n
+	}
+	return t, ok, nil
+}
+
+func (h TemplateAPI) Store(u *auth.User, n *namespace.Namespace, w http.ResponseWriter, r *http.Request) {
+	t, _, err := h.TemplateHandler.Store(u, n, r)
+
+	if err != nil {
+		h.FormError(w, r, nil, errors.Wrap(err, "Failed to create template"))
+		return
+	}
+	webutil.JSON(w, t, http.StatusCreated)
+}
+
+func (h TemplateAPI) Show(u *auth.User, n *namespace.Namespace, w http.ResponseWriter, r *http.Request) {
+	t, ok, err := h.template(n, r)
+
+	if err != nil {
+		h.InternalServerError(w, r, errors.Wrap(err, "Failed to get template"))
+		return
+	}
+
+	if !ok {
+		h.NotFound(w, r)
+		return
+	}
+	webutil.JSON(w, t, http.StatusOK)
+}
+
+func (h TemplateAPI) Update(u *auth.User, n *namespace.Namespace, w http.ResponseWriter, r *http.Request) {
+	t, ok, err := h.template(n, r)
+
+	if err != nil {
+		h.InternalServerError(w, r, errors.Wrap(err, "Failed to get template"))
+		return
+	}
+
+	if !ok {
+		h.NotFound(w, r)
+		return
+	}
+
+	t, _, err = h.TemplateHandler.Update(t, r)
+
+	if err != nil {
+		h.FormError(w, r, nil, errors.Wrap(err, "Failed to update template"))
+		return
+	}
+	webutil.JSON(w, t, http.StatusOK)
+}
+
+func (h TemplateAPI) Destroy(u *auth.User, n *namespace.Namespace, w http.ResponseWriter, r *http.Request) {
+	t, ok, err := h.template(n, r)
+
+	if err != nil {
+		h.InternalServerError(w, r, errors.Wrap(err, "Failed to get template"))
+		return
+	}
+
+	if !ok {
+		h.NotFound(w, r)
+		return
+	}
+
+	if err := h.Templates.Delete(r.Context(), t); err != nil {
+		h.InternalServerError(w, r, errors.Wrap(err, "Failed to delete template"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func registerNamespaceAPI(a auth.Authenticator, srv *server.Server) {
 	api := API{
 		Handler: NewHandler(srv),
@@ -476,6 +578,7 @@ func registerNamespaceAPI(a auth.Authenticator, srv *server.Server) {
 	sr.HandleFunc("/-/collaborators/{collaborator}", destroyCollab).Methods("DELETE")
 	sr.HandleFunc("/-/invites", srv.Restrict(a, []string{"invite:read"}, api.Namespace(api.Show))).Methods("GET")
 	sr.HandleFunc("/-/webhooks", srv.Restrict(a, []string{"webhook:read"}, api.Namespace(api.Show))).Methods("GET")
+	sr.HandleFunc("/-/templates", show).Methods("GET")
 	sr.HandleFunc("", update).Methods("PATCH")
 	sr.HandleFunc("", destroy).Methods("DELETE")
 }
@@ -502,6 +605,30 @@ func registerWebhookAPI(a auth.Authenticator, srv *server.Server) {
 	sr.HandleFunc("/{webhook:[0-9]+}", destroy).Methods("DELETE")
 }
 
+// registerTemplateAPI registers the routes for managing the templates in a
+// namespace. Templates are part of the namespace configuration, so they fall
+// under the namespace scopes.
+func registerTemplateAPI(a auth.Authenticator, srv *server.Server) {
+	api := TemplateAPI{
+		TemplateHandler: NewTemplateHandler(srv),
+	}
+
+	h := NewHandler(srv)
+
+	a = namespace.NewAuth(a, "namespace", h.Namespaces.Store)
+
+	store := srv.Restrict(a, []string{"namespace:write"}, h.Namespace(api.Store))
+	show := srv.Restrict(a, []string{"namespace:read"}, h.Namespace(api.Show))
+	update := srv.Restrict(a, []string{"namespace:write"}, h.Namespace(api.Update))
+	destroy := srv.Restrict(a, []string{"namespace:delete"}, h.Namespace(api.Destroy))
+
+	sr := srv.Router.PathPrefix("/n/{username}/{namespace:[a-zA-Z0-9\\/?]+}/-/templates").Subrouter()
+	sr.HandleFunc("", store).Methods("POST")
+	sr.HandleFunc("/{template:[0-9]+}", show).Methods("GET")
+	sr.HandleFunc("/{template:[0-9]+}", update).Methods("PATCH")
+	sr.HandleFunc("/{template:[0-9]+}", destroy).Methods("DELETE")
+}
+
 func registerInviteAPI(a auth.Authenticator, srv *server.Server) {
 	api := InviteAPI{
 		InviteHandler: NewInviteHandler(srv),
@@ -527,5 +654,6 @@ func registerInviteAPI(a auth.Authenticator, srv *server.Server) {
 func RegisterAPI(a auth.Authenticator, srv *server.Server) {
 	registerNamespaceAPI(a, srv)
 	registerWebhookAPI(a, srv)
+	registerTemplateAPI(a, srv)
 	registerInviteAPI(a, srv)
 }
@@ -11,6 +11,7 @@ import (
 	"djinn-ci.com/auth"
 	"djinn-ci.com/database"
 	"djinn-ci.com/errors"
+	"djinn-ci.com/manifest"
 	"djinn-ci.com/namespace"
 
 	"github.com/andrewpillar/query"
@@ -26,15 +27,21 @@ type Form struct {
 	Name        string
 	Description string
 	Visibility  namespace.Visibility
+	Defaults    *manifest.Defaults
 }
 
 var _ webutil.Form = (*Form)(nil)
 
 func (f *Form) Fields() map[string]string {
-	return map[string]string{
+	fields := map[string]string{
 		"name":        f.Name,
 		"description": f.Description,
 	}
+
+	if f.Defaults != nil {
+		fields["defaults"] = f.Defaults.String()
+	}
+	return fields
 }
 
 var reName = regexp.MustCompile("^[a-zA-Z0-9]+$")
@@ -60,6 +67,13 @@ func (f *Form) Validate(ctx context.Context) error {
 		if f.Description == "" {
 			f.Description = f.Namespace.Description
 		}
+		if f.Defaults == nil {
+			f.Defaults = &f.Namespace.Defaults
+		}
+	}
+
+	if f.Defaults == nil {
+		f.Defaults = &manifest.Defaults{}
 	}
 
 	v.Add("name", f.Name, webutil.FieldRequired)
@@ -87,6 +101,9 @@ func (f *Form) Validate(ctx context.Context) error {
 	})
 
 	v.Add("description", f.Description, webutil.FieldMaxLen(255))
+	v.Add("defaults", *f.Defaults, func(_ context.Context, val any) error {
+		return val.(manifest.Defaults).Validate()
+	})
 
 	errs := v.Validate(ctx)
 
@@ -221,3 +238,77 @@ func (f *WebhookForm) Validate(ctx context.Context) error {
 
 	return errs.Err()
 }
+
+type TemplateForm struct {
+	Pool      *database.Pool       `json:"-" schema:"-"`
+	Namespace *namespace.Namespace `json:"-" schema:"-"`
+	Template  *namespace.Template  `json:"-" schema:"-"`
+
+	Name    string
+	Content string
+}
+
+var _ webutil.Form = (*TemplateForm)(nil)
+
+var reTemplateName = regexp.MustCompile("^[a-zA-Z0-9\\-_.]+$")
+
+func (f *TemplateForm) Fields() map[string]string {
+	return map[string]string{
+		"name":    f.Name,
+		"content": f.Content,
+	}
+}
+
+func (f *TemplateForm) Validate(ctx context.Context) error {
+	var v webutil.Validator
+
+	v.WrapError(webutil.WrapFieldError)
+
+	if f.Template != nil {
+		f.Name = f.Template.Name
+
+		if f.Content == "" {
+			f.Content = f.Template.Content
+		}
+	}
+
+	v.Add("name", f.Name, webutil.FieldRequired)
+	v.Add("name", f.Name, webutil.FieldMatches(reTemplateName))
+	v.Add("content", f.Content, webutil.FieldRequired)
+	v.Add("content", f.Content, func(_ context.Context, val any) error {
+		m, err := manifest.Unmarshal([]byte(val.(string)))
+
+		if err != nil {
+			return errors.New("invalid manifest: " + errors.Cause(err).Error())
+		}
+
+		for _, inc := range m.Include {
+			if inc.Template == "" {
+				return errors.New("templates can only include other templates")
+			}
+		}
+		return nil
+	})
+
+	if f.Template == nil {
+		v.Add("name", f.Name, func(ctx context.Context, val any) error {
+			_, ok, err := namespace.NewTemplateStore(f.Pool).Get(
+				ctx,
+				query.Where("namespace_id", "=", query.Arg(f.Namespace.ID)),
+				query.Where("name", "=", query.Arg(val)),
+			)
+
+			if err != nil {
+				return err
+			}
+			if ok {
+				return webutil.ErrFieldExists
+			}
+			return nil
+		})
+	}
+
+	errs := v.Validate(ctx)
+
+	return errs.Err()
+}
@@ -36,6 +36,7 @@ type Handler struct {
 	Collaborators *database.Store[*namespace.Collaborator]
 	Invites       namespace.InviteStore
 	Webhooks      *namespace.WebhookStore
+	Templates     *namespace.TemplateStore
 	Users         *database.Store[*auth.User]
 	Builds        *build.Store
 	Images        *image.Store
@@ -56,6 +57,9 @@ func NewHandler(srv *server.Server) *Handler {
 			Store:  namespace.NewWebhookStore(srv.DB),
 			AESGCM: srv.AESGCM,
 		},
+		Templates: &namespace.TemplateStore{
+			Store: namespace.NewTemplateStore(srv.DB),
+		},
 		Users: user.NewStore(srv.DB),
 		Builds: &build.Store{
 			Store: build.NewStore(srv.DB),
@@ -237,6 +241,7 @@ func (h *Handler) Store(u *auth.User, r *http.Request) (*namespace.Namespace, *F
 		Name:        f.Name,
 		Description: f.Description,
 		Visibility:  f.Visibility,
+		Defaults:    *f.Defaults,
 	})
 
 	if err != nil {
@@ -309,7 +314,7 @@ func (h *Handler) Badge(w http.ResponseWriter, r *http.Request) {
 	switch b.Status {
 	case runner.Queued:
 		io.WriteString(w, badgeQueued)
-	case runner.Running:
+	case runner.Running, runner.Waiting:
 		io.WriteString(w, badgeRunning)
 	case runner.Passed:
 		io.WriteString(w, badgePassed)
@@ -341,6 +346,7 @@ func (h *Handler) Update(u *auth.User, n *namespace.Namespace, r *http.Request)
 
 	n.Description = f.Description
 	n.Visibility = f.Visibility
+	n.Defaults = *f.Defaults
 
 	if err := h.Namespaces.Update(ctx, n); err != nil {
 		return nil, &f, errors.Err(err)
@@ -597,3 +603,60 @@ func (h *WebhookHandler) Update(wh *namespace.Webhook, r *http.Request) (*namesp
 	}
 	return wh, &f, nil
 }
+
+type TemplateHandler struct {
+	*server.Server
+
+	Templates *namespace.TemplateStore
+}
+
+func NewTemplateHandler(srv *server.Server) *TemplateHandler {
+	return &TemplateHandler{
+		Server: srv,
+		Templates: &namespace.TemplateStore{
+			Store: namespace.NewTemplateStore(srv.DB),
+		},
+	}
+}
+
+func (h *TemplateHandler) Store(u *auth.User, n *namespace.Namespace, r *http.Request) (*namespace.Template, *TemplateForm, error) {
+	f := TemplateForm{
+		Pool:      h.DB,
+		Namespace: n,
+	}
+
+	if err := webutil.UnmarshalFormAndValidate(&f, r); err != nil {
+		return nil, &f, errors.Err(err)
+	}
+
+	t, err := h.Templates.Create(r.Context(), &namespace.TemplateParams{
+		User:      u,
+		Namespace: n,
+		Name:      f.Name,
+		Content:   f.Content,
+	})
+
+	if err != nil {
+		return nil, &f, errors.Err(err)
+	}
+	return t, &f, nil
+}
+
+func (h *TemplateHandler) Update(t *namespace.Template, r *http.Request) (*namespace.Template, *TemplateForm, error) {
+	f := TemplateForm{
+		Pool:      h.DB,
+		Namespace: t.Namespace,
+		Template:  t,
+	}
+
+	if err := webutil.UnmarshalFormAndValidate(&f, r); err != nil {
+		return nil, &f, errors.Err(err)
+	}
+
+	t.Content = f.Content
+
+	if err := h.Templates.Update(r.Context(), t); err != nil {
+		return nil, &f, errors.Err(err)
+	}
+	return t, &f, nil
+}
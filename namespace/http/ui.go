@@ -351,9 +351,15 @@ func (h UI) DestroyCollaborator(u *auth.User, n *namespace.Namespace, w http.Res
 func (h UI) Edit(u *auth.User, n *namespace.Namespace, w http.ResponseWriter, r *http.Request) {
 	sess, _ := h.Session(r)
 
+	f := form.New(sess, r)
+
+	if _, ok := f.Fields["defaults"]; !ok {
+		f.Fields["defaults"] = n.Defaults.String()
+	}
+
 	tmpl := template.NewDashboard(u, sess, r)
 	tmpl.Partial = &template.NamespaceForm{
-		Form:      form.New(sess, r),
+		Form:      f,
 		Namespace: n,
 	}
 	h.Template(w, r, tmpl, http.StatusOK)
@@ -13,6 +13,7 @@ import (
 	"djinn-ci.com/env"
 	"djinn-ci.com/errors"
 	"djinn-ci.com/event"
+	"djinn-ci.com/manifest"
 	"djinn-ci.com/queue"
 	"djinn-ci.com/user"
 
@@ -20,6 +21,9 @@ import (
 	"github.com/andrewpillar/webutil/v2"
 )
 
+// Namespace is a group of resources that builds can be submitted to. The
+// Defaults are merged into the manifest of every build submitted to the
+// Namespace.
 type Namespace struct {
 	loaded    []string
 	collabtab map[int64]struct{}
@@ -33,6 +37,7 @@ type Namespace struct {
 	Description string
 	Level       int64
 	Visibility  Visibility
+	Defaults    manifest.Defaults
 	CreatedAt   time.Time
 
 	User   *auth.User
@@ -153,6 +158,7 @@ func (n *Namespace) Scan(r *database.Row) error {
 		"description": &n.Description,
 		"level":       &n.Level,
 		"visibility":  &n.Visibility,
+		"defaults":    &n.Defaults,
 		"created_at":  &n.CreatedAt,
 	}
 
@@ -175,6 +181,7 @@ func (n *Namespace) Params() database.Params {
 		"description": database.CreateUpdateParam(n.Description),
 		"level":       database.CreateOnlyParam(n.Level),
 		"visibility":  database.CreateUpdateParam(n.Visibility),
+		"defaults":    database.CreateUpdateParam(n.Defaults),
 		"created_at":  database.CreateOnlyParam(n.CreatedAt),
 	}
 
@@ -198,6 +205,7 @@ func (n *Namespace) MarshalJSON() ([]byte, error) {
 		"path":              n.Path,
 		"description":       n.Description,
 		"visibility":        n.Visibility,
+		"defaults":          n.Defaults.String(),
 		"created_at":        n.CreatedAt,
 		"url":               env.DJINN_API_SERVER + n.Endpoint(),
 		"builds_url":        env.DJINN_API_SERVER + n.Endpoint("builds"),
@@ -419,6 +427,7 @@ type Params struct {
 	Name        string
 	Description string
 	Visibility  Visibility
+	Defaults    manifest.Defaults
 }
 
 func (s Store) Create(ctx context.Context, p *Params) (*Namespace, error) {
@@ -470,6 +479,7 @@ func (s Store) Create(ctx context.Context, p *Params) (*Namespace, error) {
 		Description: p.Description,
 		Level:       level,
 		Visibility:  p.Visibility,
+		Defaults:    p.Defaults,
 		CreatedAt:   time.Now(),
 		User:        p.User,
 	}
@@ -0,0 +1,250 @@
+package namespace
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"djinn-ci.com/auth"
+	"djinn-ci.com/database"
+	"djinn-ci.com/env"
+	"djinn-ci.com/errors"
+	"djinn-ci.com/manifest"
+	"djinn-ci.com/user"
+
+	"github.com/andrewpillar/query"
+)
+
+// Template is a partial manifest stored in a namespace. A manifest submitted
+// to the namespace can include the template by name.
+type Template struct {
+	loaded []string
+
+	ID          int64
+	UserID      int64
+	AuthorID    int64
+	NamespaceID int64
+	Name        string
+	Content     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Author    *auth.User
+	User      *auth.User
+	Namespace *Namespace
+}
+
+var _ database.Model = (*Template)(nil)
+
+func (t *Template) Primary() (string, any) { return "id", t.ID }
+
+func (t *Template) Scan(r *database.Row) error {
+	valtab := map[string]any{
+		"id":           &t.ID,
+		"user_id":      &t.UserID,
+		"author_id":    &t.AuthorID,
+		"namespace_id": &t.NamespaceID,
+		"name":         &t.Name,
+		"content":      &t.Content,
+		"created_at":   &t.CreatedAt,
+		"updated_at":   &t.UpdatedAt,
+	}
+
+	if err := database.Scan(r, valtab); err != nil {
+		return errors.Err(err)
+	}
+
+	t.loaded = r.Columns
+	return nil
+}
+
+func (t *Template) Params() database.Params {
+	params := database.Params{
+		"id":           database.ImmutableParam(t.ID),
+		"user_id":      database.CreateOnlyParam(t.UserID),
+		"author_id":    database.CreateOnlyParam(t.AuthorID),
+		"namespace_id": database.CreateOnlyParam(t.NamespaceID),
+		"name":         database.CreateOnlyParam(t.Name),
+		"content":      database.CreateUpdateParam(t.Content),
+		"created_at":   database.CreateOnlyParam(t.CreatedAt),
+		"updated_at":   database.CreateUpdateParam(t.UpdatedAt),
+	}
+
+	if len(t.loaded) > 0 {
+		params.Only(t.loaded...)
+	}
+	return params
+}
+
+func (t *Template) Bind(m database.Model) {
+	switch v := m.(type) {
+	case *Namespace:
+		if t.NamespaceID == v.ID {
+			t.Namespace = v
+		}
+	case *auth.User:
+		if t.AuthorID == v.ID {
+			t.Author = v
+		}
+		if t.UserID == v.ID {
+			t.User = v
+		}
+	}
+}
+
+func (t *Template) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("null"), nil
+	}
+
+	b, err := json.Marshal(map[string]any{
+		"id":           t.ID,
+		"user_id":      t.UserID,
+		"author_id":    t.AuthorID,
+		"namespace_id": t.NamespaceID,
+		"name":         t.Name,
+		"content":      t.Content,
+		"created_at":   t.CreatedAt,
+		"updated_at":   t.UpdatedAt,
+		"url":          env.DJINN_API_SERVER + t.Endpoint(),
+		"namespace":    t.Namespace,
+	})
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return b, nil
+}
+
+func (t *Template) Endpoint(elems ...string) string {
+	if t.Namespace == nil {
+		return ""
+	}
+	return t.Namespace.Endpoint(append([]string{"templates", strconv.FormatInt(t.ID, 10)}, elems...)...)
+}
+
+type TemplateStore struct {
+	*database.Store[*Template]
+}
+
+const templateTable = "namespace_templates"
+
+func NewTemplateStore(pool *database.Pool) *database.Store[*Template] {
+	return database.NewStore[*Template](pool, templateTable, func() *Template {
+		return &Template{}
+	})
+}
+
+var ErrUnknownTemplate = errors.New("unknown template")
+
+type TemplateParams struct {
+	User      *auth.User
+	Namespace *Namespace
+	Name      string
+	Content   string
+}
+
+func (s *TemplateStore) Create(ctx context.Context, p *TemplateParams) (*Template, error) {
+	now := time.Now()
+
+	t := Template{
+		UserID:      p.Namespace.UserID,
+		AuthorID:    p.User.ID,
+		NamespaceID: p.Namespace.ID,
+		Name:        p.Name,
+		Content:     p.Content,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Author:      p.User,
+		Namespace:   p.Namespace,
+	}
+
+	if err := s.Store.Create(ctx, &t); err != nil {
+		return nil, errors.Err(err)
+	}
+	return &t, nil
+}
+
+func (s *TemplateStore) Update(ctx context.Context, t *Template) error {
+	loaded := t.loaded
+	t.loaded = []string{"content", "updated_at"}
+	t.UpdatedAt = time.Now()
+
+	if err := s.Store.Update(ctx, t); err != nil {
+		return errors.Err(err)
+	}
+
+	t.loaded = loaded
+	return nil
+}
+
+// Resolver returns a manifest.Resolver for resolving the template includes of
+// a manifest. Templates are looked up in the namespace of the manifest, which
+// the given user must have access to. Path includes cannot be resolved by the
+// returned Resolver.
+func (s *TemplateStore) Resolver(ctx context.Context, u *auth.User) manifest.Resolver {
+	return func(ns string, inc manifest.Include) ([]byte, error) {
+		if inc.Template == "" {
+			return nil, errors.New("cannot include files outside of a repository")
+		}
+
+		p, err := ParsePath(ns)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.Valid {
+			return nil, errors.New("cannot include template without a namespace")
+		}
+
+		owner := u
+
+		if p.Owner != "" {
+			o, ok, err := user.NewStore(s.Pool).Get(ctx, user.WhereUsername(p.Owner))
+
+			if err != nil {
+				return nil, errors.Err(err)
+			}
+
+			if !ok {
+				return nil, &PathError{Path: p, Err: ErrOwner}
+			}
+			owner = o
+		}
+
+		n, ok, err := NewStore(s.Pool).Get(
+			ctx,
+			query.Where("user_id", "=", query.Arg(owner.ID)),
+			query.Where("path", "=", query.Arg(p.Path)),
+		)
+
+		if err != nil {
+			return nil, errors.Err(err)
+		}
+
+		if !ok {
+			return nil, ErrUnknownTemplate
+		}
+
+		if err := n.HasAccess(ctx, s.Pool, u); err != nil {
+			return nil, err
+		}
+
+		t, ok, err := s.Get(
+			ctx,
+			query.Where("namespace_id", "=", query.Arg(n.ID)),
+			query.Where("name", "=", query.Arg(inc.Template)),
+		)
+
+		if err != nil {
+			return nil, errors.Err(err)
+		}
+
+		if !ok {
+			return nil, ErrUnknownTemplate
+		}
+		return []byte(t.Content), nil
+	}
+}
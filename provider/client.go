@@ -27,6 +27,7 @@ var (
 	StatusDescriptions = map[runner.Status]string{
 		runner.Queued:             "Build is queued.",
 		runner.Running:            "Build is running.",
+		runner.Waiting:            "Build is waiting for approval.",
 		runner.Passed:             "Build has passed.",
 		runner.PassedWithFailures: "Build has passed with failures.",
 		runner.Failed:             "Build has failed.",
@@ -80,8 +81,10 @@ type Client interface {
 	SetCommitStatus(r *Repo, status runner.Status, url, sha string) error
 
 	// Manifests returns all of the build manifests that could be found in the
-	// repository contents from the root URL at the given ref.
-	Manifests(root, ref string) ([]manifest.Manifest, error)
+	// repository contents from the root URL at the given ref. Files included
+	// by the manifests are taken from the repository at the same ref, and
+	// templates are resolved with the given manifest.Resolver.
+	Manifests(root, ref string, templates manifest.Resolver) ([]manifest.Manifest, error)
 
 	// Verify verifies the given Request. This is typically used for verifying
 	// the contents of a webhook that has been sent from said provider. Upon
@@ -157,9 +160,7 @@ func (c *BaseClient) Do(r *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
-func UnmarshalBase64Manifest(r io.Reader) (manifest.Manifest, error) {
-	var m manifest.Manifest
-
+func decodeBase64File(r io.Reader) ([]byte, error) {
 	var file struct {
 		Encoding, Content string
 	}
@@ -167,27 +168,102 @@ func UnmarshalBase64Manifest(r io.Reader) (manifest.Manifest, error) {
 	json.NewDecoder(r).Decode(&file)
 
 	if file.Encoding != "base64" {
-		return m, errors.New("provider: unexpected file encoding: " + file.Encoding)
+		return nil, errors.New("provider: unexpected file encoding: " + file.Encoding)
 	}
 
-	raw, err := base64.StdEncoding.DecodeString(file.Content)
+	b, err := base64.StdEncoding.DecodeString(file.Content)
 
 	if err != nil {
+		return nil, errors.Err(err)
+	}
+	return b, nil
+}
+
+// UnmarshalBase64Manifest unmarshals the base64 encoded manifest file from the
+// given reader, and resolves its includes with the given manifest.Resolver.
+// The manifest is linted first, so it is held to the same checks as a manifest
+// submitted directly, such as unknown keys being rejected.
+func UnmarshalBase64Manifest(r io.Reader, resolve manifest.Resolver) (manifest.Manifest, error) {
+	var m manifest.Manifest
+
+	raw, err := decodeBase64File(r)
+
+	if err != nil {
+		return m, errors.Err(err)
+	}
+
+	if err := manifest.Lint(raw, resolve).Err(); err != nil {
 		return m, errors.Err(err)
 	}
 
-	m, err = manifest.Unmarshal([]byte(raw))
+	m, err = manifest.Unmarshal(raw)
 
 	if err != nil {
 		return m, errors.Err(err)
 	}
 
-	if err := m.Validate(); err != nil {
+	m, err = m.Resolve(resolve)
+
+	if err != nil {
 		return m, errors.Err(err)
 	}
 	return m, nil
 }
 
+// GetFile returns the decoded content of the base64 encoded file at the given
+// URL.
+func (c *BaseClient) GetFile(rawurl string) ([]byte, error) {
+	url, err := url.Parse(rawurl)
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+
+	req, err := c.Get(url.RequestURI())
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+
+	resp, err := c.Do(req)
+
+	if err != nil {
+		return nil, errors.Err(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.New("file not found")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("provider: unexpected http status for " + rawurl + ": " + resp.Status)
+	}
+	return decodeBase64File(resp.Body)
+}
+
+// IncludeResolver returns a manifest.Resolver that resolves path includes by
+// getting the file from the URL returned by fileurl, and template includes
+// using the given templates Resolver.
+func (c *BaseClient) IncludeResolver(fileurl func(path string) string, templates manifest.Resolver) manifest.Resolver {
+	return func(namespace string, inc manifest.Include) ([]byte, error) {
+		if inc.Template != "" {
+			if templates == nil {
+				return nil, errors.New("cannot resolve templates")
+			}
+			return templates(namespace, inc)
+		}
+
+		path, err := inc.CleanPath()
+
+		if err != nil {
+			return nil, err
+		}
+		return c.GetFile(fileurl(path))
+	}
+}
+
 func (c *BaseClient) LoadManifests(urls []string, unmarshal func(io.Reader) (manifest.Manifest, error)) ([]manifest.Manifest, error) {
 	var wg sync.WaitGroup
 	wg.Add(len(urls))
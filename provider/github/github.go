@@ -377,6 +377,7 @@ func (c Client) SetCommitStatus(r *provider.Repo, status runner.Status, url, sha
 	states := map[runner.Status]string{
 		runner.Queued:             "pending",
 		runner.Running:            "pending",
+		runner.Waiting:            "pending",
 		runner.Passed:             "success",
 		runner.PassedWithFailures: "success",
 		runner.Failed:             "failure",
@@ -413,7 +414,7 @@ func (c Client) SetCommitStatus(r *provider.Repo, status runner.Status, url, sha
 	return nil
 }
 
-func (c Client) Manifests(root, ref string) ([]manifest.Manifest, error) {
+func (c Client) Manifests(root, ref string, templates manifest.Resolver) ([]manifest.Manifest, error) {
 	req, err := c.Get(root + "?ref=" + ref)
 
 	if err != nil {
@@ -446,7 +447,15 @@ func (c Client) Manifests(root, ref string) ([]manifest.Manifest, error) {
 		}
 	}
 
-	mm, err := c.LoadManifests(urls, provider.UnmarshalBase64Manifest)
+	fileurl := func(path string) string {
+		return strings.TrimSuffix(root, ".djinn") + path + "?ref=" + ref
+	}
+
+	resolve := c.IncludeResolver(fileurl, templates)
+
+	mm, err := c.LoadManifests(urls, func(r io.Reader) (manifest.Manifest, error) {
+		return provider.UnmarshalBase64Manifest(r, resolve)
+	})
 
 	if err != nil {
 		return nil, errors.Err(err)
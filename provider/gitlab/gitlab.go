@@ -332,6 +332,7 @@ func (c Client) SetCommitStatus(r *provider.Repo, status runner.Status, url, sha
 	states := map[runner.Status]string{
 		runner.Queued:             "pending",
 		runner.Running:            "running",
+		runner.Waiting:            "pending",
 		runner.Passed:             "success",
 		runner.PassedWithFailures: "success",
 		runner.Failed:             "failed",
@@ -377,7 +378,7 @@ func (c Client) SetCommitStatus(r *provider.Repo, status runner.Status, url, sha
 	return nil
 }
 
-func (c Client) Manifests(root, ref string) ([]manifest.Manifest, error) {
+func (c Client) Manifests(root, ref string, templates manifest.Resolver) ([]manifest.Manifest, error) {
 	req, err := c.Get(root + "?ref=" + ref)
 
 	if err != nil {
@@ -410,7 +411,15 @@ func (c Client) Manifests(root, ref string) ([]manifest.Manifest, error) {
 		}
 	}
 
-	mm, err := c.LoadManifests(urls, provider.UnmarshalBase64Manifest)
+	fileurl := func(path string) string {
+		return strings.TrimSuffix(root, ".djinn") + url.QueryEscape(path) + "?ref=" + ref
+	}
+
+	resolve := c.IncludeResolver(fileurl, templates)
+
+	mm, err := c.LoadManifests(urls, func(r io.Reader) (manifest.Manifest, error) {
+		return provider.UnmarshalBase64Manifest(r, resolve)
+	})
 
 	if err != nil {
 		return nil, errors.Err(err)
@@ -8,6 +8,7 @@ import (
 	"djinn-ci.com/build"
 	"djinn-ci.com/database"
 	"djinn-ci.com/errors"
+	"djinn-ci.com/namespace"
 	"djinn-ci.com/provider"
 	"djinn-ci.com/provider/github"
 	"djinn-ci.com/provider/gitlab"
@@ -35,6 +36,10 @@ func (h Hook) Handler(name string, parseHook provider.ParseWebhookFunc) http.Han
 	}
 	repos := provider.NewRepoStore(h.DB)
 
+	templates := &namespace.TemplateStore{
+		Store: namespace.NewTemplateStore(h.DB),
+	}
+
 	users := user.NewStore(h.DB)
 
 	builds := build.Store{
@@ -133,7 +138,7 @@ func (h Hook) Handler(name string, parseHook provider.ParseWebhookFunc) http.Han
 
 		h.Log.Debug.Println("getting build manifests from", data.DirURL, "at", data.Ref)
 
-		mm, err := cli.Manifests(data.DirURL, data.Ref)
+		mm, err := cli.Manifests(data.DirURL, data.Ref, templates.Resolver(ctx, u))
 
 		if err != nil {
 			h.Log.Error.Println(req.Method, req.URL.Path, errors.Err(err))
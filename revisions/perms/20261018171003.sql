@@ -0,0 +1,12 @@
+/*
+Revision: perms/20261018171003
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Grant permissions on build_job_attempts
+*/
+
+GRANT SELECT ON build_job_attempts TO djinn_scheduler;
+GRANT SELECT, INSERT ON build_job_attempts TO djinn_worker;
+GRANT USAGE ON SEQUENCE build_job_attempts_id_seq TO djinn_worker;
+GRANT SELECT, INSERT, UPDATE, DELETE ON build_job_attempts TO djinn_server;
+GRANT USAGE ON SEQUENCE build_job_attempts_id_seq TO djinn_server;
@@ -0,0 +1,9 @@
+/*
+Revision: perms/20261018204630
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Grant permissions on namespace_templates
+*/
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON namespace_templates TO djinn_server;
+GRANT USAGE ON SEQUENCE namespace_templates_id_seq TO djinn_server;
@@ -0,0 +1,10 @@
+/*
+Revision: perms/20261018213512
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Grant the worker permission to create the artifacts matched by the patterns,
+and directories given in a job
+*/
+
+GRANT INSERT ON build_artifacts TO djinn_worker;
+GRANT USAGE ON SEQUENCE build_artifacts_id_seq TO djinn_worker;
@@ -0,0 +1,11 @@
+/*
+Revision: perms/20261018220530
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Grant the worker permission to restore, and save build caches, and the curator
+permission to evict them
+*/
+
+GRANT SELECT, INSERT, UPDATE ON build_caches TO djinn_worker;
+GRANT USAGE ON SEQUENCE build_caches_id_seq TO djinn_worker;
+GRANT SELECT, DELETE ON build_caches TO djinn_curator;
@@ -0,0 +1,8 @@
+/*
+Revision: schema/20261018101512
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add needs column to build_jobs
+*/
+
+ALTER TABLE build_jobs ADD COLUMN needs VARCHAR NOT NULL DEFAULT '';
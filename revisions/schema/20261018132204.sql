@@ -0,0 +1,10 @@
+/*
+Revision: schema/20261018132204
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add parent and matrix columns to build_jobs for build matrix expansion
+*/
+
+ALTER TABLE build_jobs
+	ADD COLUMN parent VARCHAR NULL,
+	ADD COLUMN matrix JSON NOT NULL DEFAULT '{}';
@@ -0,0 +1,8 @@
+/*
+Revision: schema/20261018153047
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add timeout column to build_jobs, this is stored in nanoseconds
+*/
+
+ALTER TABLE build_jobs ADD COLUMN timeout BIGINT NOT NULL DEFAULT 0;
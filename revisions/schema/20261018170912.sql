@@ -0,0 +1,20 @@
+/*
+Revision: schema/20261018170912
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add retry, and attempt columns to build_jobs, and create the build_job_attempts
+table for storing the output of each failed attempt at a job
+*/
+
+ALTER TABLE build_jobs ADD COLUMN retry JSON NOT NULL DEFAULT '{}';
+ALTER TABLE build_jobs ADD COLUMN attempt INT NOT NULL DEFAULT 1;
+
+CREATE TABLE build_job_attempts (
+	id          SERIAL PRIMARY KEY,
+	job_id      INT NOT NULL REFERENCES build_jobs(id) ON DELETE CASCADE,
+	number      INT NOT NULL,
+	status      status NOT NULL,
+	output      TEXT NULL,
+	started_at  TIMESTAMP NULL,
+	finished_at TIMESTAMP NULL
+);
@@ -0,0 +1,9 @@
+/*
+Revision: schema/20261018181420
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add skipped to the status type for jobs that are filtered out by the rules in
+the build manifest
+*/
+
+ALTER TYPE status ADD VALUE 'skipped';
@@ -0,0 +1,10 @@
+/*
+Revision: schema/20261018193155
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add always, and after_failure columns to build_stages for the stages that are
+executed once every other stage has finished
+*/
+
+ALTER TABLE build_stages ADD COLUMN always BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE build_stages ADD COLUMN after_failure BOOLEAN NOT NULL DEFAULT FALSE;
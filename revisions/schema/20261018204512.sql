@@ -0,0 +1,19 @@
+/*
+Revision: schema/20261018204512
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Create the namespace_templates table for storing manifest templates that can
+be included by the manifests submitted to a namespace
+*/
+
+CREATE TABLE namespace_templates (
+	id           SERIAL PRIMARY KEY,
+	user_id      INT NOT NULL REFERENCES users(id),
+	author_id    INT NOT NULL REFERENCES users(id),
+	namespace_id INT NOT NULL REFERENCES namespaces(id) ON DELETE CASCADE,
+	name         VARCHAR NOT NULL,
+	content      TEXT NOT NULL,
+	created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (namespace_id, name)
+);
@@ -0,0 +1,10 @@
+/*
+Revision: schema/20261018211037
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add env, and workdir columns to build_jobs for the environment variables, and
+working directory declared on each job
+*/
+
+ALTER TABLE build_jobs ADD COLUMN env JSON NOT NULL DEFAULT '[]';
+ALTER TABLE build_jobs ADD COLUMN workdir VARCHAR NOT NULL DEFAULT '';
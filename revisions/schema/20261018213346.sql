@@ -0,0 +1,10 @@
+/*
+Revision: schema/20261018213346
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add artifact_paths column to build_jobs for the artifacts to collect from each
+job, these may be directories, or patterns whose files are not known until
+they are collected
+*/
+
+ALTER TABLE build_jobs ADD COLUMN artifact_paths JSON NOT NULL DEFAULT '{}';
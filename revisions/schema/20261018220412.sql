@@ -0,0 +1,23 @@
+/*
+Revision: schema/20261018220412
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Create build_caches table for the caches that are restored into, and saved
+from the jobs of a build. Caches are shared between the builds of a namespace,
+or between the builds of a user outside of a namespace, so each key is unique
+within either scope
+*/
+
+CREATE TABLE build_caches (
+	id           SERIAL PRIMARY KEY,
+	user_id      INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	namespace_id INT NULL REFERENCES namespaces(id) ON DELETE CASCADE,
+	key          VARCHAR NOT NULL,
+	hash         VARCHAR NOT NULL UNIQUE,
+	size         BIGINT NOT NULL DEFAULT 0,
+	created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+	used_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX ON build_caches (namespace_id, key) WHERE namespace_id IS NOT NULL;
+CREATE UNIQUE INDEX ON build_caches (user_id, key) WHERE namespace_id IS NULL;
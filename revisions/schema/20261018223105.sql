@@ -0,0 +1,9 @@
+/*
+Revision: schema/20261018223105
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add inputs column to build_jobs for the artifacts of earlier jobs that are
+placed before a job is executed
+*/
+
+ALTER TABLE build_jobs ADD COLUMN inputs JSON NOT NULL DEFAULT '{}';
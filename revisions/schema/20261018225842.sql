@@ -0,0 +1,10 @@
+/*
+Revision: schema/20261018225842
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add shell, and quiet columns to build_jobs for the shell a job is executed
+with, and whether its commands are echoed
+*/
+
+ALTER TABLE build_jobs ADD COLUMN shell VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE build_jobs ADD COLUMN quiet BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,10 @@
+/*
+Revision: schema/20261018232714
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add command_results column to build_jobs, and build_job_attempts for the exit
+code, and timing of each command executed in a job
+*/
+
+ALTER TABLE build_jobs ADD COLUMN command_results JSON NOT NULL DEFAULT '[]';
+ALTER TABLE build_job_attempts ADD COLUMN command_results JSON NOT NULL DEFAULT '[]';
@@ -0,0 +1,9 @@
+/*
+Revision: schema/20261018235210
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add waiting to the status type for builds, and jobs that are waiting for a
+manual job to be approved
+*/
+
+ALTER TYPE status ADD VALUE 'waiting';
@@ -0,0 +1,11 @@
+/*
+Revision: schema/20261018235531
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add manual column to build_jobs for jobs that have to be approved before they
+are executed, along with who approved the job and when
+*/
+
+ALTER TABLE build_jobs ADD COLUMN manual BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE build_jobs ADD COLUMN approved_by INT NULL REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE build_jobs ADD COLUMN approved_at TIMESTAMP NULL;
@@ -0,0 +1,10 @@
+/*
+Revision: schema/20261019000412
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add params column to builds and cron for the values of the params declared in
+the manifest
+*/
+
+ALTER TABLE builds ADD COLUMN params JSON NOT NULL DEFAULT '{}';
+ALTER TABLE cron ADD COLUMN params JSON NOT NULL DEFAULT '{}';
@@ -0,0 +1,9 @@
+/*
+Revision: schema/20261019001027
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add defaults column to namespaces for the settings that are merged into every
+manifest submitted to the namespace
+*/
+
+ALTER TABLE namespaces ADD COLUMN defaults TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+/*
+Revision: schema/20261019001544
+Author:   Andrew Pillar <me@andrewpillar.com>
+
+Add sandbox to the driver_type enum for the sandbox driver
+*/
+
+ALTER TYPE driver_type ADD VALUE 'sandbox';
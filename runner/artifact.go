@@ -0,0 +1,185 @@
+package runner
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"djinn-ci.com/errors"
+)
+
+// Artifact is a single artifact to collect from a job. The Source is either
+// the path to a file, the path to a directory, or a glob pattern matching the
+// files to collect. Patterns are matched against the paths of files, and
+// support ** for matching any number of directories. If the Name of the
+// artifact ends in .tar.gz, .tgz, or .zip, and the Source is a directory or a
+// pattern, then the files are packaged into a single archive of that Name.
+type Artifact struct {
+	Source string
+	Name   string
+}
+
+// Artifacts returns the artifacts in the given Passthrough, sorted by their
+// source.
+func Artifacts(pt Passthrough) []Artifact {
+	aa := make([]Artifact, 0, len(pt))
+
+	for src, dst := range pt {
+		aa = append(aa, Artifact{
+			Source: src,
+			Name:   dst,
+		})
+	}
+
+	sort.Slice(aa, func(i, j int) bool {
+		return aa[i].Source < aa[j].Source
+	})
+	return aa
+}
+
+func isPattern(s string) bool { return strings.ContainsAny(s, "*?[") }
+
+// IsPattern returns whether the source of the artifact is a glob pattern.
+func (a Artifact) IsPattern() bool { return isPattern(a.Source) }
+
+// IsDir returns whether the source of the artifact is explicitly given as a
+// directory, that is, with a trailing slash.
+func (a Artifact) IsDir() bool { return strings.HasSuffix(a.Source, "/") }
+
+// Format returns the archive format the files of the artifact are packaged
+// in, this will either be tar.gz, zip, or empty if the files are collected
+// as they are. Names with a wildcard are never archives.
+func (a Artifact) Format() string {
+	switch {
+	case strings.Contains(a.Name, "*"):
+		return ""
+	case strings.HasSuffix(a.Name, ".tar.gz"), strings.HasSuffix(a.Name, ".tgz"):
+		return "tar.gz"
+	case strings.HasSuffix(a.Name, ".zip"):
+		return "zip"
+	}
+	return ""
+}
+
+// Known returns whether the name of the collected artifact is known before
+// the artifact is collected. This is true for single files, and archives.
+func (a Artifact) Known() bool {
+	if a.IsPattern() || a.IsDir() {
+		return a.Format() != ""
+	}
+	return true
+}
+
+// Root returns the path to begin looking for the files of the artifact from.
+// For patterns this is the directory leading up to the first element with a
+// wildcard, otherwise it is the source itself.
+func (a Artifact) Root() string {
+	src := path.Clean(a.Source)
+
+	if !isPattern(src) {
+		return src
+	}
+
+	parts := strings.Split(src, "/")
+	root := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		if isPattern(part) {
+			break
+		}
+		root = append(root, part)
+	}
+
+	if len(root) == 0 {
+		return "."
+	}
+	if root[0] == "" {
+		return "/" + path.Join(root...)
+	}
+	return path.Join(root...)
+}
+
+// Match returns whether the file at the given path should be collected for
+// the artifact.
+func (a Artifact) Match(name string) bool {
+	if !a.IsPattern() {
+		return true
+	}
+	return matchPath(strings.Split(path.Clean(a.Source), "/"), strings.Split(path.Clean(name), "/"))
+}
+
+func matchPath(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchPath(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
+
+// FileName returns the name the file at the given path is collected as when
+// the artifact is not a single file, or an archive. If the name of the
+// artifact is a pattern then any wildcard in the name is replaced with the
+// name of the file, unless the name matches the file. Otherwise the file
+// keeps its own name.
+func (a Artifact) FileName(name string) string {
+	base := path.Base(name)
+
+	if !strings.Contains(a.Name, "*") {
+		return base
+	}
+
+	if ok, _ := path.Match(a.Name, base); ok {
+		return base
+	}
+	return strings.Replace(a.Name, "*", base, -1)
+}
+
+// Produces returns whether a file with the given name could be collected for
+// the artifact. This is always true for a directory, or pattern whose files
+// keep their own names, since those are not known until they are collected.
+func (a Artifact) Produces(name string) bool {
+	if a.Known() {
+		return a.Name == name
+	}
+
+	if !strings.Contains(a.Name, "*") {
+		return true
+	}
+
+	ok, _ := path.Match(a.Name, name)
+	return ok
+}
+
+// Validate checks the source of the artifact is a valid pattern, and that
+// the name of the artifact is a file name.
+func (a Artifact) Validate() error {
+	if strings.TrimSpace(a.Source) == "" {
+		return errors.New("artifact has no source")
+	}
+
+	if _, err := path.Match(a.Source, ""); err != nil {
+		return errors.New("artifact " + a.Source + " has invalid pattern")
+	}
+
+	if a.Name == "" || strings.Contains(a.Name, "/") {
+		return errors.New("artifact " + a.Source + " has invalid name " + a.Name)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package runner
+
+import "testing"
+
+func Test_ArtifactMatch(t *testing.T) {
+	tests := []struct {
+		src   string
+		name  string
+		root  string
+		match bool
+	}{
+		{"dist/**/*.tar.gz", "dist/djinn-1.0.tar.gz", "dist", true},
+		{"dist/**/*.tar.gz", "dist/linux/amd64/djinn-1.0.tar.gz", "dist", true},
+		{"dist/**/*.tar.gz", "dist/linux/djinn-1.0.zip", "dist", false},
+		{"dist/**/*.tar.gz", "build/djinn-1.0.tar.gz", "dist", false},
+		{"*.log", "test.log", ".", true},
+		{"*.log", "logs/test.log", ".", false},
+		{"out/*/bin", "out/linux/bin", "out", true},
+		{"dist", "dist/djinn", "dist", true},
+	}
+
+	for i, test := range tests {
+		a := Artifact{Source: test.src}
+
+		if root := a.Root(); root != test.root {
+			t.Errorf("tests[%d] - unexpected root, expected=%q, got=%q\n", i, test.root, root)
+		}
+
+		if match := a.Match(test.name); match != test.match {
+			t.Errorf("tests[%d] - unexpected match for %q, expected=%v, got=%v\n", i, test.name, test.match, match)
+		}
+	}
+}
+
+func Test_ArtifactFileName(t *testing.T) {
+	tests := []struct {
+		dst      string
+		name     string
+		expected string
+	}{
+		{"*.tar.gz", "dist/djinn-1.0.tar.gz", "djinn-1.0.tar.gz"},
+		{"release-*", "dist/djinn-1.0.tar.gz", "release-djinn-1.0.tar.gz"},
+		{"dist", "dist/linux/djinn", "djinn"},
+	}
+
+	for i, test := range tests {
+		a := Artifact{Source: "dist/", Name: test.dst}
+
+		if name := a.FileName(test.name); name != test.expected {
+			t.Errorf("tests[%d] - unexpected name, expected=%q, got=%q\n", i, test.expected, name)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"text/template"
+
+	"djinn-ci.com/errors"
+
+	"github.com/andrewpillar/fs"
+)
+
+// Cache is the cache that is restored into the environment of a job before
+// it is executed, and saved from it once the job has passed.
+type Cache struct {
+	// Key is the template the key of the cache is resolved from. This is
+	// executed with Vars as its data, and has the checksum function for
+	// getting the checksum of files in the environment of the job, for
+	// example,
+	//
+	//	go-{{ checksum "go.sum" }}-{{ .Branch }}
+	Key string
+
+	// Vars is the data the Key template is executed with.
+	Vars map[string]string
+
+	// Paths are the paths in the environment of the job that are saved in
+	// the cache.
+	Paths []string
+
+	// Store is where caches are restored from, and saved to. Each cache is
+	// stored under its resolved key.
+	Store fs.FS
+}
+
+func parseCacheKey(key string, checksum func(names ...string) (string, error)) (*template.Template, error) {
+	t, err := template.New("key").Option("missingkey=zero").Funcs(template.FuncMap{
+		"checksum": checksum,
+	}).Parse(key)
+
+	if err != nil {
+		return nil, errors.New("invalid cache key: " + strings.TrimPrefix(err.Error(), "template: "))
+	}
+	return t, nil
+}
+
+// ParseCacheKey checks that the given cache key is a valid template.
+func ParseCacheKey(key string) error {
+	_, err := parseCacheKey(key, func(...string) (string, error) { return "", nil })
+	return err
+}
+
+// ResolveKey resolves the key of the cache. The given read function is used
+// for reading the files in the environment of the job that are given to the
+// checksum function of the key template. Any slashes, or whitespace in the
+// resolved key are replaced with a hyphen.
+func (c *Cache) ResolveKey(read func(name string) ([]byte, error)) (string, error) {
+	checksum := func(names ...string) (string, error) {
+		h := sha256.New()
+
+		for _, name := range names {
+			b, err := read(name)
+
+			if err != nil {
+				return "", errors.New("checksum " + name + ": " + errors.Cause(err).Error())
+			}
+			h.Write(b)
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	t, err := parseCacheKey(c.Key, checksum)
+
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+
+	if err := t.Execute(&buf, c.Vars); err != nil {
+		return "", errors.New("invalid cache key: " + strings.TrimPrefix(errors.Cause(err).Error(), "template: "))
+	}
+
+	key := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' || r == '\t' || r == '\n' {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(buf.String()))
+
+	if key == "" {
+		return "", errors.New("cache key is empty")
+	}
+	return key, nil
+}
@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_CacheResolveKey(t *testing.T) {
+	files := map[string][]byte{
+		"go.sum": []byte("github.com/andrewpillar/fs v0.0.0\n"),
+	}
+
+	read := func(name string) ([]byte, error) {
+		b, ok := files[name]
+
+		if !ok {
+			return nil, errors.New("no such file")
+		}
+		return b, nil
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+		err      bool
+	}{
+		{"go-{{ .Branch }}", "go-feature-cache", false},
+		{"{{ .Namespace }}-{{ .Missing }}", "djinn-", false},
+		{`go-{{ checksum "go.sum" }}`, "go-a865c3d1b7c383344ba6058c07131f43f751fa273c0cbd795c2091b91cb00639", false},
+		{`go-{{ checksum "go.mod" }}`, "", true},
+		{"{{ .Missing }}", "", true},
+		{"go-{{ .Branch", "", true},
+	}
+
+	for i, test := range tests {
+		c := Cache{
+			Key: test.key,
+			Vars: map[string]string{
+				"Branch":    "feature/cache",
+				"Namespace": "djinn",
+			},
+		}
+
+		key, err := c.ResolveKey(read)
+
+		if err != nil {
+			if !test.err {
+				t.Errorf("tests[%d] - unexpected error: %s\n", i, err)
+			}
+			continue
+		}
+
+		if test.err {
+			t.Errorf("tests[%d] - expected error, got key %q\n", i, key)
+			continue
+		}
+
+		if key != test.expected {
+			t.Errorf("tests[%d] - unexpected key, expected=%q, got=%q\n", i, test.expected, key)
+		}
+	}
+}
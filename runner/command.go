@@ -0,0 +1,317 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"djinn-ci.com/errors"
+)
+
+// commandMarker is the prefix of the marker lines written to the output of a
+// job to denote where the output of each command begins.
+const commandMarker = "##djinn:cmd:"
+
+// CommandMarker returns the marker line to write to the output of a job before
+// the command at the given index in the job's commands is executed. If the
+// index is negative then the marker denoting that every command has been
+// executed is returned. Marker lines are removed from the output of the job
+// by the Runner, and are used to record the CommandResult of each command.
+func CommandMarker(i int) string {
+	if i < 0 {
+		return commandMarker + "end"
+	}
+	return commandMarker + strconv.Itoa(i+1)
+}
+
+// CommandResult is the result of executing a single command of a job. Line is
+// the number of the line in the output of the job at which the output of the
+// command begins, starting from 0, and Lines is the number of lines of output
+// the command produced. If the command did not finish, because the job was
+// killed or timed out, then the ExitCode will be -1.
+type CommandResult struct {
+	Command   string        `json:"command"`
+	ExitCode  int           `json:"exit_code"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Line      int           `json:"line"`
+	Lines     int           `json:"lines"`
+}
+
+// tracePrefix is the prefix PowerShell gives to each statement it traces.
+// The statement writing a marker is traced along with every other statement,
+// so lines with this prefix are held until complete to check for a marker.
+const tracePrefix = "DEBUG:"
+
+// maxHeld is the most output held back from the underlying io.Writer while
+// waiting to see if it is a marker line, or a traced marker.
+const maxHeld = 256
+
+// The states of the commandWriter, these determine how each byte of output
+// is handled.
+const (
+	lineStart  = iota // the start of a line, which may be a marker line
+	lineOutput        // a line of output that may end with a marker
+	lineMarker        // a marker line
+	lineTrace         // a traced statement that may have written a marker
+)
+
+// commandWriter records the result of each command of a job from the marker
+// lines written to the output of the job. Output is passed through to the
+// underlying io.Writer as it is written, with the marker lines removed. Only
+// the bytes that may be part of a marker are held back, up to maxHeld.
+type commandWriter struct {
+	io.Writer
+
+	mu    sync.Mutex
+	job   *Job
+	state int
+	held  []byte
+	out   []byte
+	last  byte
+	lines int
+	curr  int
+}
+
+// newCommandWriter sets the Writer of the given job to a commandWriter
+// wrapping the job's current Writer. The results of the job are reset.
+func newCommandWriter(j *Job) *commandWriter {
+	w := &commandWriter{
+		Writer: j.Writer,
+		job:    j,
+		curr:   -1,
+	}
+
+	j.results = nil
+	j.Writer = w
+	return w
+}
+
+// end marks the current command as finished with the given exit code.
+func (w *commandWriter) end(code int) {
+	if w.curr < 0 {
+		return
+	}
+
+	res := &w.job.results[w.curr]
+	res.ExitCode = code
+	res.Duration = time.Since(res.StartedAt)
+	res.Lines = w.lines - res.Line
+
+	w.curr = -1
+}
+
+// marker handles the given marker, with the marker prefix stripped.
+func (w *commandWriter) marker(s string) {
+	if strings.HasPrefix(s, "end") {
+		w.end(0)
+		return
+	}
+
+	end := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+
+	if end < 0 {
+		end = len(s)
+	}
+
+	n, err := strconv.Atoi(s[:end])
+
+	// The same marker may be seen more than once if the shell traces the
+	// statement that writes it.
+	if err != nil || n <= len(w.job.results) || n > len(w.job.Commands) {
+		return
+	}
+
+	w.end(0)
+
+	w.job.results = append(w.job.results, CommandResult{
+		Command:   w.job.Commands[n-1],
+		ExitCode:  -1,
+		StartedAt: time.Now(),
+		Line:      w.lines,
+	})
+	w.curr = len(w.job.results) - 1
+}
+
+// emit appends the given bytes to the output to be written, counting the
+// lines of output.
+func (w *commandWriter) emit(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	w.out = append(w.out, b...)
+	w.lines += bytes.Count(b, []byte{'\n'})
+	w.last = b[len(b)-1]
+}
+
+// release emits the held bytes as output.
+func (w *commandWriter) release() {
+	w.emit(w.held)
+	w.held = w.held[:0]
+
+	w.state = lineOutput
+
+	if w.last == '\n' {
+		w.state = lineStart
+	}
+}
+
+// feed handles the given byte of output based on the current state of the
+// writer.
+func (w *commandWriter) feed(b byte) {
+	marker := []byte(commandMarker)
+
+	w.held = append(w.held, b)
+
+	switch w.state {
+	case lineStart:
+		if bytes.HasPrefix(marker, w.held) {
+			if len(w.held) == len(marker) {
+				w.state = lineMarker
+			}
+			return
+		}
+
+		if bytes.HasPrefix([]byte(tracePrefix), w.held) {
+			if len(w.held) == len(tracePrefix) {
+				w.state = lineTrace
+			}
+			return
+		}
+
+		w.emit(w.held[:len(w.held)-1])
+		w.held = w.held[:0]
+		w.state = lineOutput
+		w.feed(b)
+	case lineOutput:
+		if b == '\n' {
+			w.release()
+			return
+		}
+
+		if bytes.HasPrefix(marker, w.held) {
+			if len(w.held) < len(marker) {
+				return
+			}
+
+			// A marker preceded by a quote is output that happens to
+			// contain a marker, otherwise anything before the marker is
+			// output that did not end with a newline.
+			if w.last == '\'' || w.last == '"' {
+				w.release()
+				return
+			}
+
+			w.emit([]byte{'\n'})
+			w.state = lineMarker
+			return
+		}
+
+		// Hold back the longest suffix that may still be the start of a
+		// marker.
+		i := 1
+
+		for ; i < len(w.held); i++ {
+			if bytes.HasPrefix(marker, w.held[i:]) {
+				break
+			}
+		}
+
+		w.emit(w.held[:i])
+		w.held = append(w.held[:0], w.held[i:]...)
+	case lineMarker:
+		if b == '\n' {
+			w.marker(string(w.held[len(marker):]))
+			w.held = w.held[:0]
+			w.state = lineStart
+			return
+		}
+
+		if len(w.held) > maxHeld {
+			w.release()
+		}
+	case lineTrace:
+		if b == '\n' {
+			w.trace()
+			return
+		}
+
+		if len(w.held) > maxHeld {
+			w.release()
+		}
+	}
+}
+
+// trace handles the held line of traced output. If a quoted marker is in the
+// line then it is the shell tracing the statement that wrote the marker, and
+// the line is removed from the output.
+func (w *commandWriter) trace() {
+	i := bytes.Index(w.held, []byte(commandMarker))
+
+	if i <= 0 || (w.held[i-1] != '\'' && w.held[i-1] != '"') {
+		w.release()
+		return
+	}
+
+	w.marker(string(w.held[i+len(commandMarker):]))
+	w.held = w.held[:0]
+	w.state = lineStart
+}
+
+func (w *commandWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.out = w.out[:0]
+
+	for _, b := range p {
+		w.feed(b)
+	}
+
+	if len(w.out) > 0 {
+		if _, err := w.Writer.Write(w.out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// finish writes any remaining output, and marks the current command as
+// finished with the exit code from the given error.
+func (w *commandWriter) finish(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.out = w.out[:0]
+
+	switch w.state {
+	case lineMarker:
+		w.marker(string(w.held[len(commandMarker):]))
+		w.held = w.held[:0]
+	case lineTrace:
+		w.trace()
+	}
+
+	w.release()
+
+	if len(w.out) > 0 {
+		w.Writer.Write(w.out)
+	}
+
+	code := 0
+
+	if err != nil {
+		code = -1
+
+		var exitErr *ExitError
+
+		if errors.As(err, &exitErr) {
+			code = exitErr.Code
+		}
+	}
+	w.end(code)
+}
@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_CommandWriter(t *testing.T) {
+	tests := []struct {
+		output   []string
+		err      error
+		expected string
+		results  []CommandResult
+	}{
+		{
+			[]string{
+				"Restoring cache\n",
+				CommandMarker(0) + "\n",
+				"+ make\nbuilding",
+				"...\n",
+				CommandMarker(1) + "\n",
+				"+ make test\nok\n",
+				CommandMarker(-1) + "\n",
+				"Collecting artifact\n",
+			},
+			nil,
+			"Restoring cache\n+ make\nbuilding...\n+ make test\nok\nCollecting artifact\n",
+			[]CommandResult{
+				{Command: "make", ExitCode: 0, Line: 1, Lines: 2},
+				{Command: "make test", ExitCode: 0, Line: 3, Lines: 2},
+			},
+		},
+		{
+			[]string{
+				"DEBUG:    7+  >>>> [Console]::WriteLine('" + CommandMarker(0) + "')\n",
+				CommandMarker(0) + "\n",
+				"no newline",
+				CommandMarker(1) + "\n",
+				"make: *** [test] Error 2\n",
+			},
+			&ExitError{Code: 2},
+			"no newline\nmake: *** [test] Error 2\n",
+			[]CommandResult{
+				{Command: "make", ExitCode: 0, Line: 0, Lines: 1},
+				{Command: "make test", ExitCode: 2, Line: 1, Lines: 1},
+			},
+		},
+		{
+			[]string{CommandMarker(0) + "\n", "sleep\n"},
+			ErrTimedOut,
+			"sleep\n",
+			[]CommandResult{
+				{Command: "make", ExitCode: -1, Line: 0, Lines: 1},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		var buf bytes.Buffer
+
+		j := &Job{
+			Writer:   &buf,
+			Commands: []string{"make", "make test"},
+		}
+
+		w := newCommandWriter(j)
+
+		for _, s := range test.output {
+			io.WriteString(j.Writer, s)
+		}
+
+		w.finish(test.err)
+
+		if s := buf.String(); s != test.expected {
+			t.Errorf("tests[%d] - unexpected output, expected=%q, got=%q\n", i, test.expected, s)
+		}
+
+		results := j.Results()
+
+		if len(results) != len(test.results) {
+			t.Errorf("tests[%d] - unexpected number of results, expected=%d, got=%d\n", i, len(test.results), len(results))
+			continue
+		}
+
+		for k, res := range results {
+			expected := test.results[k]
+
+			if res.Command != expected.Command || res.ExitCode != expected.ExitCode || res.Line != expected.Line || res.Lines != expected.Lines {
+				t.Errorf("tests[%d] - results[%d] - expected=%+v, got=%+v\n", i, k, expected, res)
+			}
+		}
+	}
+}
+
+func Test_CommandWriterStream(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{"Downloading 50%", "Downloading 50%"},
+		{"Continue? [y/N] ", "Continue? [y/N] "},
+		{"##djinn", ""},
+		{"progress ##dj", "progress "},
+		{"echo '" + CommandMarker(0) + "'\n", "echo '" + CommandMarker(0) + "'\n"},
+		{"DEBUG: tracing", ""},
+		{CommandMarker(0) + strings.Repeat("x", maxHeld), CommandMarker(0) + strings.Repeat("x", maxHeld)},
+		{"DEBUG:" + strings.Repeat("x", maxHeld), "DEBUG:" + strings.Repeat("x", maxHeld)},
+	}
+
+	for i, test := range tests {
+		var buf bytes.Buffer
+
+		j := &Job{
+			Writer:   &buf,
+			Commands: []string{"make"},
+		}
+
+		newCommandWriter(j)
+
+		io.WriteString(j.Writer, test.output)
+
+		if s := buf.String(); s != test.expected {
+			t.Errorf("tests[%d] - unexpected output, expected=%q, got=%q\n", i, test.expected, s)
+		}
+	}
+}
@@ -61,7 +61,7 @@ func (d OS) Create(_ context.Context, env []string, pt Passthrough, objects fs.F
 	return nil
 }
 
-func (d OS) Execute(j *Job, artifacts fs.FS) error {
+func (d OS) Execute(ctx context.Context, j *Job, artifacts fs.FS) error {
 	for _, cmdline := range j.Commands {
 		r := csv.NewReader(strings.NewReader(cmdline))
 		r.Comma = ' '
@@ -72,7 +72,8 @@ func (d OS) Execute(j *Job, artifacts fs.FS) error {
 			continue
 		}
 
-		cmd := exec.Command(args[0], args[1:]...)
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Env = append(os.Environ(), j.Env...)
 		cmd.Stdout = j.Writer
 		cmd.Stderr = j.Writer
 
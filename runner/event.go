@@ -0,0 +1,194 @@
+package runner
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/andrewpillar/fs"
+)
+
+// EventType is the type of an Event emitted from a Runner.
+type EventType string
+
+const (
+	RunStart          EventType = "run.start"          // RunStart is emitted when the Runner begins running.
+	RunFinish         EventType = "run.finish"         // RunFinish is emitted with the final status of the run.
+	JobStart          EventType = "job.start"          // JobStart is emitted when a job begins executing.
+	JobWait           EventType = "job.wait"           // JobWait is emitted when a manual job starts waiting for approval.
+	JobOutput         EventType = "job.output"         // JobOutput is emitted for each chunk of output from a job, or the driver.
+	JobRetry          EventType = "job.retry"          // JobRetry is emitted when a failed attempt at a job is retried.
+	JobFinish         EventType = "job.finish"         // JobFinish is emitted with the status, and duration of a finished job.
+	ArtifactCollected EventType = "artifact.collected" // ArtifactCollected is emitted for each artifact collected from a job.
+)
+
+// Event is a single event that happened during a run. The Job is the full
+// name of the job the event is for, if any. Duration is given in seconds.
+// Errors are the errors of a job that did not pass, and Commands the result of
+// each command executed in the job.
+type Event struct {
+	Type     EventType `json:"type"`
+	Time     time.Time `json:"time"`
+	Job      string    `json:"job,omitempty"`
+	Status   *Status   `json:"status,omitempty"`
+	Attempt  int       `json:"attempt,omitempty"`
+	Duration float64   `json:"duration,omitempty"`
+	Output   string    `json:"output,omitempty"`
+	Artifact string    `json:"artifact,omitempty"`
+	Size     int64     `json:"size,omitempty"`
+	Errors   []string  `json:"errors,omitempty"`
+
+	Commands []CommandResult `json:"commands,omitempty"`
+}
+
+// Events encodes the events of a Runner as JSON lines to an io.Writer. This
+// is safe for concurrent use, so jobs executing concurrently can write their
+// output at the same time.
+type Events struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	started map[*Job]time.Time
+}
+
+// NewEvents returns Events for encoding the events of a Runner to the given
+// io.Writer.
+func NewEvents(w io.Writer) *Events {
+	return &Events{
+		enc:     json.NewEncoder(w),
+		started: make(map[*Job]time.Time),
+	}
+}
+
+// Emit encodes the given Event. If the Event has no time then the current
+// time is used.
+func (e *Events) Emit(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.enc.Encode(ev)
+}
+
+type eventWriter struct {
+	events *Events
+	job    *Job
+}
+
+func (w eventWriter) Write(p []byte) (int, error) {
+	ev := Event{
+		Type:   JobOutput,
+		Output: string(p),
+	}
+
+	if w.job != nil {
+		ev.Job = w.job.FullName()
+	}
+
+	w.events.Emit(ev)
+	return len(p), nil
+}
+
+// Writer returns an io.Writer that emits everything written to it as the
+// output of the given job. If the job is nil then the output is not tagged
+// with a job, this is used for the output of the driver.
+func (e *Events) Writer(j *Job) io.Writer {
+	return eventWriter{
+		events: e,
+		job:    j,
+	}
+}
+
+func (e *Events) statusEvent(typ EventType, j *Job) Event {
+	status := j.Status()
+
+	return Event{
+		Type:    typ,
+		Job:     j.FullName(),
+		Status:  &status,
+		Attempt: j.Attempt(),
+	}
+}
+
+// Attach sets the handlers of the given Runner so that its events are
+// emitted. This replaces any handlers already set on the Runner. Jobs that do
+// not belong to a stage of the Runner are ignored, these are the placeholders
+// for driver creation when there is no job for it.
+func (e *Events) Attach(r *Runner) {
+	r.HandleRunStart(func(Status) {
+		e.Emit(Event{Type: RunStart})
+	})
+
+	r.HandleRunComplete(func(status Status) {
+		e.Emit(Event{
+			Type:   RunFinish,
+			Status: &status,
+		})
+	})
+
+	r.HandleJobStart(func(j *Job) {
+		if j.stage == "" {
+			return
+		}
+
+		now := time.Now()
+
+		e.mu.Lock()
+		e.started[j] = now
+		e.mu.Unlock()
+
+		ev := e.statusEvent(JobStart, j)
+		ev.Time = now
+
+		e.Emit(ev)
+	})
+
+	r.HandleJobWait(func(j *Job) {
+		e.Emit(e.statusEvent(JobWait, j))
+	})
+
+	r.HandleJobRetry(func(j *Job) {
+		if j.stage == "" {
+			return
+		}
+		e.Emit(e.statusEvent(JobRetry, j))
+	})
+
+	r.HandleJobComplete(func(j *Job) {
+		if j.stage == "" {
+			return
+		}
+
+		ev := e.statusEvent(JobFinish, j)
+		ev.Time = time.Now()
+
+		for _, err := range j.errs {
+			ev.Errors = append(ev.Errors, err.Error())
+		}
+
+		ev.Commands = j.Results()
+
+		e.mu.Lock()
+
+		if start, ok := e.started[j]; ok {
+			ev.Duration = ev.Time.Sub(start).Seconds()
+			delete(e.started, j)
+		}
+
+		e.mu.Unlock()
+
+		e.Emit(ev)
+	})
+
+	r.HandleArtifact(func(j *Job, info fs.FileInfo) {
+		e.Emit(Event{
+			Type:     ArtifactCollected,
+			Job:      j.FullName(),
+			Artifact: info.Name(),
+			Size:     info.Size(),
+		})
+	})
+}
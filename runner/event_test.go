@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/andrewpillar/fs"
+)
+
+type artifactDriver struct {
+	*recordDriver
+}
+
+func (d artifactDriver) Execute(ctx context.Context, j *Job, artifacts fs.FS) error {
+	if err := d.recordDriver.Execute(ctx, j, artifacts); err != nil {
+		return errors.New("build failed")
+	}
+
+	io.WriteString(j.Writer, "building "+j.Name+"\n")
+
+	f, err := fs.ReadFile(j.Name+".tar", strings.NewReader("archive"))
+
+	if err != nil {
+		return err
+	}
+
+	defer fs.Cleanup(f)
+
+	_, err = artifacts.Put(f)
+	return err
+}
+
+func Test_Events(t *testing.T) {
+	var buf bytes.Buffer
+
+	r := Runner{
+		Writer:    io.Discard,
+		Objects:   fs.New(""),
+		Artifacts: fs.New(t.TempDir()),
+	}
+
+	events := NewEvents(&buf)
+	events.Attach(&r)
+
+	stage := Stage{
+		Name: "build",
+	}
+
+	stage.Add(
+		&Job{Name: "compile", Commands: []string{"true"}},
+		&Job{Name: "test", Needs: []string{"compile"}, Commands: []string{"true"}},
+	)
+
+	for _, j := range stage.Jobs() {
+		j.Writer = events.Writer(j)
+	}
+
+	d := artifactDriver{
+		recordDriver: &recordDriver{
+			Writer: events.Writer(nil),
+			fail: map[string]struct{}{
+				"test": {},
+			},
+		},
+	}
+
+	r.Add(&stage)
+	r.Run(context.Background(), d)
+
+	expected := []struct {
+		typ    EventType
+		job    string
+		status string
+	}{
+		{RunStart, "", ""},
+		{JobStart, "build/compile", "running"},
+		{JobOutput, "build/compile", ""},
+		{ArtifactCollected, "build/compile", ""},
+		{JobFinish, "build/compile", "passed"},
+		{JobStart, "build/test", "running"},
+		{JobFinish, "build/test", "failed"},
+		{RunFinish, "", "failed"},
+	}
+
+	sc := bufio.NewScanner(&buf)
+
+	i := 0
+
+	for ; sc.Scan(); i++ {
+		var ev struct {
+			Type     EventType
+			Job      string
+			Status   string
+			Artifact string
+			Size     int64
+			Errors   []string
+		}
+
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatal(err)
+		}
+
+		if i >= len(expected) {
+			t.Fatalf("unexpected event %s\n", sc.Text())
+		}
+
+		exp := expected[i]
+
+		if ev.Type != exp.typ || ev.Job != exp.job || ev.Status != exp.status {
+			t.Errorf("events[%d] - unexpected event, expected=%s %q %q, got=%s\n", i, exp.typ, exp.job, exp.status, sc.Text())
+		}
+
+		if ev.Type == ArtifactCollected && (ev.Artifact != "compile.tar" || ev.Size != 7) {
+			t.Errorf("events[%d] - unexpected artifact, got=%s\n", i, sc.Text())
+		}
+
+		if ev.Type == JobFinish && ev.Status == "failed" && len(ev.Errors) == 0 {
+			t.Errorf("events[%d] - expected errors for failed job\n", i)
+		}
+	}
+
+	if i != len(expected) {
+		t.Errorf("unexpected number of events, expected=%d, got=%d\n", len(expected), i)
+	}
+}
@@ -0,0 +1,299 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Report collects the output of the jobs of a Runner, so that a report of
+// each job can be written once the Runner has finished. Reports can be written
+// in the JUnit XML, or TAP formats.
+type Report struct {
+	mu     sync.Mutex
+	output map[*Job]*bytes.Buffer
+}
+
+// NewReport returns an empty Report.
+func NewReport() *Report {
+	return &Report{
+		output: make(map[*Job]*bytes.Buffer),
+	}
+}
+
+type reportWriter struct {
+	mu  *sync.Mutex
+	buf *bytes.Buffer
+}
+
+func (w reportWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.Write(p)
+}
+
+// Writer returns an io.Writer that captures the output of the given job for
+// the report, as well as writing it to the job's current io.Writer. The
+// returned io.Writer should be set as the Writer of the job.
+func (rep *Report) Writer(j *Job) io.Writer {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	buf, ok := rep.output[j]
+
+	if !ok {
+		buf = &bytes.Buffer{}
+		rep.output[j] = buf
+	}
+
+	w := reportWriter{
+		mu:  &rep.mu,
+		buf: buf,
+	}
+
+	if j.Writer == nil {
+		return w
+	}
+	return io.MultiWriter(j.Writer, w)
+}
+
+func (rep *Report) jobOutput(j *Job) string {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	if buf, ok := rep.output[j]; ok {
+		return buf.String()
+	}
+	return ""
+}
+
+// reportSkipped reports whether the given job should be reported as skipped.
+// A job that was never executed because an earlier job failed will have the
+// status of the run, so is reported as skipped rather than failed.
+func reportSkipped(j *Job) bool {
+	switch j.status {
+	case Queued, Skipped:
+		return true
+	case Failed, Killed, TimedOut:
+		return j.started.IsZero() && len(j.Commands) > 0
+	}
+	return false
+}
+
+// reportFailed reports whether the given job should be reported as failed.
+func reportFailed(j *Job) bool {
+	if reportSkipped(j) {
+		return false
+	}
+	return j.status == Failed || j.status == Killed || j.status == TimedOut
+}
+
+// jobErrors returns the errors of the given job. If the job failed, and has no
+// errors, then the status of the job is given as the error.
+func jobErrors(j *Job) []string {
+	errs := make([]string, 0, len(j.errs))
+
+	for _, err := range j.errs {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) == 0 && reportFailed(j) {
+		errs = append(errs, j.FullName()+" "+j.status.String())
+	}
+	return errs
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       string           `xml:"time,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+func seconds(secs float64) string { return fmt.Sprintf("%.3f", secs) }
+
+// WriteJUnit writes a JUnit XML report of the jobs in the given Runner to the
+// given io.Writer. Each stage is a testsuite, and each job a testcase. Jobs
+// that failed, were killed, or timed out are failures, and jobs that were not
+// executed are skipped. The errors of a job that was allowed to fail are
+// written to the testcase's system-err.
+func (rep *Report) WriteJUnit(w io.Writer, r *Runner) error {
+	suites := junitTestSuites{
+		Name: "djinn",
+	}
+
+	var total float64
+
+	for _, st := range r.Stages() {
+		jobs := st.Jobs()
+
+		if len(jobs) == 0 {
+			continue
+		}
+
+		suite := junitTestSuite{
+			Name: st.Name,
+		}
+
+		var time float64
+
+		for _, j := range jobs {
+			secs := j.Duration().Seconds()
+			time += secs
+
+			tc := junitTestCase{
+				Name:      j.Name,
+				Classname: st.Name,
+				Time:      seconds(secs),
+				SystemOut: rep.jobOutput(j),
+			}
+
+			errs := strings.Join(jobErrors(j), "\n")
+
+			switch {
+			case reportSkipped(j):
+				tc.Skipped = &junitSkipped{
+					Message: j.status.String(),
+				}
+				suite.Skipped++
+			case reportFailed(j):
+				tc.Failure = &junitFailure{
+					Message: j.FullName() + " " + j.status.String(),
+					Type:    j.status.String(),
+					Text:    errs,
+				}
+				suite.Failures++
+			case j.status == PassedWithFailures:
+				tc.SystemErr = errs
+			}
+
+			suite.TestCases = append(suite.TestCases, tc)
+			suite.Tests++
+		}
+
+		suite.Time = seconds(time)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.TestSuites = append(suites.TestSuites, suite)
+
+		total += time
+	}
+
+	suites.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type tapDiagnostic struct {
+	Status   string   `yaml:"status"`
+	Duration string   `yaml:"duration"`
+	Errors   []string `yaml:"errors,omitempty"`
+	Output   string   `yaml:"output,omitempty"`
+}
+
+// WriteTAP writes a TAP version 13 report of the jobs in the given Runner to
+// the given io.Writer. Each job is a test point, with the status, duration,
+// errors, and output of the job given in a YAML block. Jobs that were not
+// executed are marked with the SKIP directive.
+func (rep *Report) WriteTAP(w io.Writer, r *Runner) error {
+	jobs := make([]*Job, 0)
+
+	for _, st := range r.Stages() {
+		jobs = append(jobs, st.Jobs()...)
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString("TAP version 13\n")
+	fmt.Fprintf(&buf, "1..%d\n", len(jobs))
+
+	for i, j := range jobs {
+		ok := "ok"
+
+		if reportFailed(j) {
+			ok = "not ok"
+		}
+
+		fmt.Fprintf(&buf, "%s %d - %s", ok, i+1, j.FullName())
+
+		if reportSkipped(j) {
+			buf.WriteString(" # SKIP " + j.status.String())
+		}
+		buf.WriteString("\n")
+
+		b, err := yaml.Marshal(tapDiagnostic{
+			Status:   j.status.String(),
+			Duration: seconds(j.Duration().Seconds()) + "s",
+			Errors:   jobErrors(j),
+			Output:   rep.jobOutput(j),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString("  ---\n")
+
+		for _, line := range strings.SplitAfter(string(b), "\n") {
+			if line != "" {
+				buf.WriteString("  " + line)
+			}
+		}
+		buf.WriteString("  ...\n")
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
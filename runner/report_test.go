@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/andrewpillar/fs"
+)
+
+func reportRun(t *testing.T) (*Report, *Runner) {
+	r := &Runner{
+		Writer:    io.Discard,
+		Objects:   fs.New(""),
+		Artifacts: fs.New(t.TempDir()),
+	}
+
+	rep := NewReport()
+
+	build := Stage{
+		Name: "build",
+	}
+
+	build.Add(
+		&Job{Name: "compile", Commands: []string{"true"}},
+		&Job{Name: "test", Needs: []string{"compile"}, Commands: []string{"true"}},
+	)
+
+	deploy := Stage{
+		Name: "deploy",
+	}
+
+	deploy.Add(&Job{Name: "release", Commands: []string{"true"}})
+
+	for _, st := range []*Stage{&build, &deploy} {
+		for _, j := range st.Jobs() {
+			j.Writer = rep.Writer(j)
+		}
+		r.Add(st)
+	}
+
+	d := artifactDriver{
+		recordDriver: &recordDriver{
+			Writer: io.Discard,
+			fail: map[string]struct{}{
+				"test": {},
+			},
+		},
+	}
+
+	r.Run(context.Background(), d)
+	return rep, r
+}
+
+func Test_ReportJUnit(t *testing.T) {
+	rep, r := reportRun(t)
+
+	var buf bytes.Buffer
+
+	if err := rep.WriteJUnit(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+
+	var suites junitTestSuites
+
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatal(err)
+	}
+
+	if suites.Tests != 3 || suites.Failures != 1 || suites.Skipped != 1 {
+		t.Fatalf("unexpected totals, expected tests=3 failures=1 skipped=1, got tests=%d failures=%d skipped=%d\n", suites.Tests, suites.Failures, suites.Skipped)
+	}
+
+	compile := suites.TestSuites[0].TestCases[0]
+
+	if compile.Failure != nil || !strings.Contains(compile.SystemOut, "building compile") {
+		t.Errorf("unexpected testcase for compile, got=%+v\n", compile)
+	}
+
+	test := suites.TestSuites[0].TestCases[1]
+
+	if test.Failure == nil || !strings.Contains(test.Failure.Text, "build failed") {
+		t.Errorf("expected failure for test, got=%+v\n", test)
+	}
+
+	if release := suites.TestSuites[1].TestCases[0]; release.Skipped == nil {
+		t.Errorf("expected release to be skipped, got=%+v\n", release)
+	}
+}
+
+func Test_ReportTAP(t *testing.T) {
+	rep, r := reportRun(t)
+
+	var buf bytes.Buffer
+
+	if err := rep.WriteTAP(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"TAP version 13",
+		"1..3",
+		"ok 1 - build/compile",
+		"not ok 2 - build/test",
+		"ok 3 - deploy/release # SKIP failed",
+	}
+
+	lines := make([]string, 0, len(expected))
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if !strings.HasPrefix(line, " ") && line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("unexpected report, expected=%d lines, got=%d\n%s\n", len(expected), len(lines), buf.String())
+	}
+
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("lines[%d] - expected=%q, got=%q\n", i, expected[i], line)
+		}
+	}
+
+	if !strings.Contains(buf.String(), "build failed") {
+		t.Errorf("expected errors of failed job in report\n%s\n", buf.String())
+	}
+}
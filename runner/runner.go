@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"bytes"
 	"context"
 	"database/sql/driver"
 	"encoding/json"
@@ -8,8 +9,11 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"djinn-ci.com/errors"
@@ -28,6 +32,8 @@ const (
 	Failed                           // failed
 	Killed                           // killed
 	TimedOut                         // timed_out
+	Skipped                          // skipped
+	Waiting                          // waiting
 )
 
 func (s Status) MarshalJSON() ([]byte, error) { return json.Marshal(s.String()) }
@@ -40,6 +46,8 @@ var statusMap = map[string]Status{
 	"failed":               Failed,
 	"killed":               Killed,
 	"timed_out":            TimedOut,
+	"skipped":              Skipped,
+	"waiting":              Waiting,
 }
 
 func (s *Status) Scan(val any) error {
@@ -122,6 +130,59 @@ func (p *Passthrough) UnmarshalYAML(unmarshal func(any) error) error {
 	return nil
 }
 
+// Retry configures how a job is retried when it fails. If no exit codes, or
+// patterns are given, then any failure of the job is retried.
+type Retry struct {
+	// Max is the maximum number of attempts that will be made at executing
+	// the job.
+	Max int
+
+	// ExitCodes is the exit codes the job must fail with to be retried.
+	ExitCodes []int
+
+	// Patterns is the patterns, one of which must match the output of the
+	// failed job for it to be retried.
+	Patterns []*regexp.Regexp
+}
+
+// retryable reports whether the given failure of a job can be retried. This
+// checks the given error against the configured exit codes, and the given
+// output against the configured patterns.
+func (r Retry) retryable(err error, output []byte) bool {
+	if len(r.ExitCodes) == 0 && len(r.Patterns) == 0 {
+		return true
+	}
+
+	var exitErr *ExitError
+
+	if errors.As(err, &exitErr) {
+		for _, code := range r.ExitCodes {
+			if exitErr.Code == code {
+				return true
+			}
+		}
+	}
+
+	for _, re := range r.Patterns {
+		if re.Match(output) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExitError is the error returned by a Driver when the script for a job exits
+// with a non-zero exit code.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string { return "exit status " + strconv.Itoa(e.Code) }
+
+// Is reports whether the target is ErrFailed, so an ExitError is treated the
+// same as any other job failure.
+func (e *ExitError) Is(target error) bool { return target == ErrFailed }
+
 type Job struct {
 	io.Writer
 
@@ -129,10 +190,50 @@ type Job struct {
 	canFail bool
 	status  Status
 	stage   string
+	attempt int
+
+	started  time.Time
+	finished time.Time
+	results  []CommandResult
 
 	Name      string
+	Needs     []string
+	Env       []string
 	Commands  []string
 	Artifacts Passthrough
+
+	// Inputs are the artifacts of earlier jobs to place into the environment
+	// before the job is executed. Each key is the name of an artifact, and
+	// each value the path to place it at.
+	Inputs Passthrough
+
+	// Workdir is the directory the commands of the job are executed in. If
+	// relative then it is relative to the directory the driver executes jobs
+	// in.
+	Workdir string
+
+	// Shell is the name of the shell the commands of the job are executed
+	// with. If empty then sh is used.
+	Shell string
+
+	// Quiet is whether the commands of the job should not be echoed as they
+	// are executed.
+	Quiet bool
+
+	// Manual denotes that the job has to be approved before it is executed.
+	// Whilst waiting for approval the job will have the Waiting status.
+	Manual bool
+
+	// Cache is the cache to restore before the job is executed, and to save
+	// once the job has passed. If nil then the job is not cached.
+	Cache *Cache
+
+	// Timeout is the maximum amount of time the job can be executed for. If
+	// this is zero then the job can run for as long as the build can. The
+	// timeout applies to each attempt at executing the job.
+	Timeout time.Duration
+
+	Retry Retry
 }
 
 func (j *Job) FullName() string {
@@ -144,6 +245,28 @@ func (j *Job) FullName() string {
 
 func (j *Job) Status() Status { return j.status }
 
+// Results returns the result of each command of the job that was executed in
+// the latest attempt at the job.
+func (j *Job) Results() []CommandResult { return j.results }
+
+// Duration returns how long the job was executed for, across every attempt.
+// This will be zero if the job was never executed.
+func (j *Job) Duration() time.Duration {
+	if j.started.IsZero() || j.finished.IsZero() {
+		return 0
+	}
+	return j.finished.Sub(j.started)
+}
+
+// Attempt returns the number of the current attempt at executing the job,
+// starting from 1.
+func (j *Job) Attempt() int {
+	if j.attempt == 0 {
+		return 1
+	}
+	return j.attempt
+}
+
 func (j *Job) Failed(err error) {
 	if err != nil {
 		if !errors.Is(err, ErrFailed) {
@@ -158,6 +281,23 @@ func (j *Job) Failed(err error) {
 	}
 }
 
+// TimedOut marks the job as timed out. Unlike Failed, the job will always have
+// the TimedOut status, even if the job is allowed to fail.
+func (j *Job) TimedOut() {
+	j.errs = append(j.errs, &Error{Stage: j.stage, Job: j.Name, Err: ErrTimedOut})
+	j.status = TimedOut
+}
+
+// runStatus returns the status of the job as it should count towards the
+// status of the entire run. A job that timed out in a stage that can fail
+// only counts as a failure that was allowed.
+func (j *Job) runStatus() Status {
+	if j.status == TimedOut && j.canFail {
+		return PassedWithFailures
+	}
+	return j.status
+}
+
 type orderedMap[T any] struct {
 	order []string
 	curr  int
@@ -187,7 +327,7 @@ func (m *orderedMap[T]) next() (T, bool) {
 func (m *orderedMap[T]) get(name string) (T, bool) {
 	var zero T
 
-	if m.m == nil {
+	if m == nil || m.m == nil {
 		return zero, false
 	}
 
@@ -237,8 +377,21 @@ type Stage struct {
 
 	Name    string
 	CanFail bool
+
+	// Always denotes that the stage is executed once every other stage has
+	// finished, regardless of whether the run failed, was killed, or timed
+	// out.
+	Always bool
+
+	// AfterFailure denotes that the stage is executed once every other stage
+	// has finished, but only if the run failed, was killed, or timed out.
+	// Otherwise the jobs in the stage are skipped.
+	AfterFailure bool
 }
 
+// post reports whether the stage is executed after every other stage.
+func (s *Stage) post() bool { return s.Always || s.AfterFailure }
+
 func (s *Stage) Add(jobs ...*Job) {
 	if s.jobs == nil {
 		s.jobs = &orderedMap[*Job]{}
@@ -257,6 +410,20 @@ func (s *Stage) Add(jobs ...*Job) {
 
 func (s *Stage) Get(name string) (*Job, bool) { return s.jobs.get(name) }
 
+// Jobs returns the jobs in the stage in the order they were added.
+func (s *Stage) Jobs() []*Job {
+	if s.jobs == nil {
+		return nil
+	}
+
+	jobs := make([]*Job, 0, s.jobs.len())
+
+	for _, name := range s.jobs.order {
+		jobs = append(jobs, s.jobs.m[name])
+	}
+	return jobs
+}
+
 type Error struct {
 	Stage string
 	Job   string
@@ -274,28 +441,88 @@ type Driver interface {
 
 	Create(ctx context.Context, env []string, pt Passthrough, fs fs.FS) error
 
-	Execute(j *Job, fs fs.FS) error
+	// Execute the given job. The given context will be cancelled if the job
+	// times out, or if the run is killed.
+	Execute(ctx context.Context, j *Job, fs fs.FS) error
 
 	Destroy()
 }
 
+// Placer is implemented by drivers that can place files into the environment
+// after the driver has been created. This is used for placing the inputs of a
+// job before it is executed.
+type Placer interface {
+	// Place copies each file in the given Passthrough from the given
+	// filestore into the environment. The first file that cannot be placed
+	// is returned as an error.
+	Place(ctx context.Context, w io.Writer, pt Passthrough, fs fs.FS) error
+}
+
 type JobHandlerFunc func(j *Job)
 
+// RunHandlerFunc is called with the status of the Runner when a run starts,
+// and when it completes.
+type RunHandlerFunc func(status Status)
+
+// ArtifactHandlerFunc is called with the information of each artifact
+// collected from the given job.
+type ArtifactHandlerFunc func(j *Job, info fs.FileInfo)
+
 type Runner struct {
 	io.Writer
 
+	mu     sync.Mutex
 	status Status
 	stages *orderedMap[*Stage]
 	job    *Job
 
+	// abandoned is set once a killed run stops waiting on the job being
+	// executed, after which the results of any job are dropped.
+	abandoned bool
+
 	handleJobStart    JobHandlerFunc
+	handleJobWait     JobHandlerFunc
+	handleJobRetry    JobHandlerFunc
 	handleJobComplete JobHandlerFunc
+	handleRunStart    RunHandlerFunc
+	handleRunComplete RunHandlerFunc
+	handleArtifact    ArtifactHandlerFunc
 
 	Env         []string
 	Passthrough Passthrough
 
+	// Parallelism is the maximum number of jobs in a stage that will be
+	// executed concurrently on the driver. Jobs are only ever executed
+	// concurrently if they do not need one another. If this is less than 1
+	// then jobs are executed one after the other.
+	Parallelism int
+
+	// PostTimeout is the maximum amount of time the always, and after
+	// failure stages can be executed for. If this is zero then
+	// DefaultPostTimeout is used.
+	PostTimeout time.Duration
+
+	// PostContext is the parent of the context the always, and after failure
+	// stages are executed with. Unlike the context given to Run, this should
+	// only be cancelled when the run must be stopped outright, such as on
+	// shutdown. If this is nil then context.Background is used.
+	PostContext context.Context
+
 	Objects   fs.FS
 	Artifacts fs.FS
+
+	// JobArtifacts returns the filestore to collect the artifacts of the
+	// given job into. If this is nil then Artifacts is used for every job.
+	JobArtifacts func(j *Job) fs.FS
+
+	// JobInputs returns the filestore to read the inputs of the given job
+	// from. If this is nil then jobs with inputs fail.
+	JobInputs func(j *Job) fs.FS
+
+	// Approve blocks until the given manual job has been approved. If the job
+	// will never be approved then ErrNotApproved should be returned, and the
+	// job is killed. If this is nil then manual jobs fail.
+	Approve func(ctx context.Context, j *Job) error
 }
 
 func Default(pt Passthrough) *Runner {
@@ -311,6 +538,27 @@ func Default(pt Passthrough) *Runner {
 func (r *Runner) HandleJobStart(fn JobHandlerFunc)    { r.handleJobStart = fn }
 func (r *Runner) HandleJobComplete(fn JobHandlerFunc) { r.handleJobComplete = fn }
 
+// HandleJobWait sets the handler that is called when a manual job starts
+// waiting for approval. When called the job will have the Waiting status.
+func (r *Runner) HandleJobWait(fn JobHandlerFunc) { r.handleJobWait = fn }
+
+// HandleJobRetry sets the handler that is called when an attempt at executing
+// a job fails, and the job is about to be retried. When called the job will
+// have the status of the failed attempt.
+func (r *Runner) HandleJobRetry(fn JobHandlerFunc) { r.handleJobRetry = fn }
+
+// HandleRunStart sets the handler that is called before the driver is
+// created.
+func (r *Runner) HandleRunStart(fn RunHandlerFunc) { r.handleRunStart = fn }
+
+// HandleRunComplete sets the handler that is called with the final status of
+// the Runner once every stage has been run.
+func (r *Runner) HandleRunComplete(fn RunHandlerFunc) { r.handleRunComplete = fn }
+
+// HandleArtifact sets the handler that is called for each artifact that is
+// collected from a job.
+func (r *Runner) HandleArtifact(fn ArtifactHandlerFunc) { r.handleArtifact = fn }
+
 func (r *Runner) Stages() []*Stage {
 	if r.stages == nil {
 		return nil
@@ -356,51 +604,375 @@ func (r *Runner) printLastJobStatus() {
 	fmt.Fprintf(r.Writer, "Done. Run %s\n", r.status)
 }
 
-func (r *Runner) runStage(st *Stage, d Driver) error {
-	if st.jobs.len() == 0 {
+// lookupJob returns the job of the given name from any of the stages in the
+// runner.
+func (r *Runner) lookupJob(name string) (*Job, bool) {
+	for _, st := range r.stages.m {
+		if j, ok := st.jobs.get(name); ok {
+			return j, true
+		}
+	}
+	return nil, false
+}
+
+// ready reports whether the needs of the given job have been satisfied. If a
+// needed job did not pass, then the error returned details this, and the job
+// should not be executed. Needs of jobs that are not in the runner are
+// ignored.
+func (r *Runner) ready(j *Job) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range j.Needs {
+		dep, ok := r.lookupJob(name)
+
+		if !ok {
+			continue
+		}
+
+		switch dep.status {
+		case Queued, Running, Waiting:
+			return false, nil
+		case Passed:
+			continue
+		default:
+			return false, errors.New("needed job " + dep.FullName() + " " + dep.status.String())
+		}
+	}
+	return true, nil
+}
+
+// executeAttempt makes a single attempt at executing the given job. If the
+// attempt timed out then ErrTimedOut is returned. The output of the attempt is
+// returned if the job has any retry patterns to match against.
+func (r *Runner) executeAttempt(ctx context.Context, j *Job, d Driver) ([]byte, error) {
+	attemptCtx := ctx
+
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		attemptCtx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+
+	var buf bytes.Buffer
+
+	w := j.Writer
+
+	if len(j.Retry.Patterns) > 0 {
+		j.Writer = io.MultiWriter(w, &buf)
+	}
+
+	artifacts := r.Artifacts
+
+	if r.JobArtifacts != nil {
+		artifacts = fs.WriteOnly(r.JobArtifacts(j))
+	}
+
+	if r.handleArtifact != nil {
+		artifacts = artifactCollector{
+			FS:     artifacts,
+			job:    j,
+			handle: r.handleArtifact,
+		}
+	}
+
+	err := d.Execute(attemptCtx, j, artifacts)
+
+	j.Writer = w
+
+	if err != nil && ctx.Err() == nil && attemptCtx.Err() == context.DeadlineExceeded {
+		return buf.Bytes(), ErrTimedOut
+	}
+	return buf.Bytes(), err
+}
+
+// artifactCollector calls the artifact handler for each file that is put into
+// the underlying filestore.
+type artifactCollector struct {
+	fs.FS
+
+	job    *Job
+	handle ArtifactHandlerFunc
+}
+
+func (c artifactCollector) Put(f fs.File) (fs.File, error) {
+	f, err := c.FS.Put(f)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := f.Stat(); err == nil {
+		c.handle(c.job, info)
+	}
+	return f, nil
+}
+
+// placeInputs places the inputs of the given job into the environment of the
+// driver.
+func (r *Runner) placeInputs(ctx context.Context, j *Job, d Driver) error {
+	if len(j.Inputs) == 0 {
 		return nil
 	}
 
+	if r.JobInputs == nil {
+		return errors.New("cannot place inputs, no filestore configured")
+	}
+
+	p, ok := d.(Placer)
+
+	if !ok {
+		return errors.New("cannot place inputs, driver does not support placement")
+	}
+
+	if err := p.Place(ctx, j.Writer, j.Inputs, fs.ReadOnly(r.JobInputs(j))); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(j.Writer)
+	return nil
+}
+
+// execute executes the given job on the driver. If an attempt at executing the
+// job fails, and the failure matches the job's retry conditions, then the job
+// is executed again, up to the maximum number of attempts. The error from the
+// final attempt is returned.
+func (r *Runner) execute(ctx context.Context, j *Job, d Driver) error {
+	cw := newCommandWriter(j)
+
+	defer func() {
+		j.Writer = cw.Writer
+	}()
+
+	if err := r.placeInputs(ctx, j, d); err != nil {
+		return err
+	}
+
 	for {
-		j, ok := st.jobs.next()
+		output, err := r.executeAttempt(ctx, j, d)
 
-		if !ok {
-			break
+		cw.finish(err)
+
+		if err == nil || ctx.Err() != nil || j.Attempt() >= j.Retry.Max || !j.Retry.retryable(err, output) {
+			return err
 		}
 
-		r.job = j
+		r.mu.Lock()
 
-		j.status = Running
+		if errors.Is(err, ErrTimedOut) {
+			j.TimedOut()
+		} else {
+			j.Failed(err)
+		}
 
-		if len(j.Commands) > 0 {
-			if r.handleJobStart != nil {
-				r.handleJobStart(j)
-			}
+		status := j.status
+		attempt := j.Attempt()
 
-			if err := d.Execute(j, r.Artifacts); err != nil {
-				j.Failed(err)
-			}
+		r.mu.Unlock()
+
+		fmt.Fprintf(r.Writer, "Job %s %s on attempt %d, retrying...\n", j.FullName(), status, attempt)
+
+		// The handler may replace the Writer of the job for the next
+		// attempt, so it is given the Writer the job originally had.
+		j.Writer = cw.Writer
+
+		if r.handleJobRetry != nil {
+			r.handleJobRetry(j)
 		}
 
-		if j.status != Failed && j.status != PassedWithFailures {
-			j.status = Passed
+		r.mu.Lock()
+		j.errs = nil
+		j.attempt = attempt + 1
+		j.status = Running
+		r.mu.Unlock()
+
+		cw = newCommandWriter(j)
+	}
+}
+
+// wait waits for the given manual job to be approved. The driver is kept
+// whilst waiting, so the job is executed in the same environment as the jobs
+// before it once approved.
+func (r *Runner) wait(ctx context.Context, j *Job) error {
+	if r.Approve == nil {
+		return errors.New("cannot wait for approval, no approver configured")
+	}
+
+	r.mu.Lock()
+	j.status = Waiting
+	r.mu.Unlock()
+
+	fmt.Fprintf(r.Writer, "Job %s waiting for approval...\n", j.FullName())
+
+	if r.handleJobWait != nil {
+		r.handleJobWait(j)
+	}
+
+	err := r.Approve(ctx, j)
+
+	r.mu.Lock()
+	j.status = Running
+	r.mu.Unlock()
+
+	return err
+}
+
+func (r *Runner) runJob(ctx context.Context, j *Job, d Driver, skip error) {
+	r.mu.Lock()
+
+	if r.abandoned {
+		r.mu.Unlock()
+		return
+	}
+
+	r.job = j
+	j.status = Running
+	r.mu.Unlock()
+
+	var err error
+
+	if skip == nil && len(j.Commands) > 0 && j.Manual {
+		skip = r.wait(ctx, j)
+	}
+
+	if skip == nil && len(j.Commands) > 0 {
+		if r.handleJobStart != nil {
+			r.handleJobStart(j)
 		}
 
-		if r.handleJobComplete != nil {
-			r.handleJobComplete(j)
+		r.mu.Lock()
+		j.started = time.Now()
+		r.mu.Unlock()
+
+		err = r.execute(ctx, j, d)
+	}
+
+	r.mu.Lock()
+
+	// The run stopped waiting on the job, and has already given it a final
+	// status.
+	if r.abandoned {
+		r.mu.Unlock()
+		return
+	}
+
+	if !j.started.IsZero() {
+		j.finished = time.Now()
+	}
+
+	if skip != nil {
+		err = skip
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTimedOut), ctx.Err() == context.DeadlineExceeded:
+			j.TimedOut()
+		case ctx.Err() == context.Canceled:
+			j.status = Killed
+		case errors.Is(err, ErrNotApproved):
+			j.errs = append(j.errs, &Error{Stage: j.stage, Job: j.Name, Err: err})
+			j.status = Killed
+		default:
+			j.Failed(err)
 		}
+	}
+
+	if j.status == Running {
+		j.status = Passed
+	}
+
+	r.mu.Unlock()
+
+	if r.handleJobComplete != nil {
+		r.handleJobComplete(j)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if status := j.runStatus(); status >= r.status {
+		r.status = status
+	}
 
-		if j.status >= r.status {
-			r.status = j.status
+	if r.status == Failed {
+		fmt.Fprintf(r.Writer, "\n")
+
+		for _, err := range j.errs {
+			fmt.Fprintf(r.Writer, "error: %s\n", err)
 		}
+	}
+}
+
+// runStage executes the jobs in the given stage. Jobs are started in the
+// order they were added to the stage once all of the jobs they need have
+// passed, with at most Parallelism jobs executing at once.
+func (r *Runner) runStage(ctx context.Context, st *Stage, d Driver) error {
+	if st.jobs.len() == 0 {
+		return nil
+	}
+
+	limit := r.Parallelism
+
+	if limit < 1 {
+		limit = 1
+	}
+
+	pending := make([]*Job, 0, st.jobs.len())
+
+	for _, name := range st.jobs.order {
+		pending = append(pending, st.jobs.m[name])
+	}
+
+	done := make(chan struct{})
+	running := 0
 
-		if r.status == Failed {
-			fmt.Fprintf(r.Writer, "\n")
+	for len(pending) > 0 {
+		for i := 0; i < len(pending) && running < limit; {
+			j := pending[i]
+
+			ok, err := r.ready(j)
+
+			if !ok && err == nil {
+				i++
+				continue
+			}
+
+			pending = append(pending[:i], pending[i+1:]...)
+
+			if err != nil {
+				r.runJob(ctx, j, d, err)
+				continue
+			}
+
+			running++
+
+			go func(j *Job) {
+				r.runJob(ctx, j, d, nil)
+				done <- struct{}{}
+			}(j)
+		}
 
-			for _, err := range j.errs {
-				fmt.Fprintf(r.Writer, "error: %s\n", err)
+		// Nothing is running, and nothing can be started, so whatever is left
+		// can never be ready.
+		if running == 0 {
+			for _, j := range pending {
+				r.runJob(ctx, j, d, errors.New("needs could not be satisfied"))
 			}
+			break
 		}
+
+		<-done
+		running--
+	}
+
+	for ; running > 0; running-- {
+		<-done
+	}
+
+	if status := r.Status(); status == Failed || status == Killed || status == TimedOut {
+		return ErrFailed
 	}
 	return nil
 }
@@ -408,23 +980,93 @@ func (r *Runner) runStage(st *Stage, d Driver) error {
 var (
 	ErrFailed   = errors.New("runner: failed")
 	ErrTimedOut = errors.New("runner: timed out")
+
+	// ErrNotApproved is returned by the Approve function of a Runner when a
+	// manual job will never be approved, for example if the approval
+	// expired.
+	ErrNotApproved = errors.New("runner: not approved")
 )
 
+// DefaultPostTimeout is the default amount of time the always, and after
+// failure stages can be executed for.
+const DefaultPostTimeout = time.Minute * 10
+
+// killGracePeriod is how long to wait for the job being executed to be killed
+// before the always, and after failure stages are executed. If the job has not
+// stopped by then, the run is abandoned.
+var killGracePeriod = time.Second * 10
+
+// finishStage sets the status of the jobs in the given stage that were never
+// executed to the given status. If the run was abandoned, then the jobs still
+// being executed are given the status too.
+func (r *Runner) finishStage(st *Stage, status Status) {
+	if st.jobs == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, j := range st.jobs.m {
+		if j.status == Queued || (r.abandoned && j.status == Running) {
+			j.status = status
+
+			if r.handleJobComplete != nil {
+				r.handleJobComplete(j)
+			}
+		}
+	}
+}
+
 func (r *Runner) updateUnfinishedJobs() {
+	status := r.Status()
+
 	for _, st := range r.stages.m {
-		if st.jobs == nil {
-			continue
+		if !st.post() {
+			r.finishStage(st, status)
 		}
+	}
+}
 
-		for _, j := range st.jobs.m {
-			if j.status == Queued {
-				j.status = r.status
+// runPostStages executes the always, and after failure stages once every
+// other stage has finished. These are executed with a context derived from
+// PostContext, so they are still executed if the run was killed, or timed out.
+// The status of a run that did not pass is left as is.
+func (r *Runner) runPostStages(d Driver) {
+	status := r.Status()
+	failed := status == Failed || status == Killed || status == TimedOut
 
-				if r.handleJobComplete != nil {
-					r.handleJobComplete(j)
-				}
-			}
+	timeout := r.PostTimeout
+
+	if timeout <= 0 {
+		timeout = DefaultPostTimeout
+	}
+
+	parent := r.PostContext
+
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	for _, st := range r.Stages() {
+		if !st.post() {
+			continue
+		}
+
+		if st.AfterFailure && !failed {
+			r.finishStage(st, Skipped)
+			continue
 		}
+		r.runStage(ctx, st, d)
+	}
+
+	if failed {
+		r.mu.Lock()
+		r.status = status
+		r.mu.Unlock()
 	}
 }
 
@@ -449,14 +1091,21 @@ func (r *Runner) Run(ctx context.Context, d Driver) error {
 
 	defer d.Destroy()
 
-	ctx, cancel := context.WithTimeout(ctx, time.Minute*5)
-	defer cancel()
-
 	statustab := map[error]Status{
 		context.Canceled:         Killed,
 		context.DeadlineExceeded: TimedOut,
 	}
 
+	if r.handleRunStart != nil {
+		r.handleRunStart(Running)
+	}
+
+	if r.handleRunComplete != nil {
+		defer func() {
+			r.handleRunComplete(r.Status())
+		}()
+	}
+
 	if r.handleJobStart != nil {
 		r.handleJobStart(r.findCreateDriverJob())
 	}
@@ -475,6 +1124,13 @@ func (r *Runner) Run(ctx context.Context, d Driver) error {
 		r.status = status
 		r.updateUnfinishedJobs()
 
+		// Without a driver there is nothing to execute the always, and after
+		// failure stages on.
+		for _, st := range r.Stages() {
+			if st.post() {
+				r.finishStage(st, status)
+			}
+		}
 		return ErrFailed
 	}
 
@@ -482,40 +1138,75 @@ func (r *Runner) Run(ctx context.Context, d Driver) error {
 		r.handleJobComplete(r.findCreateDriverJob())
 	}
 
-	done := make(chan struct{})
+	done := make(chan struct{}, 1)
 
 	go func() {
-		for {
-			st, ok := r.stages.next()
-
-			if !ok {
-				break
+		for _, st := range r.Stages() {
+			if st.post() {
+				continue
 			}
 
-			if err := r.runStage(st, d); err != nil {
+			if err := r.runStage(ctx, st, d); err != nil {
 				break
 			}
 		}
 		done <- struct{}{}
 	}()
 
+	var err error
+
 	select {
 	case <-ctx.Done():
-		err := ctx.Err()
+		err = ctx.Err()
 
+		r.mu.Lock()
 		r.status = statustab[err]
-		r.updateUnfinishedJobs()
-
-		return err
-	case <-done:
-		if r.status == Failed {
-			return ErrFailed
+		r.mu.Unlock()
+
+		// Give the job being executed a chance to be killed before anything
+		// else is executed on the driver. If it is not killed in time, then
+		// the run is abandoned, since nothing else can be executed on the
+		// driver whilst the job is.
+		select {
+		case <-done:
+		case <-time.After(killGracePeriod):
+			r.mu.Lock()
+			r.abandoned = true
+			r.mu.Unlock()
+
+			fmt.Fprintln(d, "job did not stop after being killed, skipping the always, and after failure stages")
 		}
-		if r.status == TimedOut {
-			return ErrTimedOut
+	case <-done:
+	}
+
+	r.updateUnfinishedJobs()
+
+	if r.abandoned {
+		for _, st := range r.Stages() {
+			if st.post() {
+				r.finishStage(st, r.Status())
+			}
 		}
-		return nil
+	} else {
+		r.runPostStages(d)
+	}
+
+	if err != nil {
+		return err
 	}
+
+	switch r.Status() {
+	case Failed, Killed:
+		return ErrFailed
+	case TimedOut:
+		return ErrTimedOut
+	}
+	return nil
 }
 
-func (r *Runner) Status() Status { return r.status }
+func (r *Runner) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.status
+}
@@ -3,9 +3,15 @@ package runner
 import (
 	"context"
 	"crypto/rand"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/andrewpillar/fs"
 
@@ -120,3 +126,597 @@ func Test_Runner(t *testing.T) {
 		t.Fatalf("unexpected status, expected=%s, got=%s\n", Passed, status)
 	}
 }
+
+type recordDriver struct {
+	io.Writer
+
+	mu    sync.Mutex
+	order []string
+	fail  map[string]struct{}
+	flaky map[string]int
+	sleep map[string]time.Duration
+}
+
+func (d *recordDriver) Create(context.Context, []string, Passthrough, fs.FS) error { return nil }
+
+func (d *recordDriver) Execute(ctx context.Context, j *Job, _ fs.FS) error {
+	if dur, ok := d.sleep[j.Name]; ok {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dur):
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.order = append(d.order, j.Name)
+
+	if _, ok := d.fail[j.Name]; ok {
+		return ErrFailed
+	}
+
+	if n, ok := d.flaky[j.Name]; ok && n > 0 {
+		d.flaky[j.Name]--
+
+		io.WriteString(j.Writer, "connection reset by peer\n")
+		return &ExitError{Code: 75}
+	}
+	return nil
+}
+
+func (d *recordDriver) Destroy() {}
+
+func Test_RunnerNeeds(t *testing.T) {
+	tests := []struct {
+		parallelism int
+		fail        string
+		expected    map[string]Status
+	}{
+		{
+			1,
+			"",
+			map[string]Status{"a": Passed, "b": Passed, "c": Passed, "d": Passed},
+		},
+		{
+			4,
+			"",
+			map[string]Status{"a": Passed, "b": Passed, "c": Passed, "d": Passed},
+		},
+		{
+			2,
+			"b",
+			map[string]Status{"a": Passed, "b": Failed, "c": Passed, "d": Failed},
+		},
+	}
+
+	for i, test := range tests {
+		d := &recordDriver{
+			Writer: io.Discard,
+			fail:   map[string]struct{}{test.fail: {}},
+		}
+
+		r := Runner{
+			Writer:      io.Discard,
+			Parallelism: test.parallelism,
+			Objects:     fs.New(""),
+			Artifacts:   fs.New(""),
+		}
+
+		stage := Stage{
+			Name: "test",
+		}
+
+		stage.Add(
+			&Job{Writer: io.Discard, Name: "d", Needs: []string{"b", "c"}, Commands: []string{"true"}},
+			&Job{Writer: io.Discard, Name: "b", Needs: []string{"a"}, Commands: []string{"true"}},
+			&Job{Writer: io.Discard, Name: "c", Needs: []string{"a"}, Commands: []string{"true"}},
+			&Job{Writer: io.Discard, Name: "a", Commands: []string{"true"}},
+		)
+
+		r.Add(&stage)
+		r.Run(context.Background(), d)
+
+		pos := make(map[string]int)
+
+		for j, name := range d.order {
+			pos[name] = j
+		}
+
+		for name, status := range test.expected {
+			j, _ := stage.Get(name)
+
+			if j.Status() != status {
+				t.Errorf("tests[%d] - unexpected status for %q, expected=%s, got=%s\n", i, name, status, j.Status())
+			}
+
+			for _, need := range j.Needs {
+				if _, ok := pos[name]; ok && pos[need] > pos[name] {
+					t.Errorf("tests[%d] - job %q executed before needed job %q\n", i, name, need)
+				}
+			}
+		}
+
+		if _, ok := pos["d"]; ok && test.fail != "" {
+			t.Errorf("tests[%d] - job %q executed despite failed need\n", i, "d")
+		}
+	}
+}
+
+func Test_RunnerJobTimeout(t *testing.T) {
+	tests := []struct {
+		canFail     bool
+		expected    Status
+		expectedJob Status
+	}{
+		{false, TimedOut, TimedOut},
+		{true, PassedWithFailures, TimedOut},
+	}
+
+	for i, test := range tests {
+		d := &recordDriver{
+			Writer: io.Discard,
+			sleep:  map[string]time.Duration{"slow": time.Second},
+		}
+
+		r := Runner{
+			Writer:    io.Discard,
+			Objects:   fs.New(""),
+			Artifacts: fs.New(""),
+		}
+
+		stage := Stage{
+			Name:    "test",
+			CanFail: test.canFail,
+		}
+
+		stage.Add(
+			&Job{Writer: io.Discard, Name: "slow", Commands: []string{"true"}, Timeout: time.Millisecond * 10},
+			&Job{Writer: io.Discard, Name: "fast", Commands: []string{"true"}},
+		)
+
+		r.Add(&stage)
+		r.Run(context.Background(), d)
+
+		if status := r.Status(); status != test.expected {
+			t.Errorf("tests[%d] - unexpected run status, expected=%s, got=%s\n", i, test.expected, status)
+		}
+
+		slow, _ := stage.Get("slow")
+
+		if status := slow.Status(); status != test.expectedJob {
+			t.Errorf("tests[%d] - unexpected job status, expected=%s, got=%s\n", i, test.expectedJob, status)
+		}
+
+		fast, _ := stage.Get("fast")
+
+		if status := fast.Status(); status != Passed {
+			t.Errorf("tests[%d] - unexpected job status, expected=%s, got=%s\n", i, Passed, status)
+		}
+	}
+}
+
+func Test_RunnerRetry(t *testing.T) {
+	tests := []struct {
+		flaky           int
+		retry           Retry
+		expected        Status
+		expectedAttempt int
+	}{
+		{2, Retry{Max: 3}, Passed, 3},
+		{3, Retry{Max: 3}, Failed, 3},
+		{1, Retry{Max: 2, ExitCodes: []int{75}}, Passed, 2},
+		{1, Retry{Max: 2, ExitCodes: []int{1}}, Failed, 1},
+		{1, Retry{Max: 2, Patterns: []*regexp.Regexp{regexp.MustCompile("connection reset")}}, Passed, 2},
+		{1, Retry{Max: 2, Patterns: []*regexp.Regexp{regexp.MustCompile("no space left")}}, Failed, 1},
+	}
+
+	for i, test := range tests {
+		d := &recordDriver{
+			Writer: io.Discard,
+			flaky:  map[string]int{"flaky": test.flaky},
+		}
+
+		r := Runner{
+			Writer:    io.Discard,
+			Objects:   fs.New(""),
+			Artifacts: fs.New(""),
+		}
+
+		retries := 0
+
+		r.HandleJobRetry(func(j *Job) {
+			retries++
+
+			if j.Status() != Failed {
+				t.Errorf("tests[%d] - unexpected status on retry, expected=%s, got=%s\n", i, Failed, j.Status())
+			}
+		})
+
+		stage := Stage{
+			Name: "test",
+		}
+
+		stage.Add(&Job{Writer: io.Discard, Name: "flaky", Commands: []string{"true"}, Retry: test.retry})
+
+		r.Add(&stage)
+		r.Run(context.Background(), d)
+
+		j, _ := stage.Get("flaky")
+
+		if status := j.Status(); status != test.expected {
+			t.Errorf("tests[%d] - unexpected job status, expected=%s, got=%s\n", i, test.expected, status)
+		}
+
+		if attempt := j.Attempt(); attempt != test.expectedAttempt {
+			t.Errorf("tests[%d] - unexpected attempt, expected=%d, got=%d\n", i, test.expectedAttempt, attempt)
+		}
+
+		if retries != test.expectedAttempt-1 {
+			t.Errorf("tests[%d] - unexpected retries, expected=%d, got=%d\n", i, test.expectedAttempt-1, retries)
+		}
+	}
+}
+
+func Test_RunnerPostStages(t *testing.T) {
+	tests := []struct {
+		fail     string
+		kill     bool
+		expected Status
+		statuses map[string]Status
+	}{
+		{
+			"",
+			false,
+			Passed,
+			map[string]Status{"build": Passed, "deploy": Passed, "cleanup": Passed, "report": Skipped},
+		},
+		{
+			"build",
+			false,
+			Failed,
+			map[string]Status{"build": Failed, "deploy": Failed, "cleanup": Passed, "report": Passed},
+		},
+		{
+			"",
+			true,
+			Killed,
+			map[string]Status{"build": Killed, "deploy": Killed, "cleanup": Passed, "report": Passed},
+		},
+	}
+
+	for i, test := range tests {
+		d := &recordDriver{
+			Writer: io.Discard,
+			fail:   map[string]struct{}{test.fail: {}},
+			sleep:  make(map[string]time.Duration),
+		}
+
+		if test.kill {
+			d.sleep["build"] = time.Second
+		}
+
+		r := Runner{
+			Writer:    io.Discard,
+			Objects:   fs.New(""),
+			Artifacts: fs.New(""),
+		}
+
+		stages := []*Stage{
+			{Name: "build"},
+			{Name: "deploy"},
+			{Name: "always", Always: true},
+			{Name: "after_failure", AfterFailure: true},
+		}
+
+		stages[0].Add(&Job{Writer: io.Discard, Name: "build", Commands: []string{"true"}})
+		stages[1].Add(&Job{Writer: io.Discard, Name: "deploy", Commands: []string{"true"}})
+		stages[2].Add(&Job{Writer: io.Discard, Name: "cleanup", Commands: []string{"true"}})
+		stages[3].Add(&Job{Writer: io.Discard, Name: "report", Commands: []string{"true"}})
+
+		r.Add(stages...)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		if test.kill {
+			time.AfterFunc(time.Millisecond*10, cancel)
+		}
+
+		r.Run(ctx, d)
+		cancel()
+
+		if status := r.Status(); status != test.expected {
+			t.Errorf("tests[%d] - unexpected run status, expected=%s, got=%s\n", i, test.expected, status)
+		}
+
+		for _, st := range stages {
+			for name, status := range test.statuses {
+				j, ok := st.Get(name)
+
+				if !ok {
+					continue
+				}
+
+				if j.Status() != status {
+					t.Errorf("tests[%d] - unexpected status for %q, expected=%s, got=%s\n", i, name, status, j.Status())
+				}
+			}
+		}
+	}
+}
+
+func Test_RunnerPostContext(t *testing.T) {
+	d := &recordDriver{
+		Writer: io.Discard,
+		fail:   make(map[string]struct{}),
+		sleep:  map[string]time.Duration{"cleanup": time.Minute},
+	}
+
+	parent, cancel := context.WithCancel(context.Background())
+
+	r := Runner{
+		Writer:      io.Discard,
+		Objects:     fs.New(""),
+		Artifacts:   fs.New(""),
+		PostContext: parent,
+	}
+
+	stages := []*Stage{
+		{Name: "build"},
+		{Name: "always", Always: true},
+	}
+
+	stages[0].Add(&Job{Writer: io.Discard, Name: "build", Commands: []string{"true"}})
+	stages[1].Add(&Job{Writer: io.Discard, Name: "cleanup", Commands: []string{"true"}})
+
+	r.Add(stages...)
+
+	time.AfterFunc(time.Millisecond*10, cancel)
+
+	done := make(chan struct{})
+
+	go func() {
+		r.Run(context.Background(), d)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected always stage to be stopped when the post context is cancelled")
+	}
+
+	j, _ := stages[1].Get("cleanup")
+
+	if j.Status() == Passed {
+		t.Errorf("unexpected status for %q, got=%s\n", "cleanup", j.Status())
+	}
+}
+
+// stuckDriver is a driver that ignores cancellation when executing a job.
+type stuckDriver struct {
+	*recordDriver
+
+	running  int32
+	parallel int32
+}
+
+func (d *stuckDriver) Execute(_ context.Context, j *Job, _ fs.FS) error {
+	if n := atomic.AddInt32(&d.running, 1); n > 1 {
+		atomic.StoreInt32(&d.parallel, n)
+	}
+	defer atomic.AddInt32(&d.running, -1)
+
+	if dur, ok := d.sleep[j.Name]; ok {
+		time.Sleep(dur)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.order = append(d.order, j.Name)
+	return nil
+}
+
+func Test_RunnerAbandon(t *testing.T) {
+	grace := killGracePeriod
+	killGracePeriod = time.Millisecond * 50
+
+	defer func() {
+		killGracePeriod = grace
+	}()
+
+	d := &stuckDriver{
+		recordDriver: &recordDriver{
+			Writer: io.Discard,
+			fail:   make(map[string]struct{}),
+			sleep:  map[string]time.Duration{"build": time.Millisecond * 300},
+		},
+	}
+
+	r := Runner{
+		Writer:    io.Discard,
+		Objects:   fs.New(""),
+		Artifacts: fs.New(""),
+	}
+
+	stages := []*Stage{
+		{Name: "build"},
+		{Name: "deploy"},
+		{Name: "always", Always: true},
+	}
+
+	stages[0].Add(&Job{Writer: io.Discard, Name: "build", Commands: []string{"true"}})
+	stages[1].Add(&Job{Writer: io.Discard, Name: "deploy", Commands: []string{"true"}})
+	stages[2].Add(&Job{Writer: io.Discard, Name: "cleanup", Commands: []string{"true"}})
+
+	r.Add(stages...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*10, cancel)
+
+	if err := r.Run(ctx, d); err == nil {
+		t.Fatalf("expected run to fail\n")
+	}
+
+	// Let the stuck job finish, its results should be dropped.
+	time.Sleep(time.Millisecond * 400)
+
+	if n := atomic.LoadInt32(&d.parallel); n > 0 {
+		t.Errorf("unexpected concurrent executions on the driver, got=%d\n", n)
+	}
+
+	if status := r.Status(); status != Killed {
+		t.Errorf("unexpected run status, expected=%s, got=%s\n", Killed, status)
+	}
+
+	expected := map[string]Status{"build": Killed, "deploy": Killed, "cleanup": Killed}
+
+	for _, st := range stages {
+		for name, status := range expected {
+			j, ok := st.Get(name)
+
+			if !ok {
+				continue
+			}
+
+			if j.Status() != status {
+				t.Errorf("unexpected status for %q, expected=%s, got=%s\n", name, status, j.Status())
+			}
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.order) != 1 || d.order[0] != "build" {
+		t.Errorf("unexpected jobs executed, expected=%v, got=%v\n", []string{"build"}, d.order)
+	}
+}
+
+type placeDriver struct {
+	*recordDriver
+
+	placed Passthrough
+}
+
+func (d *placeDriver) Place(_ context.Context, _ io.Writer, pt Passthrough, inputs fs.FS) error {
+	for src, dst := range pt {
+		f, err := inputs.Open(src)
+
+		if err != nil {
+			return err
+		}
+
+		f.Close()
+		d.placed[src] = dst
+	}
+	return nil
+}
+
+func Test_RunnerInputs(t *testing.T) {
+	dir := t.TempDir()
+
+	genfile(t, filepath.Join(dir, "djinn")).Close()
+
+	tests := []struct {
+		driver Driver
+		inputs Passthrough
+		status Status
+	}{
+		{&placeDriver{recordDriver: &recordDriver{Writer: io.Discard}, placed: make(Passthrough)}, Passthrough{"djinn": "bin/djinn"}, Passed},
+		{&placeDriver{recordDriver: &recordDriver{Writer: io.Discard}, placed: make(Passthrough)}, Passthrough{"missing": "bin/missing"}, Failed},
+		{&recordDriver{Writer: io.Discard}, Passthrough{"djinn": "bin/djinn"}, Failed},
+	}
+
+	for i, test := range tests {
+		r := Runner{
+			Writer:    io.Discard,
+			Objects:   fs.New(""),
+			Artifacts: fs.New(""),
+			JobInputs: func(*Job) fs.FS { return fs.New(dir) },
+		}
+
+		stage := Stage{
+			Name: "package",
+		}
+
+		stage.Add(&Job{Writer: io.Discard, Name: "package", Inputs: test.inputs, Commands: []string{"true"}})
+
+		r.Add(&stage)
+		r.Run(context.Background(), test.driver)
+
+		j, _ := stage.Get("package")
+
+		if j.Status() != test.status {
+			t.Errorf("tests[%d] - unexpected status, expected=%s, got=%s\n", i, test.status, j.Status())
+			continue
+		}
+
+		if d, ok := test.driver.(*placeDriver); ok && test.status == Passed {
+			for src, dst := range test.inputs {
+				if d.placed[src] != dst {
+					t.Errorf("tests[%d] - expected input %q to be placed at %q, got=%q\n", i, src, dst, d.placed[src])
+				}
+			}
+		}
+	}
+}
+
+func Test_RunnerManual(t *testing.T) {
+	tests := []struct {
+		approve  error
+		expected Status
+		order    []string
+	}{
+		{nil, Passed, []string{"build", "deploy"}},
+		{ErrNotApproved, Killed, []string{"build"}},
+	}
+
+	for i, test := range tests {
+		d := &recordDriver{
+			Writer: io.Discard,
+		}
+
+		waiting := make([]Status, 0)
+
+		r := Runner{
+			Writer:    io.Discard,
+			Objects:   fs.New(""),
+			Artifacts: fs.New(""),
+			Approve: func(_ context.Context, j *Job) error {
+				return test.approve
+			},
+		}
+
+		r.HandleJobWait(func(j *Job) {
+			waiting = append(waiting, j.Status())
+		})
+
+		build := Stage{
+			Name: "build",
+		}
+		build.Add(&Job{Writer: io.Discard, Name: "build", Commands: []string{"true"}})
+
+		deploy := Stage{
+			Name: "deploy",
+		}
+		deploy.Add(&Job{Writer: io.Discard, Name: "deploy", Commands: []string{"true"}, Manual: true})
+
+		r.Add(&build, &deploy)
+		r.Run(context.Background(), d)
+
+		if status := r.Status(); status != test.expected {
+			t.Errorf("tests[%d] - unexpected run status, expected=%s, got=%s\n", i, test.expected, status)
+		}
+
+		if len(waiting) != 1 || waiting[0] != Waiting {
+			t.Errorf("tests[%d] - expected job to be waiting once, got=%v\n", i, waiting)
+		}
+
+		if !reflect.DeepEqual(d.order, test.order) {
+			t.Errorf("tests[%d] - unexpected jobs executed, expected=%v, got=%v\n", i, test.order, d.order)
+		}
+	}
+}
@@ -15,11 +15,13 @@ func _() {
 	_ = x[Failed-4]
 	_ = x[Killed-5]
 	_ = x[TimedOut-6]
+	_ = x[Skipped-7]
+	_ = x[Waiting-8]
 }
 
-const _Status_name = "queuedrunningpassedpassed_with_failuresfailedkilledtimed_out"
+const _Status_name = "queuedrunningpassedpassed_with_failuresfailedkilledtimed_outskippedwaiting"
 
-var _Status_index = [...]uint8{0, 6, 13, 19, 39, 45, 51, 60}
+var _Status_index = [...]uint8{0, 6, 13, 19, 39, 45, 51, 60, 67, 74}
 
 func (i Status) String() string {
 	if i >= Status(len(_Status_index)-1) {
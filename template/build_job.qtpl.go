@@ -1,28 +1,30 @@
 // Code generated by qtc from "build_job.qtpl". DO NOT EDIT.
 // See https://github.com/valyala/quicktemplate for details.
 
-//line template/build_job.qtpl:2
+//line build_job.qtpl:2
 package template
 
-//line template/build_job.qtpl:2
+//line build_job.qtpl:2
 import (
 	"djinn-ci.com/build"
+	"djinn-ci.com/runner"
+	"djinn-ci.com/template/form"
 )
 
-//line template/build_job.qtpl:7
+//line build_job.qtpl:9
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line template/build_job.qtpl:7
+//line build_job.qtpl:9
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line template/build_job.qtpl:8
+//line build_job.qtpl:10
 type BuildJob struct {
 	*Page
 
@@ -31,412 +33,556 @@ type BuildJob struct {
 	Job       *build.Job
 }
 
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 func (p *BuildJob) StreamTitle(qw422016 *qt422016.Writer) {
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 	qw422016.E().S(p.Job.Name)
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 }
 
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 func (p *BuildJob) WriteTitle(qq422016 qtio422016.Writer) {
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 	p.StreamTitle(qw422016)
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 }
 
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 func (p *BuildJob) Title() string {
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 	p.WriteTitle(qb422016)
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 	qs422016 := string(qb422016.B)
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 	return qs422016
-//line template/build_job.qtpl:18
+//line build_job.qtpl:20
 }
 
-//line template/build_job.qtpl:20
+//line build_job.qtpl:22
 func (p *BuildJob) StreamHeader(qw422016 *qt422016.Writer) {
-//line template/build_job.qtpl:20
+//line build_job.qtpl:22
 	qw422016.N().S(` <a class="back" href="`)
-//line template/build_job.qtpl:21
+//line build_job.qtpl:23
 	qw422016.E().S(p.Job.Build.Endpoint())
-//line template/build_job.qtpl:21
+//line build_job.qtpl:23
 	qw422016.N().S(`">`)
-//line template/build_job.qtpl:21
+//line build_job.qtpl:23
 	qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M20.016 11.016v1.969h-12.188l5.578 5.625-1.406 1.406-8.016-8.016 8.016-8.016 1.406 1.406-5.578 5.625h12.188z"></path>
 </svg>
 `)
-//line template/build_job.qtpl:21
+//line build_job.qtpl:23
 	qw422016.N().S(`</a> `)
-//line template/build_job.qtpl:22
+//line build_job.qtpl:24
 	if p.Job.Build.Namespace != nil {
-//line template/build_job.qtpl:22
+//line build_job.qtpl:24
 		qw422016.N().S(` <a href="`)
-//line template/build_job.qtpl:23
+//line build_job.qtpl:25
 		qw422016.E().S(p.Job.Build.Namespace.Endpoint())
-//line template/build_job.qtpl:23
+//line build_job.qtpl:25
 		qw422016.N().S(`">`)
-//line template/build_job.qtpl:23
+//line build_job.qtpl:25
 		qw422016.E().V(p.Job.Build.Namespace.Name)
-//line template/build_job.qtpl:23
+//line build_job.qtpl:25
 		qw422016.N().S(`</a> / `)
-//line template/build_job.qtpl:24
+//line build_job.qtpl:26
 	}
-//line template/build_job.qtpl:24
+//line build_job.qtpl:26
 	qw422016.N().S(` Build #`)
-//line template/build_job.qtpl:25
+//line build_job.qtpl:27
 	qw422016.E().V(p.Job.Build.Number)
-//line template/build_job.qtpl:25
+//line build_job.qtpl:27
 	qw422016.N().S(` / `)
-//line template/build_job.qtpl:25
+//line build_job.qtpl:27
 	qw422016.E().S(p.Job.Stage.Name)
-//line template/build_job.qtpl:25
+//line build_job.qtpl:27
 	qw422016.N().S(` - `)
-//line template/build_job.qtpl:25
+//line build_job.qtpl:27
 	qw422016.E().S(p.Job.Name)
-//line template/build_job.qtpl:25
+//line build_job.qtpl:27
 	qw422016.N().S(` `)
-//line template/build_job.qtpl:26
+//line build_job.qtpl:28
 	if p.Job.Build.Pinned {
-//line template/build_job.qtpl:26
+//line build_job.qtpl:28
 		qw422016.N().S(` <span class="muted" title="Pinned">`)
-//line template/build_job.qtpl:27
+//line build_job.qtpl:29
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12.75 0l-2.25 2.25 2.25 2.25-5.25 6h-5.25l4.125 4.125-6.375 8.452v0.923h0.923l8.452-6.375 4.125 4.125v-5.25l6-5.25 2.25 2.25 2.25-2.25-11.25-11.25zM10.5 12.75l-1.5-1.5 5.25-5.25 1.5 1.5-5.25 5.25z"></path>
 </svg>
 `)
-//line template/build_job.qtpl:27
+//line build_job.qtpl:29
 		qw422016.N().S(`</span> `)
-//line template/build_job.qtpl:28
+//line build_job.qtpl:30
 	}
-//line template/build_job.qtpl:28
+//line build_job.qtpl:30
 	qw422016.N().S(` `)
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 }
 
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 func (p *BuildJob) WriteHeader(qq422016 qtio422016.Writer) {
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 	p.StreamHeader(qw422016)
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 }
 
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 func (p *BuildJob) Header() string {
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 	p.WriteHeader(qb422016)
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 	qs422016 := string(qb422016.B)
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 	return qs422016
-//line template/build_job.qtpl:29
+//line build_job.qtpl:31
 }
 
-//line template/build_job.qtpl:31
+//line build_job.qtpl:33
 func (p *BuildJob) StreamActions(qw422016 *qt422016.Writer) {
-//line template/build_job.qtpl:31
+//line build_job.qtpl:33
+	qw422016.N().S(` `)
+//line build_job.qtpl:34
+	if p.Job.Status == runner.Waiting && !p.Job.ApprovedBy.Valid {
+//line build_job.qtpl:34
+		qw422016.N().S(` <li> <form method="POST" action="`)
+//line build_job.qtpl:36
+		qw422016.E().S(p.Job.Endpoint("approve"))
+//line build_job.qtpl:36
+		qw422016.N().S(`"> `)
+//line build_job.qtpl:37
+		form.StreamMethod(qw422016, "PATCH")
+//line build_job.qtpl:37
+		qw422016.N().S(` `)
+//line build_job.qtpl:38
+		qw422016.N().V(p.CSRF)
+//line build_job.qtpl:38
+		qw422016.N().S(` <button type="submit" class="btn btn-primary">Approve</button> </form> </li> `)
+//line build_job.qtpl:42
+	}
+//line build_job.qtpl:42
+	qw422016.N().S(` `)
+//line build_job.qtpl:43
 }
 
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 func (p *BuildJob) WriteActions(qq422016 qtio422016.Writer) {
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 	p.StreamActions(qw422016)
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 }
 
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 func (p *BuildJob) Actions() string {
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 	p.WriteActions(qb422016)
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 	qs422016 := string(qb422016.B)
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 	return qs422016
-//line template/build_job.qtpl:31
+//line build_job.qtpl:43
 }
 
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 func (p *BuildJob) StreamNavigation(qw422016 *qt422016.Writer) {
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 }
 
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 func (p *BuildJob) WriteNavigation(qq422016 qtio422016.Writer) {
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 	p.StreamNavigation(qw422016)
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 }
 
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 func (p *BuildJob) Navigation() string {
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 	p.WriteNavigation(qb422016)
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 	qs422016 := string(qb422016.B)
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 	return qs422016
-//line template/build_job.qtpl:32
+//line build_job.qtpl:44
 }
 
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 func (p *BuildJob) StreamFooter(qw422016 *qt422016.Writer) {
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 }
 
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 func (p *BuildJob) WriteFooter(qq422016 qtio422016.Writer) {
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 	p.StreamFooter(qw422016)
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 }
 
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 func (p *BuildJob) Footer() string {
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 	p.WriteFooter(qb422016)
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 	qs422016 := string(qb422016.B)
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 	return qs422016
-//line template/build_job.qtpl:33
+//line build_job.qtpl:45
 }
 
-//line template/build_job.qtpl:35
+//line build_job.qtpl:47
 func (p *BuildJob) streamrenderJobTime(qw422016 *qt422016.Writer, layout string) {
-//line template/build_job.qtpl:35
+//line build_job.qtpl:47
 	qw422016.N().S(` <div class="panel"> <table class="table"> <tr> <td>Status:</td> <td class="align-right">`)
-//line template/build_job.qtpl:40
+//line build_job.qtpl:52
 	StreamStatus(qw422016, p.Job.Status)
-//line template/build_job.qtpl:40
-	qw422016.N().S(`</td> </tr> <tr> <td>Started at:</td> <td class="align-right"> `)
-//line template/build_job.qtpl:45
+//line build_job.qtpl:52
+	qw422016.N().S(`</td> </tr> `)
+//line build_job.qtpl:54
+	if p.Job.Approver != nil {
+//line build_job.qtpl:54
+		qw422016.N().S(` <tr> <td>Approved by:</td> <td class="align-right">`)
+//line build_job.qtpl:57
+		qw422016.E().S(p.Job.Approver.Username)
+//line build_job.qtpl:57
+		qw422016.N().S(` at `)
+//line build_job.qtpl:57
+		qw422016.E().S(p.Job.ApprovedAt.Elem.Format(layout))
+//line build_job.qtpl:57
+		qw422016.N().S(`</td> </tr> `)
+//line build_job.qtpl:59
+	} else if p.Job.Manual {
+//line build_job.qtpl:59
+		qw422016.N().S(` <tr> <td>Approved by:</td> <td class="align-right"><span class="muted">--</span></td> </tr> `)
+//line build_job.qtpl:64
+	}
+//line build_job.qtpl:64
+	qw422016.N().S(` `)
+//line build_job.qtpl:65
+	if p.Job.Attempt > 1 {
+//line build_job.qtpl:65
+		qw422016.N().S(` <tr> <td>Attempt:</td> <td class="align-right">`)
+//line build_job.qtpl:68
+		qw422016.N().D(p.Job.Attempt)
+//line build_job.qtpl:68
+		qw422016.N().S(` of `)
+//line build_job.qtpl:68
+		qw422016.N().D(p.Job.Retry.Max)
+//line build_job.qtpl:68
+		qw422016.N().S(`</td> </tr> `)
+//line build_job.qtpl:70
+	}
+//line build_job.qtpl:70
+	qw422016.N().S(` <tr> <td>Started at:</td> <td class="align-right"> `)
+//line build_job.qtpl:74
 	if p.Job.StartedAt.Valid {
-//line template/build_job.qtpl:45
+//line build_job.qtpl:74
 		qw422016.N().S(` `)
-//line template/build_job.qtpl:46
+//line build_job.qtpl:75
 		qw422016.E().S(p.Job.StartedAt.Elem.Format(layout))
-//line template/build_job.qtpl:46
+//line build_job.qtpl:75
 		qw422016.N().S(` `)
-//line template/build_job.qtpl:47
+//line build_job.qtpl:76
 	} else {
-//line template/build_job.qtpl:47
+//line build_job.qtpl:76
 		qw422016.N().S(` <span class="muted">--</span> `)
-//line template/build_job.qtpl:49
+//line build_job.qtpl:78
 	}
-//line template/build_job.qtpl:49
+//line build_job.qtpl:78
 	qw422016.N().S(` </td> </tr> <tr> <td>Finished at:</td> <td class="align-right"> `)
-//line template/build_job.qtpl:55
+//line build_job.qtpl:84
 	if p.Job.FinishedAt.Valid {
-//line template/build_job.qtpl:55
+//line build_job.qtpl:84
 		qw422016.N().S(` `)
-//line template/build_job.qtpl:56
+//line build_job.qtpl:85
 		qw422016.E().S(p.Job.FinishedAt.Elem.Format(layout))
-//line template/build_job.qtpl:56
+//line build_job.qtpl:85
 		qw422016.N().S(` `)
-//line template/build_job.qtpl:57
+//line build_job.qtpl:86
 	} else {
-//line template/build_job.qtpl:57
+//line build_job.qtpl:86
 		qw422016.N().S(` <span class="muted">--</span> `)
-//line template/build_job.qtpl:59
+//line build_job.qtpl:88
 	}
-//line template/build_job.qtpl:59
+//line build_job.qtpl:88
 	qw422016.N().S(` </td> </tr> <tr> <td>Duration:</td> <td class="align-right"> `)
-//line template/build_job.qtpl:65
+//line build_job.qtpl:94
 	if !p.Job.FinishedAt.Valid || !p.Job.StartedAt.Valid {
-//line template/build_job.qtpl:65
+//line build_job.qtpl:94
 		qw422016.N().S(` <span class="muted">--</span> `)
-//line template/build_job.qtpl:67
+//line build_job.qtpl:96
 	} else {
-//line template/build_job.qtpl:67
+//line build_job.qtpl:96
 		qw422016.N().S(` `)
-//line template/build_job.qtpl:68
+//line build_job.qtpl:97
 		qw422016.E().V(p.Job.FinishedAt.Elem.Sub(p.Job.StartedAt.Elem))
-//line template/build_job.qtpl:68
+//line build_job.qtpl:97
 		qw422016.N().S(` `)
-//line template/build_job.qtpl:69
+//line build_job.qtpl:98
 	}
-//line template/build_job.qtpl:69
+//line build_job.qtpl:98
 	qw422016.N().S(` </td> </tr> </table> </div> `)
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 }
 
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 func (p *BuildJob) writerenderJobTime(qq422016 qtio422016.Writer, layout string) {
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 	p.streamrenderJobTime(qw422016, layout)
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 }
 
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 func (p *BuildJob) renderJobTime(layout string) string {
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 	p.writerenderJobTime(qb422016, layout)
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 	qs422016 := string(qb422016.B)
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 	return qs422016
-//line template/build_job.qtpl:74
+//line build_job.qtpl:103
 }
 
-//line template/build_job.qtpl:76
+//line build_job.qtpl:105
 func (p *BuildJob) streamrenderJobOutput(qw422016 *qt422016.Writer) {
-//line template/build_job.qtpl:76
+//line build_job.qtpl:105
 	qw422016.N().S(` <div class="panel"> `)
-//line template/build_job.qtpl:78
+//line build_job.qtpl:107
 	if p.Job.Output.Valid {
-//line template/build_job.qtpl:78
-		qw422016.N().S(` <div class="panel-header"> <h3>Output</h3> <ul class="panel-actions"> <li> <a class="btn btn-primary" href="`)
-//line template/build_job.qtpl:83
+//line build_job.qtpl:107
+		qw422016.N().S(` <div class="panel-header"> <h3>Output</h3> <ul class="panel-actions"> `)
+//line build_job.qtpl:111
+		if i := p.Job.Results.Failed(); i >= 0 {
+//line build_job.qtpl:111
+			qw422016.N().S(` <li> <a class="btn btn-danger" href="#command-`)
+//line build_job.qtpl:113
+			qw422016.N().D(i + 1)
+//line build_job.qtpl:113
+			qw422016.N().S(`">Failed command</a> </li> `)
+//line build_job.qtpl:115
+		}
+//line build_job.qtpl:115
+		qw422016.N().S(` <li> <a class="btn btn-primary" href="`)
+//line build_job.qtpl:117
 		qw422016.E().S(p.Job.Endpoint("output", "raw"))
-//line template/build_job.qtpl:83
+//line build_job.qtpl:117
 		qw422016.N().S(`"> `)
-//line template/build_job.qtpl:84
+//line build_job.qtpl:118
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12.984 9h5.531l-5.531-5.484v5.484zM15.984 14.016v-2.016h-7.969v2.016h7.969zM15.984 18v-2.016h-7.969v2.016h7.969zM14.016 2.016l6 6v12c0 1.078-0.938 1.969-2.016 1.969h-12c-1.078 0-2.016-0.891-2.016-1.969l0.047-16.031c0-1.078 0.891-1.969 1.969-1.969h8.016z"></path>
 </svg>
 `)
-//line template/build_job.qtpl:84
+//line build_job.qtpl:118
 		qw422016.N().S(`<span>Raw</span> </a> </li> </ul> </div> `)
-//line template/build_job.qtpl:89
-		StreamCode(qw422016, p.Job.Output.Elem)
-//line template/build_job.qtpl:89
+//line build_job.qtpl:123
+		StreamCodeSections(qw422016, p.Job.Output.Elem, p.Job.Results)
+//line build_job.qtpl:123
 		qw422016.N().S(` `)
-//line template/build_job.qtpl:90
+//line build_job.qtpl:124
 	} else {
-//line template/build_job.qtpl:90
+//line build_job.qtpl:124
 		qw422016.N().S(` <div class="panel-message muted">No job output has been produced.</div> `)
-//line template/build_job.qtpl:92
+//line build_job.qtpl:126
 	}
-//line template/build_job.qtpl:92
+//line build_job.qtpl:126
 	qw422016.N().S(` </div> `)
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
 }
 
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
 func (p *BuildJob) writerenderJobOutput(qq422016 qtio422016.Writer) {
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
 	p.streamrenderJobOutput(qw422016)
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
 }
 
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
 func (p *BuildJob) renderJobOutput() string {
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
 	p.writerenderJobOutput(qb422016)
-//line template/build_job.qtpl:94
+//line build_job.qtpl:128
+	qs422016 := string(qb422016.B)
+//line build_job.qtpl:128
+	qt422016.ReleaseByteBuffer(qb422016)
+//line build_job.qtpl:128
+	return qs422016
+//line build_job.qtpl:128
+}
+
+//line build_job.qtpl:130
+func (p *BuildJob) streamrenderJobAttempts(qw422016 *qt422016.Writer) {
+//line build_job.qtpl:130
+	qw422016.N().S(` `)
+//line build_job.qtpl:131
+	for i := len(p.Job.Attempts) - 1; i >= 0; i-- {
+//line build_job.qtpl:131
+		qw422016.N().S(` `)
+//line build_job.qtpl:132
+		a := p.Job.Attempts[i]
+
+//line build_job.qtpl:132
+		qw422016.N().S(` <div class="panel"> <div class="panel-header"> <h3>Attempt `)
+//line build_job.qtpl:135
+		qw422016.N().D(a.Number)
+//line build_job.qtpl:135
+		qw422016.N().S(` &ndash; `)
+//line build_job.qtpl:135
+		StreamStatus(qw422016, a.Status)
+//line build_job.qtpl:135
+		qw422016.N().S(`</h3> </div> `)
+//line build_job.qtpl:137
+		if a.Output.Valid {
+//line build_job.qtpl:137
+			qw422016.N().S(` `)
+//line build_job.qtpl:138
+			StreamCodeSections(qw422016, a.Output.Elem, a.Results)
+//line build_job.qtpl:138
+			qw422016.N().S(` `)
+//line build_job.qtpl:139
+		} else {
+//line build_job.qtpl:139
+			qw422016.N().S(` <div class="panel-message muted">No job output was produced.</div> `)
+//line build_job.qtpl:141
+		}
+//line build_job.qtpl:141
+		qw422016.N().S(` </div> `)
+//line build_job.qtpl:143
+	}
+//line build_job.qtpl:143
+	qw422016.N().S(` `)
+//line build_job.qtpl:144
+}
+
+//line build_job.qtpl:144
+func (p *BuildJob) writerenderJobAttempts(qq422016 qtio422016.Writer) {
+//line build_job.qtpl:144
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line build_job.qtpl:144
+	p.streamrenderJobAttempts(qw422016)
+//line build_job.qtpl:144
+	qt422016.ReleaseWriter(qw422016)
+//line build_job.qtpl:144
+}
+
+//line build_job.qtpl:144
+func (p *BuildJob) renderJobAttempts() string {
+//line build_job.qtpl:144
+	qb422016 := qt422016.AcquireByteBuffer()
+//line build_job.qtpl:144
+	p.writerenderJobAttempts(qb422016)
+//line build_job.qtpl:144
 	qs422016 := string(qb422016.B)
-//line template/build_job.qtpl:94
+//line build_job.qtpl:144
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_job.qtpl:94
+//line build_job.qtpl:144
 	return qs422016
-//line template/build_job.qtpl:94
+//line build_job.qtpl:144
 }
 
-//line template/build_job.qtpl:96
+//line build_job.qtpl:146
 func (p *BuildJob) StreamBody(qw422016 *qt422016.Writer) {
-//line template/build_job.qtpl:96
+//line build_job.qtpl:146
 	qw422016.N().S(` <div class="overflow"> <div class="col-25 col-left"> `)
-//line template/build_job.qtpl:99
+//line build_job.qtpl:149
 	p.streamrenderJobTime(qw422016, "Jan 02, 2006, at 15:04:05")
-//line template/build_job.qtpl:99
+//line build_job.qtpl:149
 	qw422016.N().S(` </div> <div class="col-75 col-right"> `)
-//line template/build_job.qtpl:102
+//line build_job.qtpl:152
 	p.Build.streamrenderBuildTrigger(qw422016)
-//line template/build_job.qtpl:102
+//line build_job.qtpl:152
 	qw422016.N().S(` `)
-//line template/build_job.qtpl:103
+//line build_job.qtpl:153
 	p.streamrenderJobOutput(qw422016)
-//line template/build_job.qtpl:103
+//line build_job.qtpl:153
+	qw422016.N().S(` `)
+//line build_job.qtpl:154
+	p.streamrenderJobAttempts(qw422016)
+//line build_job.qtpl:154
 	qw422016.N().S(` `)
-//line template/build_job.qtpl:104
+//line build_job.qtpl:155
 	p.Artifacts.StreamBody(qw422016)
-//line template/build_job.qtpl:104
+//line build_job.qtpl:155
 	qw422016.N().S(` </div> </div> `)
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 }
 
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 func (p *BuildJob) WriteBody(qq422016 qtio422016.Writer) {
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 	p.StreamBody(qw422016)
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 }
 
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 func (p *BuildJob) Body() string {
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 	p.WriteBody(qb422016)
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 	qs422016 := string(qb422016.B)
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 	return qs422016
-//line template/build_job.qtpl:107
+//line build_job.qtpl:158
 }
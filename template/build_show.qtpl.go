@@ -1,10 +1,10 @@
 // Code generated by qtc from "build_show.qtpl". DO NOT EDIT.
 // See https://github.com/valyala/quicktemplate for details.
 
-//line template/build_show.qtpl:2
+//line build_show.qtpl:2
 package template
 
-//line template/build_show.qtpl:2
+//line build_show.qtpl:2
 import (
 	"regexp"
 
@@ -15,20 +15,20 @@ import (
 	"github.com/hako/durafmt"
 )
 
-//line template/build_show.qtpl:13
+//line build_show.qtpl:13
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line template/build_show.qtpl:13
+//line build_show.qtpl:13
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line template/build_show.qtpl:14
+//line build_show.qtpl:14
 type BuildShow struct {
 	*Page
 
@@ -36,295 +36,295 @@ type BuildShow struct {
 	Partial Partial
 }
 
-//line template/build_show.qtpl:23
+//line build_show.qtpl:23
 func (p *BuildShow) StreamTitle(qw422016 *qt422016.Writer) {
-//line template/build_show.qtpl:23
+//line build_show.qtpl:23
 	qw422016.N().S(` `)
-//line template/build_show.qtpl:24
+//line build_show.qtpl:24
 	if p.Partial != nil {
-//line template/build_show.qtpl:24
+//line build_show.qtpl:24
 		qw422016.N().S(` Build #`)
-//line template/build_show.qtpl:25
+//line build_show.qtpl:25
 		qw422016.E().V(p.Build.Number)
-//line template/build_show.qtpl:25
+//line build_show.qtpl:25
 		qw422016.N().S(` - `)
-//line template/build_show.qtpl:25
+//line build_show.qtpl:25
 		p.Partial.StreamTitle(qw422016)
-//line template/build_show.qtpl:25
+//line build_show.qtpl:25
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:26
+//line build_show.qtpl:26
 	} else {
-//line template/build_show.qtpl:26
+//line build_show.qtpl:26
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:27
+//line build_show.qtpl:27
 		if title := p.Build.Trigger.CommentTitle(); title != "" {
-//line template/build_show.qtpl:27
+//line build_show.qtpl:27
 			qw422016.N().S(` Build #`)
-//line template/build_show.qtpl:28
+//line build_show.qtpl:28
 			qw422016.E().V(p.Build.Number)
-//line template/build_show.qtpl:28
+//line build_show.qtpl:28
 			qw422016.N().S(` - `)
-//line template/build_show.qtpl:28
+//line build_show.qtpl:28
 			qw422016.E().S(title)
-//line template/build_show.qtpl:28
+//line build_show.qtpl:28
 			qw422016.N().S(` `)
-//line template/build_show.qtpl:29
+//line build_show.qtpl:29
 		} else {
-//line template/build_show.qtpl:29
+//line build_show.qtpl:29
 			qw422016.N().S(` Build #`)
-//line template/build_show.qtpl:30
+//line build_show.qtpl:30
 			qw422016.E().V(p.Build.Number)
-//line template/build_show.qtpl:30
+//line build_show.qtpl:30
 			qw422016.N().S(` `)
-//line template/build_show.qtpl:31
+//line build_show.qtpl:31
 		}
-//line template/build_show.qtpl:31
+//line build_show.qtpl:31
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:32
+//line build_show.qtpl:32
 	}
-//line template/build_show.qtpl:32
+//line build_show.qtpl:32
 	qw422016.N().S(` `)
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 }
 
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 func (p *BuildShow) WriteTitle(qq422016 qtio422016.Writer) {
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 	p.StreamTitle(qw422016)
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 }
 
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 func (p *BuildShow) Title() string {
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 	p.WriteTitle(qb422016)
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 	return qs422016
-//line template/build_show.qtpl:33
+//line build_show.qtpl:33
 }
 
-//line template/build_show.qtpl:35
+//line build_show.qtpl:35
 func (p *BuildShow) StreamHeader(qw422016 *qt422016.Writer) {
-//line template/build_show.qtpl:35
+//line build_show.qtpl:35
 	qw422016.N().S(` <a href="/" class="back">`)
-//line template/build_show.qtpl:36
+//line build_show.qtpl:36
 	qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M20.016 11.016v1.969h-12.188l5.578 5.625-1.406 1.406-8.016-8.016 8.016-8.016 1.406 1.406-5.578 5.625h12.188z"></path>
 </svg>
 `)
-//line template/build_show.qtpl:36
+//line build_show.qtpl:36
 	qw422016.N().S(`</a> `)
-//line template/build_show.qtpl:37
+//line build_show.qtpl:37
 	if p.Build.Namespace != nil {
-//line template/build_show.qtpl:37
+//line build_show.qtpl:37
 		qw422016.N().S(` <a href="`)
-//line template/build_show.qtpl:38
+//line build_show.qtpl:38
 		qw422016.E().S(p.Build.Namespace.Endpoint())
-//line template/build_show.qtpl:38
+//line build_show.qtpl:38
 		qw422016.N().S(`">`)
-//line template/build_show.qtpl:38
+//line build_show.qtpl:38
 		qw422016.E().S(p.Build.Namespace.Name)
-//line template/build_show.qtpl:38
+//line build_show.qtpl:38
 		qw422016.N().S(`</a> / `)
-//line template/build_show.qtpl:39
+//line build_show.qtpl:39
 	}
-//line template/build_show.qtpl:39
+//line build_show.qtpl:39
 	qw422016.N().S(` Build #`)
-//line template/build_show.qtpl:40
+//line build_show.qtpl:40
 	qw422016.E().V(p.Build.Number)
-//line template/build_show.qtpl:40
+//line build_show.qtpl:40
 	qw422016.N().S(` `)
-//line template/build_show.qtpl:41
+//line build_show.qtpl:41
 	if p.Build.Pinned {
-//line template/build_show.qtpl:41
+//line build_show.qtpl:41
 		qw422016.N().S(` <span class="muted" title="Pinned">`)
-//line template/build_show.qtpl:42
+//line build_show.qtpl:42
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12.75 0l-2.25 2.25 2.25 2.25-5.25 6h-5.25l4.125 4.125-6.375 8.452v0.923h0.923l8.452-6.375 4.125 4.125v-5.25l6-5.25 2.25 2.25 2.25-2.25-11.25-11.25zM10.5 12.75l-1.5-1.5 5.25-5.25 1.5 1.5-5.25 5.25z"></path>
 </svg>
 `)
-//line template/build_show.qtpl:42
+//line build_show.qtpl:42
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:43
+//line build_show.qtpl:43
 	}
-//line template/build_show.qtpl:43
+//line build_show.qtpl:43
 	qw422016.N().S(` `)
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 }
 
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 func (p *BuildShow) WriteHeader(qq422016 qtio422016.Writer) {
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 	p.StreamHeader(qw422016)
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 }
 
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 func (p *BuildShow) Header() string {
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 	p.WriteHeader(qb422016)
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 	return qs422016
-//line template/build_show.qtpl:44
+//line build_show.qtpl:44
 }
 
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 func (p *BuildShow) StreamFooter(qw422016 *qt422016.Writer) {
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 }
 
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 func (p *BuildShow) WriteFooter(qq422016 qtio422016.Writer) {
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 	p.StreamFooter(qw422016)
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 }
 
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 func (p *BuildShow) Footer() string {
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 	p.WriteFooter(qb422016)
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 	return qs422016
-//line template/build_show.qtpl:46
+//line build_show.qtpl:46
 }
 
-//line template/build_show.qtpl:48
+//line build_show.qtpl:48
 func (p *BuildShow) StreamActions(qw422016 *qt422016.Writer) {
-//line template/build_show.qtpl:48
+//line build_show.qtpl:48
 	qw422016.N().S(` `)
-//line template/build_show.qtpl:49
+//line build_show.qtpl:49
 	if p.User.ID == p.Build.UserID {
-//line template/build_show.qtpl:49
+//line build_show.qtpl:49
 		qw422016.N().S(` <li> <form `)
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 		if p.Build.Pinned {
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 			qw422016.N().S(`action="`)
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 			qw422016.E().S(p.Build.Endpoint("unpin"))
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 			qw422016.N().S(`"`)
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 		} else {
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 			qw422016.N().S(`action="`)
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 			qw422016.E().S(p.Build.Endpoint("pin"))
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 			qw422016.N().S(`"`)
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 		}
-//line template/build_show.qtpl:51
+//line build_show.qtpl:51
 		qw422016.N().S(` method="POST"> `)
-//line template/build_show.qtpl:52
+//line build_show.qtpl:52
 		form.StreamMethod(qw422016, "PATCH")
-//line template/build_show.qtpl:52
+//line build_show.qtpl:52
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:53
+//line build_show.qtpl:53
 		qw422016.N().V(p.CSRF)
-//line template/build_show.qtpl:53
+//line build_show.qtpl:53
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:54
+//line build_show.qtpl:54
 		if p.Build.Pinned {
-//line template/build_show.qtpl:54
+//line build_show.qtpl:54
 			qw422016.N().S(` <button type="submit" class="btn btn-primary">Unpin</button> `)
-//line template/build_show.qtpl:56
+//line build_show.qtpl:56
 		} else {
-//line template/build_show.qtpl:56
+//line build_show.qtpl:56
 			qw422016.N().S(` <button type="submit" class="btn btn-primary">Pin</button> `)
-//line template/build_show.qtpl:58
+//line build_show.qtpl:58
 		}
-//line template/build_show.qtpl:58
+//line build_show.qtpl:58
 		qw422016.N().S(` </form> </li> `)
-//line template/build_show.qtpl:61
-		if p.Build.Status == runner.Running {
-//line template/build_show.qtpl:61
+//line build_show.qtpl:61
+		if p.Build.Status == runner.Running || p.Build.Status == runner.Waiting {
+//line build_show.qtpl:61
 			qw422016.N().S(` <li> <form method="POST" action="`)
-//line template/build_show.qtpl:63
+//line build_show.qtpl:63
 			qw422016.E().S(p.Build.Endpoint())
-//line template/build_show.qtpl:63
+//line build_show.qtpl:63
 			qw422016.N().S(`"> `)
-//line template/build_show.qtpl:64
+//line build_show.qtpl:64
 			form.StreamMethod(qw422016, "DELETE")
-//line template/build_show.qtpl:64
+//line build_show.qtpl:64
 			qw422016.N().S(` `)
-//line template/build_show.qtpl:65
+//line build_show.qtpl:65
 			qw422016.N().V(p.CSRF)
-//line template/build_show.qtpl:65
+//line build_show.qtpl:65
 			qw422016.N().S(` <button type="submit" class="btn btn-danger">Kill</button> </form> </li> `)
-//line template/build_show.qtpl:69
+//line build_show.qtpl:69
 		}
-//line template/build_show.qtpl:69
+//line build_show.qtpl:69
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:70
+//line build_show.qtpl:70
 	}
-//line template/build_show.qtpl:70
+//line build_show.qtpl:70
 	qw422016.N().S(` `)
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 }
 
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 func (p *BuildShow) WriteActions(qq422016 qtio422016.Writer) {
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 	p.StreamActions(qw422016)
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 }
 
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 func (p *BuildShow) Actions() string {
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 	p.WriteActions(qb422016)
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 	return qs422016
-//line template/build_show.qtpl:71
+//line build_show.qtpl:71
 }
 
-//line template/build_show.qtpl:74
+//line build_show.qtpl:74
 func (p *BuildShow) StreamNavigation(qw422016 *qt422016.Writer) {
-//line template/build_show.qtpl:75
+//line build_show.qtpl:75
 	for _, link := range []NavLink{
 		{
 			Title:   "Overview",
@@ -369,470 +369,551 @@ func (p *BuildShow) StreamNavigation(qw422016 *qt422016.Writer) {
 			Pattern: regexp.MustCompile(p.Build.Endpoint("tags")),
 		},
 	} {
-//line template/build_show.qtpl:118
+//line build_show.qtpl:118
 		qw422016.N().S(`<li>`)
-//line template/build_show.qtpl:119
+//line build_show.qtpl:119
 		link.StreamRender(qw422016, p.URL.Path)
-//line template/build_show.qtpl:119
+//line build_show.qtpl:119
 		qw422016.N().S(`</li>`)
-//line template/build_show.qtpl:120
+//line build_show.qtpl:120
 	}
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 }
 
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 func (p *BuildShow) WriteNavigation(qq422016 qtio422016.Writer) {
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 	p.StreamNavigation(qw422016)
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 }
 
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 func (p *BuildShow) Navigation() string {
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 	p.WriteNavigation(qb422016)
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 	return qs422016
-//line template/build_show.qtpl:121
+//line build_show.qtpl:121
 }
 
-//line template/build_show.qtpl:124
+//line build_show.qtpl:124
 func (p *BuildShow) streamrenderBuildTime(qw422016 *qt422016.Writer, layout string) {
-//line template/build_show.qtpl:124
+//line build_show.qtpl:124
 	qw422016.N().S(` <div class="panel"> <table class="table"> <tr> <td>Started at:</td> <td class="align-right"> `)
-//line template/build_show.qtpl:130
+//line build_show.qtpl:130
 	if p.Build.StartedAt.Valid {
-//line template/build_show.qtpl:130
+//line build_show.qtpl:130
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:131
+//line build_show.qtpl:131
 		qw422016.E().S(p.Build.StartedAt.Elem.Format(layout))
-//line template/build_show.qtpl:131
+//line build_show.qtpl:131
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:132
+//line build_show.qtpl:132
 	} else {
-//line template/build_show.qtpl:132
+//line build_show.qtpl:132
 		qw422016.N().S(` <span class="muted">--</span> `)
-//line template/build_show.qtpl:134
+//line build_show.qtpl:134
 	}
-//line template/build_show.qtpl:134
+//line build_show.qtpl:134
 	qw422016.N().S(` </td> </tr> <tr> <td>Finished at:</td> <td class="align-right"> `)
-//line template/build_show.qtpl:140
+//line build_show.qtpl:140
 	if p.Build.FinishedAt.Valid {
-//line template/build_show.qtpl:140
+//line build_show.qtpl:140
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:141
+//line build_show.qtpl:141
 		qw422016.E().S(p.Build.FinishedAt.Elem.Format(layout))
-//line template/build_show.qtpl:141
+//line build_show.qtpl:141
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:142
+//line build_show.qtpl:142
 	} else {
-//line template/build_show.qtpl:142
+//line build_show.qtpl:142
 		qw422016.N().S(` <span class="muted">--</span> `)
-//line template/build_show.qtpl:144
+//line build_show.qtpl:144
 	}
-//line template/build_show.qtpl:144
+//line build_show.qtpl:144
 	qw422016.N().S(` </td> </tr> <tr> <td>Duration:</td> <td class="align-right"> `)
-//line template/build_show.qtpl:150
+//line build_show.qtpl:150
 	if !p.Build.FinishedAt.Valid || !p.Build.StartedAt.Valid {
-//line template/build_show.qtpl:150
+//line build_show.qtpl:150
 		qw422016.N().S(` <span class="muted">--</span> `)
-//line template/build_show.qtpl:152
+//line build_show.qtpl:152
 	} else {
-//line template/build_show.qtpl:152
+//line build_show.qtpl:152
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:153
+//line build_show.qtpl:153
 		qw422016.E().V(durafmt.Parse(p.Build.FinishedAt.Elem.Sub(p.Build.StartedAt.Elem)).LimitFirstN(1))
-//line template/build_show.qtpl:153
+//line build_show.qtpl:153
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:154
+//line build_show.qtpl:154
 	}
-//line template/build_show.qtpl:154
+//line build_show.qtpl:154
 	qw422016.N().S(` </td> </tr> </table> </div> `)
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 }
 
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 func (p *BuildShow) writerenderBuildTime(qq422016 qtio422016.Writer, layout string) {
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 	p.streamrenderBuildTime(qw422016, layout)
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 }
 
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 func (p *BuildShow) renderBuildTime(layout string) string {
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 	p.writerenderBuildTime(qb422016, layout)
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 	return qs422016
-//line template/build_show.qtpl:159
+//line build_show.qtpl:159
 }
 
-//line template/build_show.qtpl:161
+//line build_show.qtpl:161
+func (p *BuildShow) streamrenderBuildJobItem(qw422016 *qt422016.Writer, j *build.Job, indent bool) {
+//line build_show.qtpl:161
+	qw422016.N().S(` <tr> <td> `)
+//line build_show.qtpl:164
+	if indent {
+//line build_show.qtpl:164
+		qw422016.N().S(`<span class="muted">&nbsp;&nbsp;</span>`)
+//line build_show.qtpl:164
+	}
+//line build_show.qtpl:164
+	qw422016.N().S(` `)
+//line build_show.qtpl:165
+	StreamIconStatus(qw422016, j.Status)
+//line build_show.qtpl:165
+	qw422016.N().S(` <a href="`)
+//line build_show.qtpl:165
+	qw422016.E().S(j.Endpoint())
+//line build_show.qtpl:165
+	qw422016.N().S(`">`)
+//line build_show.qtpl:165
+	qw422016.E().S(j.Name)
+//line build_show.qtpl:165
+	qw422016.N().S(`</a> </td> <td class="align-right"> `)
+//line build_show.qtpl:168
+	if !j.StartedAt.Valid || !j.FinishedAt.Valid {
+//line build_show.qtpl:168
+		qw422016.N().S(` <span class="muted">--</span> `)
+//line build_show.qtpl:170
+	} else {
+//line build_show.qtpl:170
+		qw422016.N().S(` `)
+//line build_show.qtpl:171
+		qw422016.E().V(j.FinishedAt.Elem.Sub(j.StartedAt.Elem))
+//line build_show.qtpl:171
+		qw422016.N().S(` `)
+//line build_show.qtpl:172
+	}
+//line build_show.qtpl:172
+	qw422016.N().S(` </td> </tr> `)
+//line build_show.qtpl:175
+}
+
+//line build_show.qtpl:175
+func (p *BuildShow) writerenderBuildJobItem(qq422016 qtio422016.Writer, j *build.Job, indent bool) {
+//line build_show.qtpl:175
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line build_show.qtpl:175
+	p.streamrenderBuildJobItem(qw422016, j, indent)
+//line build_show.qtpl:175
+	qt422016.ReleaseWriter(qw422016)
+//line build_show.qtpl:175
+}
+
+//line build_show.qtpl:175
+func (p *BuildShow) renderBuildJobItem(j *build.Job, indent bool) string {
+//line build_show.qtpl:175
+	qb422016 := qt422016.AcquireByteBuffer()
+//line build_show.qtpl:175
+	p.writerenderBuildJobItem(qb422016, j, indent)
+//line build_show.qtpl:175
+	qs422016 := string(qb422016.B)
+//line build_show.qtpl:175
+	qt422016.ReleaseByteBuffer(qb422016)
+//line build_show.qtpl:175
+	return qs422016
+//line build_show.qtpl:175
+}
+
+//line build_show.qtpl:177
 func (p *BuildShow) streamrenderBuildStageItem(qw422016 *qt422016.Writer, s *build.Stage) {
-//line template/build_show.qtpl:161
+//line build_show.qtpl:177
 	qw422016.N().S(` <div class="panel"> <div class="panel-header"><h3>`)
-//line template/build_show.qtpl:163
+//line build_show.qtpl:179
 	qw422016.E().S(s.Name)
-//line template/build_show.qtpl:163
+//line build_show.qtpl:179
 	qw422016.N().S(`</h3></div> <table class="table"> `)
-//line template/build_show.qtpl:165
-	for _, j := range s.Jobs {
-//line template/build_show.qtpl:165
-		qw422016.N().S(` <tr> <td>`)
-//line template/build_show.qtpl:167
-		StreamIconStatus(qw422016, j.Status)
-//line template/build_show.qtpl:167
-		qw422016.N().S(` <a href="`)
-//line template/build_show.qtpl:167
-		qw422016.E().S(j.Endpoint())
-//line template/build_show.qtpl:167
-		qw422016.N().S(`">`)
-//line template/build_show.qtpl:167
-		qw422016.E().S(j.Name)
-//line template/build_show.qtpl:167
-		qw422016.N().S(`</a></td> <td class="align-right"> `)
-//line template/build_show.qtpl:169
-		if !j.StartedAt.Valid || !j.FinishedAt.Valid {
-//line template/build_show.qtpl:169
-			qw422016.N().S(` <span class="muted">--</span> `)
-//line template/build_show.qtpl:171
+//line build_show.qtpl:181
+	for _, g := range s.JobGroups() {
+//line build_show.qtpl:181
+		qw422016.N().S(` `)
+//line build_show.qtpl:182
+		if g.Parent != "" {
+//line build_show.qtpl:182
+			qw422016.N().S(` <tr><td colspan="2"><strong>`)
+//line build_show.qtpl:183
+			qw422016.E().S(g.Parent)
+//line build_show.qtpl:183
+			qw422016.N().S(`</strong></td></tr> `)
+//line build_show.qtpl:184
+			for _, j := range g.Jobs {
+//line build_show.qtpl:184
+				qw422016.N().S(` `)
+//line build_show.qtpl:185
+				p.streamrenderBuildJobItem(qw422016, j, true)
+//line build_show.qtpl:185
+				qw422016.N().S(` `)
+//line build_show.qtpl:186
+			}
+//line build_show.qtpl:186
+			qw422016.N().S(` `)
+//line build_show.qtpl:187
 		} else {
-//line template/build_show.qtpl:171
+//line build_show.qtpl:187
 			qw422016.N().S(` `)
-//line template/build_show.qtpl:172
-			qw422016.E().V(j.FinishedAt.Elem.Sub(j.StartedAt.Elem))
-//line template/build_show.qtpl:172
+//line build_show.qtpl:188
+			for _, j := range g.Jobs {
+//line build_show.qtpl:188
+				qw422016.N().S(` `)
+//line build_show.qtpl:189
+				p.streamrenderBuildJobItem(qw422016, j, false)
+//line build_show.qtpl:189
+				qw422016.N().S(` `)
+//line build_show.qtpl:190
+			}
+//line build_show.qtpl:190
 			qw422016.N().S(` `)
-//line template/build_show.qtpl:173
+//line build_show.qtpl:191
 		}
-//line template/build_show.qtpl:173
-		qw422016.N().S(` </td> </tr> `)
-//line template/build_show.qtpl:176
+//line build_show.qtpl:191
+		qw422016.N().S(` `)
+//line build_show.qtpl:192
 	}
-//line template/build_show.qtpl:176
+//line build_show.qtpl:192
 	qw422016.N().S(` </table> </div> `)
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 }
 
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 func (p *BuildShow) writerenderBuildStageItem(qq422016 qtio422016.Writer, s *build.Stage) {
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 	p.streamrenderBuildStageItem(qw422016, s)
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 }
 
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 func (p *BuildShow) renderBuildStageItem(s *build.Stage) string {
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 	p.writerenderBuildStageItem(qb422016, s)
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 	return qs422016
-//line template/build_show.qtpl:179
+//line build_show.qtpl:195
 }
 
-//line template/build_show.qtpl:181
+//line build_show.qtpl:197
 func (p *BuildShow) streamrenderBuildTrigger(qw422016 *qt422016.Writer) {
-//line template/build_show.qtpl:181
+//line build_show.qtpl:197
 	qw422016.N().S(` <div class="panel"> <div class="panel-body"> <div class="comment-header"> `)
-//line template/build_show.qtpl:185
+//line build_show.qtpl:201
 	StreamIconStatus(qw422016, p.Build.Status)
-//line template/build_show.qtpl:185
+//line build_show.qtpl:201
 	qw422016.N().S(` `)
-//line template/build_show.qtpl:186
+//line build_show.qtpl:202
 	if p.Build.Trigger.Comment != "" {
-//line template/build_show.qtpl:186
+//line build_show.qtpl:202
 		qw422016.N().S(` <strong class="inline-block mt-5 middle">`)
-//line template/build_show.qtpl:187
+//line build_show.qtpl:203
 		qw422016.E().S(p.Build.Trigger.CommentTitle())
-//line template/build_show.qtpl:187
+//line build_show.qtpl:203
 		qw422016.N().S(`</strong> `)
-//line template/build_show.qtpl:188
+//line build_show.qtpl:204
 	} else {
-//line template/build_show.qtpl:188
+//line build_show.qtpl:204
 		qw422016.N().S(` <em class="inline-block mt-5 middle muted">No build comment.</em> `)
-//line template/build_show.qtpl:190
+//line build_show.qtpl:206
 	}
-//line template/build_show.qtpl:190
+//line build_show.qtpl:206
 	qw422016.N().S(` </div> `)
-//line template/build_show.qtpl:192
+//line build_show.qtpl:208
 	if comment := p.Build.Trigger.CommentBody(); comment != "" {
-//line template/build_show.qtpl:192
+//line build_show.qtpl:208
 		qw422016.N().S(` <br/><pre>`)
-//line template/build_show.qtpl:193
+//line build_show.qtpl:209
 		qw422016.E().S(comment)
-//line template/build_show.qtpl:193
+//line build_show.qtpl:209
 		qw422016.N().S(`</pre> `)
-//line template/build_show.qtpl:194
+//line build_show.qtpl:210
 	}
-//line template/build_show.qtpl:194
+//line build_show.qtpl:210
 	qw422016.N().S(` </div> <div class="panel-footer"> <strong>`)
-//line template/build_show.qtpl:197
+//line build_show.qtpl:213
 	qw422016.E().S(p.Build.Trigger.Data["username"])
-//line template/build_show.qtpl:197
+//line build_show.qtpl:213
 	qw422016.N().S(`</strong> `)
-//line template/build_show.qtpl:198
+//line build_show.qtpl:214
 	switch p.Build.Trigger.Type {
-//line template/build_show.qtpl:199
+//line build_show.qtpl:215
 	case build.Manual:
-//line template/build_show.qtpl:199
+//line build_show.qtpl:215
 		qw422016.N().S(` submitted `)
-//line template/build_show.qtpl:201
+//line build_show.qtpl:217
 	case build.Push:
-//line template/build_show.qtpl:201
+//line build_show.qtpl:217
 		qw422016.N().S(` committed <a target="_blank" href="`)
-//line template/build_show.qtpl:203
+//line build_show.qtpl:219
 		qw422016.E().S(p.Build.Trigger.Data["url"])
-//line template/build_show.qtpl:203
+//line build_show.qtpl:219
 		qw422016.N().S(`"> `)
-//line template/build_show.qtpl:204
+//line build_show.qtpl:220
 		qw422016.E().S(p.Build.Trigger.Data["sha"][:7])
-//line template/build_show.qtpl:204
+//line build_show.qtpl:220
 		qw422016.N().S(` </a> to <span class="code">`)
-//line template/build_show.qtpl:205
+//line build_show.qtpl:221
 		qw422016.E().S(p.Build.Trigger.Data["ref"])
-//line template/build_show.qtpl:205
+//line build_show.qtpl:221
 		qw422016.N().S(`</span> `)
-//line template/build_show.qtpl:206
+//line build_show.qtpl:222
 	case build.Pull:
-//line template/build_show.qtpl:206
+//line build_show.qtpl:222
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:207
+//line build_show.qtpl:223
 		qw422016.E().S(p.Build.Trigger.Data["action"])
-//line template/build_show.qtpl:207
+//line build_show.qtpl:223
 		qw422016.N().S(` pull request <a target="_blank" href="`)
-//line template/build_show.qtpl:208
+//line build_show.qtpl:224
 		qw422016.E().S(p.Build.Trigger.Data["url"])
-//line template/build_show.qtpl:208
+//line build_show.qtpl:224
 		qw422016.N().S(`"> #`)
-//line template/build_show.qtpl:209
+//line build_show.qtpl:225
 		qw422016.E().S(p.Build.Trigger.Data["id"])
-//line template/build_show.qtpl:209
+//line build_show.qtpl:225
 		qw422016.N().S(` </a> to <span class="code">`)
-//line template/build_show.qtpl:210
+//line build_show.qtpl:226
 		qw422016.E().S(p.Build.Trigger.Data["ref"])
-//line template/build_show.qtpl:210
+//line build_show.qtpl:226
 		qw422016.N().S(`</span> with commit <span class="code">`)
-//line template/build_show.qtpl:211
+//line build_show.qtpl:227
 		qw422016.E().S(p.Build.Trigger.Data["sha"][:7])
-//line template/build_show.qtpl:211
+//line build_show.qtpl:227
 		qw422016.N().S(`</span> `)
-//line template/build_show.qtpl:212
+//line build_show.qtpl:228
 	}
-//line template/build_show.qtpl:212
+//line build_show.qtpl:228
 	qw422016.N().S(` </div> `)
-//line template/build_show.qtpl:214
+//line build_show.qtpl:230
 	if len(p.Build.Tags) > 0 {
-//line template/build_show.qtpl:214
+//line build_show.qtpl:230
 		qw422016.N().S(` <div class="panel-footer"> `)
-//line template/build_show.qtpl:216
+//line build_show.qtpl:232
 		for _, t := range p.Build.Tags {
-//line template/build_show.qtpl:216
+//line build_show.qtpl:232
 			qw422016.N().S(` <a href="/builds?tag=`)
-//line template/build_show.qtpl:217
+//line build_show.qtpl:233
 			qw422016.E().S(t.Name)
-//line template/build_show.qtpl:217
+//line build_show.qtpl:233
 			qw422016.N().S(`" class="pill pill-light">`)
-//line template/build_show.qtpl:217
+//line build_show.qtpl:233
 			qw422016.E().S(t.Name)
-//line template/build_show.qtpl:217
+//line build_show.qtpl:233
 			qw422016.N().S(`</a> `)
-//line template/build_show.qtpl:218
+//line build_show.qtpl:234
 		}
-//line template/build_show.qtpl:218
+//line build_show.qtpl:234
 		qw422016.N().S(` </div> `)
-//line template/build_show.qtpl:220
+//line build_show.qtpl:236
 	}
-//line template/build_show.qtpl:220
+//line build_show.qtpl:236
 	qw422016.N().S(` </div> `)
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 }
 
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 func (p *BuildShow) writerenderBuildTrigger(qq422016 qtio422016.Writer) {
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 	p.streamrenderBuildTrigger(qw422016)
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 }
 
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 func (p *BuildShow) renderBuildTrigger() string {
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 	p.writerenderBuildTrigger(qb422016)
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 	return qs422016
-//line template/build_show.qtpl:222
+//line build_show.qtpl:238
 }
 
-//line template/build_show.qtpl:224
+//line build_show.qtpl:240
 func (p *BuildShow) streamrenderBuildOutput(qw422016 *qt422016.Writer) {
-//line template/build_show.qtpl:224
+//line build_show.qtpl:240
 	qw422016.N().S(` <div class="panel"> `)
-//line template/build_show.qtpl:226
+//line build_show.qtpl:242
 	if p.Build.Output.Valid {
-//line template/build_show.qtpl:226
+//line build_show.qtpl:242
 		qw422016.N().S(` <div class="panel-header"> <ul class="panel-actions"> <li> <a class="btn btn-primary" href="`)
-//line template/build_show.qtpl:230
+//line build_show.qtpl:246
 		qw422016.E().S(p.Build.Endpoint("output", "raw"))
-//line template/build_show.qtpl:230
+//line build_show.qtpl:246
 		qw422016.N().S(`"> `)
-//line template/build_show.qtpl:231
+//line build_show.qtpl:247
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12.984 9h5.531l-5.531-5.484v5.484zM15.984 14.016v-2.016h-7.969v2.016h7.969zM15.984 18v-2.016h-7.969v2.016h7.969zM14.016 2.016l6 6v12c0 1.078-0.938 1.969-2.016 1.969h-12c-1.078 0-2.016-0.891-2.016-1.969l0.047-16.031c0-1.078 0.891-1.969 1.969-1.969h8.016z"></path>
 </svg>
 `)
-//line template/build_show.qtpl:231
+//line build_show.qtpl:247
 		qw422016.N().S(`<span>Raw</span> </a> </li> </ul> </div> `)
-//line template/build_show.qtpl:236
+//line build_show.qtpl:252
 		StreamCode(qw422016, p.Build.Output.Elem)
-//line template/build_show.qtpl:236
+//line build_show.qtpl:252
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:237
+//line build_show.qtpl:253
 	} else {
-//line template/build_show.qtpl:237
+//line build_show.qtpl:253
 		qw422016.N().S(` <div class="panel-message muted">No build output has been produced.</div> `)
-//line template/build_show.qtpl:239
+//line build_show.qtpl:255
 	}
-//line template/build_show.qtpl:239
+//line build_show.qtpl:255
 	qw422016.N().S(` </div> `)
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 }
 
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 func (p *BuildShow) writerenderBuildOutput(qq422016 qtio422016.Writer) {
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 	p.streamrenderBuildOutput(qw422016)
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 }
 
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 func (p *BuildShow) renderBuildOutput() string {
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 	p.writerenderBuildOutput(qb422016)
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 	return qs422016
-//line template/build_show.qtpl:241
+//line build_show.qtpl:257
 }
 
-//line template/build_show.qtpl:243
+//line build_show.qtpl:259
 func (p *BuildShow) StreamBody(qw422016 *qt422016.Writer) {
-//line template/build_show.qtpl:243
+//line build_show.qtpl:259
 	qw422016.N().S(` <div class="overflow"> <div class="col-25 col-left"> `)
-//line template/build_show.qtpl:246
+//line build_show.qtpl:262
 	p.streamrenderBuildTime(qw422016, "Jan 02, 2006, at 15:04:05")
-//line template/build_show.qtpl:246
+//line build_show.qtpl:262
 	qw422016.N().S(` `)
-//line template/build_show.qtpl:247
+//line build_show.qtpl:263
 	for _, s := range p.Build.Stages {
-//line template/build_show.qtpl:247
+//line build_show.qtpl:263
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:248
+//line build_show.qtpl:264
 		p.streamrenderBuildStageItem(qw422016, s)
-//line template/build_show.qtpl:248
+//line build_show.qtpl:264
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:249
+//line build_show.qtpl:265
 	}
-//line template/build_show.qtpl:249
+//line build_show.qtpl:265
 	qw422016.N().S(` </div> <div class="col-75 col-right"> `)
-//line template/build_show.qtpl:252
+//line build_show.qtpl:268
 	p.streamrenderBuildTrigger(qw422016)
-//line template/build_show.qtpl:252
+//line build_show.qtpl:268
 	qw422016.N().S(` `)
-//line template/build_show.qtpl:253
+//line build_show.qtpl:269
 	if p.Partial != nil {
-//line template/build_show.qtpl:253
+//line build_show.qtpl:269
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:254
+//line build_show.qtpl:270
 		p.Partial.StreamBody(qw422016)
-//line template/build_show.qtpl:254
+//line build_show.qtpl:270
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:255
+//line build_show.qtpl:271
 	} else {
-//line template/build_show.qtpl:255
+//line build_show.qtpl:271
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:256
+//line build_show.qtpl:272
 		p.streamrenderBuildOutput(qw422016)
-//line template/build_show.qtpl:256
+//line build_show.qtpl:272
 		qw422016.N().S(` `)
-//line template/build_show.qtpl:257
+//line build_show.qtpl:273
 	}
-//line template/build_show.qtpl:257
+//line build_show.qtpl:273
 	qw422016.N().S(` </div> </div> `)
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 }
 
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 func (p *BuildShow) WriteBody(qq422016 qtio422016.Writer) {
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 	p.StreamBody(qw422016)
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 	qt422016.ReleaseWriter(qw422016)
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 }
 
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 func (p *BuildShow) Body() string {
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 	p.WriteBody(qb422016)
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 	qs422016 := string(qb422016.B)
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 	return qs422016
-//line template/build_show.qtpl:260
+//line build_show.qtpl:276
 }
@@ -1,29 +1,29 @@
 // Code generated by qtc from "cron_form.qtpl". DO NOT EDIT.
 // See https://github.com/valyala/quicktemplate for details.
 
-//line template/cron_form.qtpl:2
+//line cron_form.qtpl:2
 package template
 
-//line template/cron_form.qtpl:2
+//line cron_form.qtpl:2
 import (
 	"djinn-ci.com/cron"
 	"djinn-ci.com/template/form"
 )
 
-//line template/cron_form.qtpl:8
+//line cron_form.qtpl:8
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line template/cron_form.qtpl:8
+//line cron_form.qtpl:8
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line template/cron_form.qtpl:9
+//line cron_form.qtpl:9
 type CronForm struct {
 	*form.Form
 
@@ -37,246 +37,246 @@ func (p *CronForm) action() string {
 	return "/cron"
 }
 
-//line template/cron_form.qtpl:24
+//line cron_form.qtpl:24
 func (p *CronForm) StreamTitle(qw422016 *qt422016.Writer) {
-//line template/cron_form.qtpl:24
+//line cron_form.qtpl:24
 	qw422016.N().S(` `)
-//line template/cron_form.qtpl:25
+//line cron_form.qtpl:25
 	if p.Cron == nil {
-//line template/cron_form.qtpl:25
+//line cron_form.qtpl:25
 		qw422016.N().S(` Create Cron Job `)
-//line template/cron_form.qtpl:27
+//line cron_form.qtpl:27
 	} else {
-//line template/cron_form.qtpl:27
+//line cron_form.qtpl:27
 		qw422016.N().S(` Edit Cron Job `)
-//line template/cron_form.qtpl:29
+//line cron_form.qtpl:29
 	}
-//line template/cron_form.qtpl:29
+//line cron_form.qtpl:29
 	qw422016.N().S(` `)
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 }
 
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 func (p *CronForm) WriteTitle(qq422016 qtio422016.Writer) {
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 	p.StreamTitle(qw422016)
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 	qt422016.ReleaseWriter(qw422016)
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 }
 
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 func (p *CronForm) Title() string {
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 	p.WriteTitle(qb422016)
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 	qs422016 := string(qb422016.B)
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 	return qs422016
-//line template/cron_form.qtpl:30
+//line cron_form.qtpl:30
 }
 
-//line template/cron_form.qtpl:32
+//line cron_form.qtpl:32
 func (p *CronForm) StreamHeader(qw422016 *qt422016.Writer) {
-//line template/cron_form.qtpl:32
+//line cron_form.qtpl:32
 	qw422016.N().S(` `)
-//line template/cron_form.qtpl:33
+//line cron_form.qtpl:33
 	if p.Cron != nil {
-//line template/cron_form.qtpl:33
+//line cron_form.qtpl:33
 		qw422016.N().S(` <a class="back" href="`)
-//line template/cron_form.qtpl:34
+//line cron_form.qtpl:34
 		qw422016.E().S(p.Cron.Endpoint())
-//line template/cron_form.qtpl:34
+//line cron_form.qtpl:34
 		qw422016.N().S(`">`)
-//line template/cron_form.qtpl:34
+//line cron_form.qtpl:34
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M20.016 11.016v1.969h-12.188l5.578 5.625-1.406 1.406-8.016-8.016 8.016-8.016 1.406 1.406-5.578 5.625h12.188z"></path>
 </svg>
 `)
-//line template/cron_form.qtpl:34
+//line cron_form.qtpl:34
 		qw422016.N().S(`</a> `)
-//line template/cron_form.qtpl:35
+//line cron_form.qtpl:35
 		qw422016.E().S(p.Cron.Name)
-//line template/cron_form.qtpl:35
+//line cron_form.qtpl:35
 		qw422016.N().S(` - Edit `)
-//line template/cron_form.qtpl:36
+//line cron_form.qtpl:36
 	} else {
-//line template/cron_form.qtpl:36
+//line cron_form.qtpl:36
 		qw422016.N().S(` <a class="back" href="/cron">`)
-//line template/cron_form.qtpl:37
+//line cron_form.qtpl:37
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M20.016 11.016v1.969h-12.188l5.578 5.625-1.406 1.406-8.016-8.016 8.016-8.016 1.406 1.406-5.578 5.625h12.188z"></path>
 </svg>
 `)
-//line template/cron_form.qtpl:37
+//line cron_form.qtpl:37
 		qw422016.N().S(`</a> Create Cron Job `)
-//line template/cron_form.qtpl:38
+//line cron_form.qtpl:38
 	}
-//line template/cron_form.qtpl:38
+//line cron_form.qtpl:38
 	qw422016.N().S(` `)
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 }
 
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 func (p *CronForm) WriteHeader(qq422016 qtio422016.Writer) {
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 	p.StreamHeader(qw422016)
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 	qt422016.ReleaseWriter(qw422016)
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 }
 
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 func (p *CronForm) Header() string {
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 	p.WriteHeader(qb422016)
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 	qs422016 := string(qb422016.B)
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 	return qs422016
-//line template/cron_form.qtpl:39
+//line cron_form.qtpl:39
 }
 
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 func (p *CronForm) StreamActions(qw422016 *qt422016.Writer) {
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 }
 
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 func (p *CronForm) WriteActions(qq422016 qtio422016.Writer) {
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 	p.StreamActions(qw422016)
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 	qt422016.ReleaseWriter(qw422016)
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 }
 
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 func (p *CronForm) Actions() string {
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 	p.WriteActions(qb422016)
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 	qs422016 := string(qb422016.B)
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 	return qs422016
-//line template/cron_form.qtpl:41
+//line cron_form.qtpl:41
 }
 
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 func (p *CronForm) StreamNavigation(qw422016 *qt422016.Writer) {
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 }
 
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 func (p *CronForm) WriteNavigation(qq422016 qtio422016.Writer) {
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 	p.StreamNavigation(qw422016)
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 	qt422016.ReleaseWriter(qw422016)
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 }
 
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 func (p *CronForm) Navigation() string {
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 	p.WriteNavigation(qb422016)
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 	qs422016 := string(qb422016.B)
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 	return qs422016
-//line template/cron_form.qtpl:42
+//line cron_form.qtpl:42
 }
 
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 func (p *CronForm) StreamFooter(qw422016 *qt422016.Writer) {
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 }
 
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 func (p *CronForm) WriteFooter(qq422016 qtio422016.Writer) {
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 	p.StreamFooter(qw422016)
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 	qt422016.ReleaseWriter(qw422016)
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 }
 
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 func (p *CronForm) Footer() string {
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 	p.WriteFooter(qb422016)
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 	qs422016 := string(qb422016.B)
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 	return qs422016
-//line template/cron_form.qtpl:43
+//line cron_form.qtpl:43
 }
 
-//line template/cron_form.qtpl:45
+//line cron_form.qtpl:45
 func (p *CronForm) StreamBody(qw422016 *qt422016.Writer) {
-//line template/cron_form.qtpl:45
+//line cron_form.qtpl:45
 	qw422016.N().S(` <div class="panel"> <form class="panel-body slim" method="POST" action="`)
-//line template/cron_form.qtpl:47
+//line cron_form.qtpl:47
 	qw422016.E().S(p.action())
-//line template/cron_form.qtpl:47
+//line cron_form.qtpl:47
 	qw422016.N().S(`"> `)
-//line template/cron_form.qtpl:48
+//line cron_form.qtpl:48
 	if p.Cron != nil {
-//line template/cron_form.qtpl:48
+//line cron_form.qtpl:48
 		qw422016.N().S(` `)
-//line template/cron_form.qtpl:49
+//line cron_form.qtpl:49
 		form.StreamMethod(qw422016, "PATCH")
-//line template/cron_form.qtpl:49
+//line cron_form.qtpl:49
 		qw422016.N().S(` `)
-//line template/cron_form.qtpl:50
+//line cron_form.qtpl:50
 	}
-//line template/cron_form.qtpl:50
+//line cron_form.qtpl:50
 	qw422016.N().S(` `)
-//line template/cron_form.qtpl:51
+//line cron_form.qtpl:51
 	qw422016.N().V(p.CSRF)
-//line template/cron_form.qtpl:51
+//line cron_form.qtpl:51
 	qw422016.N().S(` `)
-//line template/cron_form.qtpl:52
+//line cron_form.qtpl:52
 	p.StreamField(qw422016, form.Field{
 		ID:   "name",
 		Name: "Name",
 		Type: form.Text,
 	})
-//line template/cron_form.qtpl:56
+//line cron_form.qtpl:56
 	qw422016.N().S(` `)
-//line template/cron_form.qtpl:57
+//line cron_form.qtpl:57
 	p.StreamFieldGroup(qw422016, "schedule", form.Radio, form.Field{
 		Name:    "Daily",
 		Desc:    "Run the build at the start of each day",
@@ -291,53 +291,63 @@ func (p *CronForm) StreamBody(qw422016 *qt422016.Writer) {
 		Desc:  "Run the build at the start of each month",
 		Value: "monthly",
 	})
-//line template/cron_form.qtpl:70
+//line cron_form.qtpl:70
 	qw422016.N().S(` `)
-//line template/cron_form.qtpl:71
+//line cron_form.qtpl:71
 	p.StreamField(qw422016, form.Field{
 		ID:   "manifest",
 		Name: "Manifest",
 		Type: form.Textarea,
 	})
-//line template/cron_form.qtpl:75
+//line cron_form.qtpl:75
+	qw422016.N().S(` `)
+//line cron_form.qtpl:76
+	p.StreamField(qw422016, form.Field{
+		ID:       "params",
+		Name:     "Params",
+		Type:     form.Textarea,
+		Optional: true,
+		Desc:     "Values for the params declared in the manifest, one per line as name=value.",
+	})
+//line cron_form.qtpl:82
 	qw422016.N().S(` <div class="form-field"> `)
-//line template/cron_form.qtpl:77
+//line cron_form.qtpl:84
 	if p.Cron == nil {
-//line template/cron_form.qtpl:77
+//line cron_form.qtpl:84
 		qw422016.N().S(` <button type="submit" class="btn btn-primary">Create</button> `)
-//line template/cron_form.qtpl:79
+//line cron_form.qtpl:86
 	} else {
-//line template/cron_form.qtpl:79
+//line cron_form.qtpl:86
 		qw422016.N().S(` <button type="submit" class="btn btn-primary">Save</button> `)
-//line template/cron_form.qtpl:81
+//line cron_form.qtpl:88
 	}
-//line template/cron_form.qtpl:81
+//line cron_form.qtpl:88
 	qw422016.N().S(` </div> </form> </div> `)
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 }
 
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 func (p *CronForm) WriteBody(qq422016 qtio422016.Writer) {
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 	p.StreamBody(qw422016)
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 	qt422016.ReleaseWriter(qw422016)
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 }
 
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 func (p *CronForm) Body() string {
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 	p.WriteBody(qb422016)
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 	qs422016 := string(qb422016.B)
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 	return qs422016
-//line template/cron_form.qtpl:85
+//line cron_form.qtpl:92
 }
@@ -1,267 +1,280 @@
 // Code generated by qtc from "image_create.qtpl". DO NOT EDIT.
 // See https://github.com/valyala/quicktemplate for details.
 
-//line template/image_create.qtpl:1
+//line image_create.qtpl:1
 package template
 
-//line template/image_create.qtpl:1
+//line image_create.qtpl:1
 import "djinn-ci.com/template/form"
 
-//line template/image_create.qtpl:3
+//line image_create.qtpl:3
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line template/image_create.qtpl:3
+//line image_create.qtpl:3
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line template/image_create.qtpl:4
+//line image_create.qtpl:4
 type ImageCreate struct {
 	*form.Form
 }
 
-//line template/image_create.qtpl:10
+//line image_create.qtpl:10
 func (p *ImageCreate) StreamTitle(qw422016 *qt422016.Writer) {
-//line template/image_create.qtpl:10
+//line image_create.qtpl:10
 	qw422016.N().S(` Add Image `)
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 }
 
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 func (p *ImageCreate) WriteTitle(qq422016 qtio422016.Writer) {
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 	p.StreamTitle(qw422016)
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 	qt422016.ReleaseWriter(qw422016)
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 }
 
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 func (p *ImageCreate) Title() string {
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 	p.WriteTitle(qb422016)
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 	qs422016 := string(qb422016.B)
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 	return qs422016
-//line template/image_create.qtpl:12
+//line image_create.qtpl:12
 }
 
-//line template/image_create.qtpl:14
+//line image_create.qtpl:14
 func (p *ImageCreate) StreamHeader(qw422016 *qt422016.Writer) {
-//line template/image_create.qtpl:14
+//line image_create.qtpl:14
 	qw422016.N().S(` <a class="back" href="/images">`)
-//line template/image_create.qtpl:15
+//line image_create.qtpl:15
 	qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M20.016 11.016v1.969h-12.188l5.578 5.625-1.406 1.406-8.016-8.016 8.016-8.016 1.406 1.406-5.578 5.625h12.188z"></path>
 </svg>
 `)
-//line template/image_create.qtpl:15
+//line image_create.qtpl:15
 	qw422016.N().S(`</a> `)
-//line template/image_create.qtpl:15
+//line image_create.qtpl:15
 	p.StreamTitle(qw422016)
-//line template/image_create.qtpl:15
+//line image_create.qtpl:15
 	qw422016.N().S(` `)
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 }
 
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 func (p *ImageCreate) WriteHeader(qq422016 qtio422016.Writer) {
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 	p.StreamHeader(qw422016)
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 	qt422016.ReleaseWriter(qw422016)
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 }
 
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 func (p *ImageCreate) Header() string {
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 	p.WriteHeader(qb422016)
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 	qs422016 := string(qb422016.B)
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 	return qs422016
-//line template/image_create.qtpl:16
+//line image_create.qtpl:16
 }
 
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 func (p *ImageCreate) StreamActions(qw422016 *qt422016.Writer) {
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 }
 
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 func (p *ImageCreate) WriteActions(qq422016 qtio422016.Writer) {
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 	p.StreamActions(qw422016)
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 	qt422016.ReleaseWriter(qw422016)
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 }
 
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 func (p *ImageCreate) Actions() string {
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 	p.WriteActions(qb422016)
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 	qs422016 := string(qb422016.B)
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 	return qs422016
-//line template/image_create.qtpl:18
+//line image_create.qtpl:18
 }
 
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 func (p *ImageCreate) StreamNavigation(qw422016 *qt422016.Writer) {
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 }
 
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 func (p *ImageCreate) WriteNavigation(qq422016 qtio422016.Writer) {
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 	p.StreamNavigation(qw422016)
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 	qt422016.ReleaseWriter(qw422016)
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 }
 
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 func (p *ImageCreate) Navigation() string {
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 	p.WriteNavigation(qb422016)
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 	qs422016 := string(qb422016.B)
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 	return qs422016
-//line template/image_create.qtpl:19
+//line image_create.qtpl:19
 }
 
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 func (p *ImageCreate) StreamFooter(qw422016 *qt422016.Writer) {
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 }
 
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 func (p *ImageCreate) WriteFooter(qq422016 qtio422016.Writer) {
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 	p.StreamFooter(qw422016)
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 	qt422016.ReleaseWriter(qw422016)
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 }
 
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 func (p *ImageCreate) Footer() string {
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 	p.WriteFooter(qb422016)
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 	qs422016 := string(qb422016.B)
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 	return qs422016
-//line template/image_create.qtpl:20
+//line image_create.qtpl:20
 }
 
-//line template/image_create.qtpl:22
+//line image_create.qtpl:22
 func (p *ImageCreate) StreamBody(qw422016 *qt422016.Writer) {
-//line template/image_create.qtpl:22
+//line image_create.qtpl:22
 	qw422016.N().S(` <div class="alert alert-warn"> Large images will take a long time to upload. It is recommended that you have them downloaded via a URL. </div> <br/> <div class="panel"> <form class="panel-body slim" method="POST" action="/images" enctype="multipart/form-data"> `)
-//line template/image_create.qtpl:29
+//line image_create.qtpl:29
 	qw422016.N().V(p.CSRF)
-//line template/image_create.qtpl:29
+//line image_create.qtpl:29
 	qw422016.N().S(` `)
-//line template/image_create.qtpl:30
+//line image_create.qtpl:30
 	p.StreamField(qw422016, form.Field{
 		ID:       "namespace",
 		Name:     "Namespace",
 		Optional: true,
 		Type:     form.Text,
 	})
-//line template/image_create.qtpl:35
+//line image_create.qtpl:35
 	qw422016.N().S(` `)
-//line template/image_create.qtpl:36
+//line image_create.qtpl:36
 	p.StreamField(qw422016, form.Field{
 		ID:   "name",
 		Name: "Name",
 		Type: form.Text,
 	})
-//line template/image_create.qtpl:40
+//line image_create.qtpl:40
 	qw422016.N().S(` `)
-//line template/image_create.qtpl:41
+//line image_create.qtpl:41
+	p.StreamFieldGroup(qw422016, "driver", form.Radio, form.Field{
+		Name:    "QEMU",
+		Desc:    "A QCOW2 disk image for the qemu driver",
+		Value:   "qemu",
+		Checked: true,
+	}, form.Field{
+		Name:  "Sandbox",
+		Desc:  "A tar archive of a root filesystem for the sandbox driver",
+		Value: "sandbox",
+	})
+//line image_create.qtpl:50
+	qw422016.N().S(` `)
+//line image_create.qtpl:51
 	p.StreamField(qw422016, form.Field{
 		ID:   "download_url",
 		Name: "Download URL",
 		Type: form.Text,
 	})
-//line template/image_create.qtpl:45
+//line image_create.qtpl:55
 	qw422016.N().S(` `)
-//line template/image_create.qtpl:46
+//line image_create.qtpl:56
 	p.StreamField(qw422016, form.Field{
 		ID:   "file",
 		Name: "File",
 		Type: form.File,
 	})
-//line template/image_create.qtpl:50
+//line image_create.qtpl:60
 	qw422016.N().S(` <div class="form-field"> <button type="submit" class="btn btn-primary">Create</button> </div> </div> `)
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 }
 
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 func (p *ImageCreate) WriteBody(qq422016 qtio422016.Writer) {
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 	p.StreamBody(qw422016)
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 	qt422016.ReleaseWriter(qw422016)
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 }
 
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 func (p *ImageCreate) Body() string {
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 	p.WriteBody(qb422016)
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 	qs422016 := string(qb422016.B)
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 	return qs422016
-//line template/image_create.qtpl:55
+//line image_create.qtpl:65
 }
@@ -1,10 +1,10 @@
 // Code generated by qtc from "namespace_form.qtpl". DO NOT EDIT.
 // See https://github.com/valyala/quicktemplate for details.
 
-//line template/namespace_form.qtpl:2
+//line namespace_form.qtpl:2
 package template
 
-//line template/namespace_form.qtpl:2
+//line namespace_form.qtpl:2
 import (
 	"strings"
 
@@ -12,20 +12,20 @@ import (
 	"djinn-ci.com/template/form"
 )
 
-//line template/namespace_form.qtpl:10
+//line namespace_form.qtpl:10
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line template/namespace_form.qtpl:10
+//line namespace_form.qtpl:10
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line template/namespace_form.qtpl:11
+//line namespace_form.qtpl:11
 type NamespaceForm struct {
 	*form.Form
 
@@ -66,543 +66,553 @@ func (p *NamespaceForm) disabled(v namespace.Visibility) string {
 	return ""
 }
 
-//line template/namespace_form.qtpl:53
+//line namespace_form.qtpl:53
 func streamrenderNamespacePath(qw422016 *qt422016.Writer, username string, parts []string) {
-//line template/namespace_form.qtpl:53
+//line namespace_form.qtpl:53
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:54
+//line namespace_form.qtpl:54
 	for i, part := range parts {
-//line template/namespace_form.qtpl:54
+//line namespace_form.qtpl:54
 		qw422016.N().S(` <a href="/n/`)
-//line template/namespace_form.qtpl:55
+//line namespace_form.qtpl:55
 		qw422016.E().S(username)
-//line template/namespace_form.qtpl:55
+//line namespace_form.qtpl:55
 		qw422016.N().S(`/`)
-//line template/namespace_form.qtpl:55
+//line namespace_form.qtpl:55
 		qw422016.E().S(strings.Join(parts[:i+1], "/"))
-//line template/namespace_form.qtpl:55
+//line namespace_form.qtpl:55
 		qw422016.N().S(`">`)
-//line template/namespace_form.qtpl:55
+//line namespace_form.qtpl:55
 		qw422016.E().S(part)
-//line template/namespace_form.qtpl:55
+//line namespace_form.qtpl:55
 		qw422016.N().S(`</a> `)
-//line template/namespace_form.qtpl:56
+//line namespace_form.qtpl:56
 		if i != len(parts)-1 {
-//line template/namespace_form.qtpl:56
+//line namespace_form.qtpl:56
 			qw422016.N().S(` <span> / </span> `)
-//line template/namespace_form.qtpl:58
+//line namespace_form.qtpl:58
 		}
-//line template/namespace_form.qtpl:58
+//line namespace_form.qtpl:58
 		qw422016.N().S(` `)
-//line template/namespace_form.qtpl:59
+//line namespace_form.qtpl:59
 	}
-//line template/namespace_form.qtpl:59
+//line namespace_form.qtpl:59
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 }
 
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 func writerenderNamespacePath(qq422016 qtio422016.Writer, username string, parts []string) {
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 	streamrenderNamespacePath(qw422016, username, parts)
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 	qt422016.ReleaseWriter(qw422016)
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 }
 
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 func renderNamespacePath(username string, parts []string) string {
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 	writerenderNamespacePath(qb422016, username, parts)
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 	qs422016 := string(qb422016.B)
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 	return qs422016
-//line template/namespace_form.qtpl:60
+//line namespace_form.qtpl:60
 }
 
-//line template/namespace_form.qtpl:62
+//line namespace_form.qtpl:62
 func streamnamespacePath(qw422016 *qt422016.Writer, username, path string) {
-//line template/namespace_form.qtpl:62
+//line namespace_form.qtpl:62
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:63
+//line namespace_form.qtpl:63
 	streamrenderNamespacePath(qw422016, username, strings.Split(path, "/"))
-//line template/namespace_form.qtpl:63
+//line namespace_form.qtpl:63
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 }
 
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 func writenamespacePath(qq422016 qtio422016.Writer, username, path string) {
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 	streamnamespacePath(qw422016, username, path)
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 	qt422016.ReleaseWriter(qw422016)
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 }
 
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 func namespacePath(username, path string) string {
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 	writenamespacePath(qb422016, username, path)
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 	qs422016 := string(qb422016.B)
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 	return qs422016
-//line template/namespace_form.qtpl:64
+//line namespace_form.qtpl:64
 }
 
-//line template/namespace_form.qtpl:66
+//line namespace_form.qtpl:66
 func (p *NamespaceForm) StreamTitle(qw422016 *qt422016.Writer) {
-//line template/namespace_form.qtpl:66
+//line namespace_form.qtpl:66
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:67
+//line namespace_form.qtpl:67
 	if p.Namespace == nil {
-//line template/namespace_form.qtpl:67
+//line namespace_form.qtpl:67
 		qw422016.N().S(` Create Namespace `)
-//line template/namespace_form.qtpl:69
+//line namespace_form.qtpl:69
 	} else {
-//line template/namespace_form.qtpl:69
+//line namespace_form.qtpl:69
 		qw422016.N().S(` `)
-//line template/namespace_form.qtpl:70
+//line namespace_form.qtpl:70
 		qw422016.E().S(p.Namespace.User.Username)
-//line template/namespace_form.qtpl:70
+//line namespace_form.qtpl:70
 		qw422016.N().S(`/`)
-//line template/namespace_form.qtpl:70
+//line namespace_form.qtpl:70
 		qw422016.E().S(p.Namespace.Name)
-//line template/namespace_form.qtpl:70
+//line namespace_form.qtpl:70
 		qw422016.N().S(` - Edit Namespace `)
-//line template/namespace_form.qtpl:71
+//line namespace_form.qtpl:71
 	}
-//line template/namespace_form.qtpl:71
+//line namespace_form.qtpl:71
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 }
 
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 func (p *NamespaceForm) WriteTitle(qq422016 qtio422016.Writer) {
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 	p.StreamTitle(qw422016)
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 	qt422016.ReleaseWriter(qw422016)
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 }
 
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 func (p *NamespaceForm) Title() string {
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 	p.WriteTitle(qb422016)
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 	qs422016 := string(qb422016.B)
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 	return qs422016
-//line template/namespace_form.qtpl:72
+//line namespace_form.qtpl:72
 }
 
-//line template/namespace_form.qtpl:74
+//line namespace_form.qtpl:74
 func (p *NamespaceForm) StreamHeader(qw422016 *qt422016.Writer) {
-//line template/namespace_form.qtpl:74
+//line namespace_form.qtpl:74
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:75
+//line namespace_form.qtpl:75
 	if p.Namespace != nil {
-//line template/namespace_form.qtpl:75
+//line namespace_form.qtpl:75
 		qw422016.N().S(` <a class="back" href="`)
-//line template/namespace_form.qtpl:76
+//line namespace_form.qtpl:76
 		qw422016.E().S(p.Namespace.Endpoint())
-//line template/namespace_form.qtpl:76
+//line namespace_form.qtpl:76
 		qw422016.N().S(`">`)
-//line template/namespace_form.qtpl:76
+//line namespace_form.qtpl:76
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M20.016 11.016v1.969h-12.188l5.578 5.625-1.406 1.406-8.016-8.016 8.016-8.016 1.406 1.406-5.578 5.625h12.188z"></path>
 </svg>
 `)
-//line template/namespace_form.qtpl:76
+//line namespace_form.qtpl:76
 		qw422016.N().S(`</a> `)
-//line template/namespace_form.qtpl:77
+//line namespace_form.qtpl:77
 		streamnamespacePath(qw422016, p.Namespace.User.Username, p.Namespace.Path)
-//line template/namespace_form.qtpl:77
+//line namespace_form.qtpl:77
 		qw422016.N().S(` - Edit `)
-//line template/namespace_form.qtpl:78
+//line namespace_form.qtpl:78
 	} else {
-//line template/namespace_form.qtpl:78
+//line namespace_form.qtpl:78
 		qw422016.N().S(` `)
-//line template/namespace_form.qtpl:79
+//line namespace_form.qtpl:79
 		if p.Parent != nil {
-//line template/namespace_form.qtpl:79
+//line namespace_form.qtpl:79
 			qw422016.N().S(` <a class="back" href="`)
-//line template/namespace_form.qtpl:80
+//line namespace_form.qtpl:80
 			qw422016.E().S(p.Parent.Endpoint())
-//line template/namespace_form.qtpl:80
+//line namespace_form.qtpl:80
 			qw422016.N().S(`">`)
-//line template/namespace_form.qtpl:80
+//line namespace_form.qtpl:80
 			qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M20.016 11.016v1.969h-12.188l5.578 5.625-1.406 1.406-8.016-8.016 8.016-8.016 1.406 1.406-5.578 5.625h12.188z"></path>
 </svg>
 `)
-//line template/namespace_form.qtpl:80
+//line namespace_form.qtpl:80
 			qw422016.N().S(`</a> `)
-//line template/namespace_form.qtpl:81
+//line namespace_form.qtpl:81
 			streamnamespacePath(qw422016, p.Parent.User.Username, p.Parent.Path)
-//line template/namespace_form.qtpl:81
+//line namespace_form.qtpl:81
 			qw422016.N().S(` - Create Sub-namespace `)
-//line template/namespace_form.qtpl:82
+//line namespace_form.qtpl:82
 		} else {
-//line template/namespace_form.qtpl:82
+//line namespace_form.qtpl:82
 			qw422016.N().S(` <a class="back" href="/namespaces">`)
-//line template/namespace_form.qtpl:83
+//line namespace_form.qtpl:83
 			qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M20.016 11.016v1.969h-12.188l5.578 5.625-1.406 1.406-8.016-8.016 8.016-8.016 1.406 1.406-5.578 5.625h12.188z"></path>
 </svg>
 `)
-//line template/namespace_form.qtpl:83
+//line namespace_form.qtpl:83
 			qw422016.N().S(`</a> Create Namespace `)
-//line template/namespace_form.qtpl:84
+//line namespace_form.qtpl:84
 		}
-//line template/namespace_form.qtpl:84
+//line namespace_form.qtpl:84
 		qw422016.N().S(` `)
-//line template/namespace_form.qtpl:85
+//line namespace_form.qtpl:85
 	}
-//line template/namespace_form.qtpl:85
+//line namespace_form.qtpl:85
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 }
 
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 func (p *NamespaceForm) WriteHeader(qq422016 qtio422016.Writer) {
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 	p.StreamHeader(qw422016)
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 	qt422016.ReleaseWriter(qw422016)
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 }
 
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 func (p *NamespaceForm) Header() string {
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 	p.WriteHeader(qb422016)
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 	qs422016 := string(qb422016.B)
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 	return qs422016
-//line template/namespace_form.qtpl:86
+//line namespace_form.qtpl:86
 }
 
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 func (p *NamespaceForm) StreamActions(qw422016 *qt422016.Writer) {
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 }
 
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 func (p *NamespaceForm) WriteActions(qq422016 qtio422016.Writer) {
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 	p.StreamActions(qw422016)
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 	qt422016.ReleaseWriter(qw422016)
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 }
 
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 func (p *NamespaceForm) Actions() string {
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 	p.WriteActions(qb422016)
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 	qs422016 := string(qb422016.B)
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 	return qs422016
-//line template/namespace_form.qtpl:88
+//line namespace_form.qtpl:88
 }
 
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 func (p *NamespaceForm) StreamNavigation(qw422016 *qt422016.Writer) {
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 }
 
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 func (p *NamespaceForm) WriteNavigation(qq422016 qtio422016.Writer) {
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 	p.StreamNavigation(qw422016)
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 	qt422016.ReleaseWriter(qw422016)
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 }
 
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 func (p *NamespaceForm) Navigation() string {
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 	p.WriteNavigation(qb422016)
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 	qs422016 := string(qb422016.B)
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 	return qs422016
-//line template/namespace_form.qtpl:89
+//line namespace_form.qtpl:89
 }
 
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 func (p *NamespaceForm) StreamFooter(qw422016 *qt422016.Writer) {
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 }
 
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 func (p *NamespaceForm) WriteFooter(qq422016 qtio422016.Writer) {
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 	p.StreamFooter(qw422016)
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 	qt422016.ReleaseWriter(qw422016)
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 }
 
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 func (p *NamespaceForm) Footer() string {
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 	p.WriteFooter(qb422016)
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 	qs422016 := string(qb422016.B)
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 	return qs422016
-//line template/namespace_form.qtpl:90
+//line namespace_form.qtpl:90
 }
 
-//line template/namespace_form.qtpl:92
+//line namespace_form.qtpl:92
 func (p *NamespaceForm) streamvisibilityField(qw422016 *qt422016.Writer, iconName string, v namespace.Visibility, fld form.Field) {
-//line template/namespace_form.qtpl:92
+//line namespace_form.qtpl:92
 	qw422016.N().S(` <label class="form-option"> <input `)
-//line template/namespace_form.qtpl:94
+//line namespace_form.qtpl:94
 	qw422016.E().S(p.checked(v))
-//line template/namespace_form.qtpl:94
+//line namespace_form.qtpl:94
 	qw422016.N().S(` class="form-selector" `)
-//line template/namespace_form.qtpl:94
+//line namespace_form.qtpl:94
 	qw422016.E().S(p.disabled(v))
-//line template/namespace_form.qtpl:94
+//line namespace_form.qtpl:94
 	qw422016.N().S(` name="visibility" type="radio" value="`)
-//line template/namespace_form.qtpl:94
+//line namespace_form.qtpl:94
 	qw422016.E().S(v.String())
-//line template/namespace_form.qtpl:94
+//line namespace_form.qtpl:94
 	qw422016.N().S(`"/> `)
-//line template/namespace_form.qtpl:95
+//line namespace_form.qtpl:95
 	qw422016.N().V(icon("static/svg/" + iconName))
-//line template/namespace_form.qtpl:95
+//line namespace_form.qtpl:95
 	qw422016.N().S(` <div class="form-option-info"> <strong>`)
-//line template/namespace_form.qtpl:97
+//line namespace_form.qtpl:97
 	qw422016.E().S(fld.Name)
-//line template/namespace_form.qtpl:97
+//line namespace_form.qtpl:97
 	qw422016.N().S(`</strong> <div class="form-desc">`)
-//line template/namespace_form.qtpl:98
+//line namespace_form.qtpl:98
 	qw422016.E().S(fld.Desc)
-//line template/namespace_form.qtpl:98
+//line namespace_form.qtpl:98
 	qw422016.N().S(`</div> </div> </label> `)
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 }
 
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 func (p *NamespaceForm) writevisibilityField(qq422016 qtio422016.Writer, iconName string, v namespace.Visibility, fld form.Field) {
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 	p.streamvisibilityField(qw422016, iconName, v, fld)
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 	qt422016.ReleaseWriter(qw422016)
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 }
 
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 func (p *NamespaceForm) visibilityField(iconName string, v namespace.Visibility, fld form.Field) string {
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 	p.writevisibilityField(qb422016, iconName, v, fld)
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 	qs422016 := string(qb422016.B)
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 	return qs422016
-//line template/namespace_form.qtpl:101
+//line namespace_form.qtpl:101
 }
 
-//line template/namespace_form.qtpl:103
+//line namespace_form.qtpl:103
 func (p *NamespaceForm) StreamBody(qw422016 *qt422016.Writer) {
-//line template/namespace_form.qtpl:103
+//line namespace_form.qtpl:103
 	qw422016.N().S(` <div class="panel"> <div class="panel-body slim"> <form action="`)
-//line template/namespace_form.qtpl:106
+//line namespace_form.qtpl:106
 	qw422016.E().S(p.action())
-//line template/namespace_form.qtpl:106
+//line namespace_form.qtpl:106
 	qw422016.N().S(`" method="POST"> `)
-//line template/namespace_form.qtpl:107
+//line namespace_form.qtpl:107
 	if p.Namespace != nil {
-//line template/namespace_form.qtpl:107
+//line namespace_form.qtpl:107
 		qw422016.N().S(` `)
-//line template/namespace_form.qtpl:108
+//line namespace_form.qtpl:108
 		form.StreamMethod(qw422016, "PATCH")
-//line template/namespace_form.qtpl:108
+//line namespace_form.qtpl:108
 		qw422016.N().S(` `)
-//line template/namespace_form.qtpl:109
+//line namespace_form.qtpl:109
 	}
-//line template/namespace_form.qtpl:109
+//line namespace_form.qtpl:109
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:110
+//line namespace_form.qtpl:110
 	qw422016.N().V(p.CSRF)
-//line template/namespace_form.qtpl:110
+//line namespace_form.qtpl:110
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:111
+//line namespace_form.qtpl:111
 	if p.Parent != nil {
-//line template/namespace_form.qtpl:111
+//line namespace_form.qtpl:111
 		qw422016.N().S(` <input name="parent" type="hidden" value="`)
-//line template/namespace_form.qtpl:112
+//line namespace_form.qtpl:112
 		qw422016.E().S(p.Parent.Path)
-//line template/namespace_form.qtpl:112
+//line namespace_form.qtpl:112
 		qw422016.N().S(`"/> `)
-//line template/namespace_form.qtpl:113
+//line namespace_form.qtpl:113
 	}
-//line template/namespace_form.qtpl:113
+//line namespace_form.qtpl:113
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:114
+//line namespace_form.qtpl:114
 	if p.Namespace == nil {
-//line template/namespace_form.qtpl:114
+//line namespace_form.qtpl:114
 		qw422016.N().S(` `)
-//line template/namespace_form.qtpl:115
+//line namespace_form.qtpl:115
 		p.StreamField(qw422016, form.Field{
 			ID:   "name",
 			Name: "Name",
 			Type: form.Text,
 		})
-//line template/namespace_form.qtpl:119
+//line namespace_form.qtpl:119
 		qw422016.N().S(` `)
-//line template/namespace_form.qtpl:120
+//line namespace_form.qtpl:120
 	}
-//line template/namespace_form.qtpl:120
+//line namespace_form.qtpl:120
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:121
+//line namespace_form.qtpl:121
 	p.StreamField(qw422016, form.Field{
 		ID:       "description",
 		Name:     "Description",
 		Type:     form.Text,
 		Optional: true,
 	})
-//line template/namespace_form.qtpl:126
+//line namespace_form.qtpl:126
+	qw422016.N().S(` `)
+//line namespace_form.qtpl:127
+	p.StreamField(qw422016, form.Field{
+		ID:       "defaults",
+		Name:     "Defaults",
+		Type:     form.Textarea,
+		Optional: true,
+		Desc:     "YAML of the driver, env, allow_failures, objects, and before commands merged into every build manifest submitted to the namespace.",
+	})
+//line namespace_form.qtpl:133
 	qw422016.N().S(` <div class="form-field"> `)
-//line template/namespace_form.qtpl:128
+//line namespace_form.qtpl:135
 	p.streamvisibilityField(qw422016, "lock.svg", namespace.Private, form.Field{
 		Name: "Private",
 		Desc: "You choose who can view the namespace",
 	})
-//line template/namespace_form.qtpl:131
+//line namespace_form.qtpl:138
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:132
+//line namespace_form.qtpl:139
 	p.streamvisibilityField(qw422016, "security.svg", namespace.Internal, form.Field{
 		Name: "Internal",
 		Desc: "Anyone with an account can view the namespace",
 	})
-//line template/namespace_form.qtpl:135
+//line namespace_form.qtpl:142
 	qw422016.N().S(` `)
-//line template/namespace_form.qtpl:136
+//line namespace_form.qtpl:143
 	p.streamvisibilityField(qw422016, "public.svg", namespace.Public, form.Field{
 		Name: "Public",
 		Desc: "Anyone can view the namespace",
 	})
-//line template/namespace_form.qtpl:139
+//line namespace_form.qtpl:146
 	qw422016.N().S(` </div> <div class="form-field"> `)
-//line template/namespace_form.qtpl:142
+//line namespace_form.qtpl:149
 	if p.Namespace != nil {
-//line template/namespace_form.qtpl:142
+//line namespace_form.qtpl:149
 		qw422016.N().S(` <button type="submit" class="btn btn-primary">Save</button> `)
-//line template/namespace_form.qtpl:144
+//line namespace_form.qtpl:151
 	} else {
-//line template/namespace_form.qtpl:144
+//line namespace_form.qtpl:151
 		qw422016.N().S(` <button type="submit" class="btn btn-primary">Create</button> `)
-//line template/namespace_form.qtpl:146
+//line namespace_form.qtpl:153
 	}
-//line template/namespace_form.qtpl:146
+//line namespace_form.qtpl:153
 	qw422016.N().S(` </div> </form> `)
-//line template/namespace_form.qtpl:149
+//line namespace_form.qtpl:156
 	if p.Namespace != nil {
-//line template/namespace_form.qtpl:149
+//line namespace_form.qtpl:156
 		qw422016.N().S(` <div class="separator"></div> <form action="`)
-//line template/namespace_form.qtpl:151
+//line namespace_form.qtpl:158
 		qw422016.E().S(p.Namespace.Endpoint())
-//line template/namespace_form.qtpl:151
+//line namespace_form.qtpl:158
 		qw422016.N().S(`" method="POST"> `)
-//line template/namespace_form.qtpl:152
+//line namespace_form.qtpl:159
 		form.StreamMethod(qw422016, "DELETE")
-//line template/namespace_form.qtpl:152
+//line namespace_form.qtpl:159
 		qw422016.N().S(` `)
-//line template/namespace_form.qtpl:153
+//line namespace_form.qtpl:160
 		qw422016.N().V(p.CSRF)
-//line template/namespace_form.qtpl:153
+//line namespace_form.qtpl:160
 		qw422016.N().S(` <div class="overflow"> <div class="right"> <button type="submit" class="btn btn-danger">Delete</button> </div> <strong>Delete Namespace</strong> <br/><p>Builds within the namespace will not be deleted.</p> </div> </form> `)
-//line template/namespace_form.qtpl:162
+//line namespace_form.qtpl:169
 	}
-//line template/namespace_form.qtpl:162
+//line namespace_form.qtpl:169
 	qw422016.N().S(` </div> </div> `)
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 }
 
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 func (p *NamespaceForm) WriteBody(qq422016 qtio422016.Writer) {
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 	p.StreamBody(qw422016)
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 	qt422016.ReleaseWriter(qw422016)
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 }
 
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 func (p *NamespaceForm) Body() string {
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 	p.WriteBody(qb422016)
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 	qs422016 := string(qb422016.B)
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 	return qs422016
-//line template/namespace_form.qtpl:165
+//line namespace_form.qtpl:172
 }
@@ -1,10 +1,10 @@
 // Code generated by qtc from "template.qtpl". DO NOT EDIT.
 // See https://github.com/valyala/quicktemplate for details.
 
-//line template/template.qtpl:2
+//line template.qtpl:2
 package template
 
-//line template/template.qtpl:2
+//line template.qtpl:2
 import (
 	"bytes"
 	"encoding/json"
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"djinn-ci.com/auth"
 	"djinn-ci.com/errors"
@@ -21,43 +22,43 @@ import (
 	"github.com/gorilla/csrf"
 )
 
-//line template/template.qtpl:20
+//line template.qtpl:21
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line template/template.qtpl:20
+//line template.qtpl:21
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line template/template.qtpl:20
+//line template.qtpl:21
 type Template interface {
-//line template/template.qtpl:20
+//line template.qtpl:21
 	Title() string
-//line template/template.qtpl:20
+//line template.qtpl:21
 	StreamTitle(qw422016 *qt422016.Writer)
-//line template/template.qtpl:20
+//line template.qtpl:21
 	WriteTitle(qq422016 qtio422016.Writer)
-//line template/template.qtpl:20
+//line template.qtpl:21
 	Body() string
-//line template/template.qtpl:20
+//line template.qtpl:21
 	StreamBody(qw422016 *qt422016.Writer)
-//line template/template.qtpl:20
+//line template.qtpl:21
 	WriteBody(qq422016 qtio422016.Writer)
-//line template/template.qtpl:20
+//line template.qtpl:21
 	Footer() string
-//line template/template.qtpl:20
+//line template.qtpl:21
 	StreamFooter(qw422016 *qt422016.Writer)
-//line template/template.qtpl:20
+//line template.qtpl:21
 	WriteFooter(qq422016 qtio422016.Writer)
-//line template/template.qtpl:20
+//line template.qtpl:21
 }
 
-//line template/template.qtpl:30
+//line template.qtpl:31
 type Page struct {
 	User *auth.User
 	CSRF template.HTML
@@ -119,563 +120,879 @@ type FatalError struct {
 	Stack string
 }
 
-//line template/template.qtpl:93
-func StreamCode(qw422016 *qt422016.Writer, code string) {
-//line template/template.qtpl:93
-	qw422016.N().S(` <div class="code-wrap"> <table class="code"> <tbody> `)
-//line template/template.qtpl:97
-	for i, line := range strings.Split(code, "\n") {
-//line template/template.qtpl:97
+// codeSection is a section of code that is the output of a command, if result
+// is not nil, offset is the number of lines that precede the section.
+type codeSection struct {
+	index  int
+	result *runner.CommandResult
+	offset int
+	lines  []string
+}
+
+// splitSections splits the given code into the sections of output of each of
+// the given results. Lines that are not the output of a command are given
+// their own sections.
+func splitSections(code string, results []runner.CommandResult) []codeSection {
+	lines := strings.Split(code, "\n")
+	secs := make([]codeSection, 0, len(results)*2+1)
+
+	clamp := func(n int) int {
+		if n < 0 {
+			return 0
+		}
+		if n > len(lines) {
+			return len(lines)
+		}
+		return n
+	}
+
+	pos := 0
+
+	for i := range results {
+		res := &results[i]
+
+		start := clamp(res.Line)
+		end := clamp(res.Line + res.Lines)
+
+		if start < pos {
+			start = pos
+		}
+		if end < start {
+			end = start
+		}
+
+		if start > pos {
+			secs = append(secs, codeSection{offset: pos, lines: lines[pos:start]})
+		}
+
+		secs = append(secs, codeSection{
+			index:  i,
+			result: res,
+			offset: start,
+			lines:  lines[start:end],
+		})
+		pos = end
+	}
+
+	if pos < len(lines) {
+		secs = append(secs, codeSection{offset: pos, lines: lines[pos:]})
+	}
+	return secs
+}
+
+//line template.qtpl:154
+func streamcodeLines(qw422016 *qt422016.Writer, lines []string, offset int) {
+//line template.qtpl:154
+	qw422016.N().S(` `)
+//line template.qtpl:155
+	for i, line := range lines {
+//line template.qtpl:155
+		qw422016.N().S(` `)
+//line template.qtpl:156
+		n := offset + i + 1
+
+//line template.qtpl:156
 		qw422016.N().S(` <tr> <td class="line-number"><a href="#L`)
-//line template/template.qtpl:99
-		qw422016.E().V(i + 1)
-//line template/template.qtpl:99
+//line template.qtpl:158
+		qw422016.E().V(n)
+//line template.qtpl:158
 		qw422016.N().S(`">`)
-//line template/template.qtpl:99
-		qw422016.E().V(i + 1)
-//line template/template.qtpl:99
+//line template.qtpl:158
+		qw422016.E().V(n)
+//line template.qtpl:158
 		qw422016.N().S(`</a></td> <td class="line" id="L`)
-//line template/template.qtpl:100
-		qw422016.E().V(i + 1)
-//line template/template.qtpl:100
+//line template.qtpl:159
+		qw422016.E().V(n)
+//line template.qtpl:159
 		qw422016.N().S(`">`)
-//line template/template.qtpl:100
+//line template.qtpl:159
 		qw422016.E().S(line)
-//line template/template.qtpl:100
+//line template.qtpl:159
 		qw422016.N().S(`</td> </tr> `)
-//line template/template.qtpl:102
+//line template.qtpl:161
 	}
-//line template/template.qtpl:102
+//line template.qtpl:161
+	qw422016.N().S(` `)
+//line template.qtpl:162
+}
+
+//line template.qtpl:162
+func writecodeLines(qq422016 qtio422016.Writer, lines []string, offset int) {
+//line template.qtpl:162
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line template.qtpl:162
+	streamcodeLines(qw422016, lines, offset)
+//line template.qtpl:162
+	qt422016.ReleaseWriter(qw422016)
+//line template.qtpl:162
+}
+
+//line template.qtpl:162
+func codeLines(lines []string, offset int) string {
+//line template.qtpl:162
+	qb422016 := qt422016.AcquireByteBuffer()
+//line template.qtpl:162
+	writecodeLines(qb422016, lines, offset)
+//line template.qtpl:162
+	qs422016 := string(qb422016.B)
+//line template.qtpl:162
+	qt422016.ReleaseByteBuffer(qb422016)
+//line template.qtpl:162
+	return qs422016
+//line template.qtpl:162
+}
+
+//line template.qtpl:164
+func StreamCode(qw422016 *qt422016.Writer, code string) {
+//line template.qtpl:164
+	qw422016.N().S(` <div class="code-wrap"> <table class="code"> <tbody> `)
+//line template.qtpl:168
+	streamcodeLines(qw422016, strings.Split(code, "\n"), 0)
+//line template.qtpl:168
 	qw422016.N().S(` </tbody> </table> </div> `)
-//line template/template.qtpl:106
+//line template.qtpl:172
 }
 
-//line template/template.qtpl:106
+//line template.qtpl:172
 func WriteCode(qq422016 qtio422016.Writer, code string) {
-//line template/template.qtpl:106
+//line template.qtpl:172
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/template.qtpl:106
+//line template.qtpl:172
 	StreamCode(qw422016, code)
-//line template/template.qtpl:106
+//line template.qtpl:172
 	qt422016.ReleaseWriter(qw422016)
-//line template/template.qtpl:106
+//line template.qtpl:172
 }
 
-//line template/template.qtpl:106
+//line template.qtpl:172
 func Code(code string) string {
-//line template/template.qtpl:106
+//line template.qtpl:172
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/template.qtpl:106
+//line template.qtpl:172
 	WriteCode(qb422016, code)
-//line template/template.qtpl:106
+//line template.qtpl:172
 	qs422016 := string(qb422016.B)
-//line template/template.qtpl:106
+//line template.qtpl:172
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/template.qtpl:106
+//line template.qtpl:172
 	return qs422016
-//line template/template.qtpl:106
+//line template.qtpl:172
 }
 
-//line template/template.qtpl:108
+//line template.qtpl:174
+func streamcommandResult(qw422016 *qt422016.Writer, res *runner.CommandResult) {
+//line template.qtpl:174
+	qw422016.N().S(` `)
+//line template.qtpl:175
+	if res.ExitCode < 0 {
+//line template.qtpl:175
+		qw422016.N().S(` did not finish `)
+//line template.qtpl:177
+	} else {
+//line template.qtpl:177
+		qw422016.N().S(` exit `)
+//line template.qtpl:178
+		qw422016.N().D(res.ExitCode)
+//line template.qtpl:178
+		qw422016.N().S(` `)
+//line template.qtpl:179
+	}
+//line template.qtpl:179
+	qw422016.N().S(` in `)
+//line template.qtpl:180
+	qw422016.E().V(res.Duration.Round(time.Millisecond))
+//line template.qtpl:180
+	qw422016.N().S(` `)
+//line template.qtpl:181
+}
+
+//line template.qtpl:181
+func writecommandResult(qq422016 qtio422016.Writer, res *runner.CommandResult) {
+//line template.qtpl:181
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line template.qtpl:181
+	streamcommandResult(qw422016, res)
+//line template.qtpl:181
+	qt422016.ReleaseWriter(qw422016)
+//line template.qtpl:181
+}
+
+//line template.qtpl:181
+func commandResult(res *runner.CommandResult) string {
+//line template.qtpl:181
+	qb422016 := qt422016.AcquireByteBuffer()
+//line template.qtpl:181
+	writecommandResult(qb422016, res)
+//line template.qtpl:181
+	qs422016 := string(qb422016.B)
+//line template.qtpl:181
+	qt422016.ReleaseByteBuffer(qb422016)
+//line template.qtpl:181
+	return qs422016
+//line template.qtpl:181
+}
+
+//line template.qtpl:183
+func StreamCodeSections(qw422016 *qt422016.Writer, code string, results []runner.CommandResult) {
+//line template.qtpl:183
+	qw422016.N().S(` `)
+//line template.qtpl:184
+	if len(results) == 0 {
+//line template.qtpl:184
+		qw422016.N().S(` `)
+//line template.qtpl:185
+		StreamCode(qw422016, code)
+//line template.qtpl:185
+		qw422016.N().S(` `)
+//line template.qtpl:186
+	} else {
+//line template.qtpl:186
+		qw422016.N().S(` <div class="code-wrap"> `)
+//line template.qtpl:188
+		for _, sec := range splitSections(code, results) {
+//line template.qtpl:188
+			qw422016.N().S(` `)
+//line template.qtpl:189
+			if sec.result == nil {
+//line template.qtpl:189
+				qw422016.N().S(` `)
+//line template.qtpl:190
+				if len(sec.lines) > 0 {
+//line template.qtpl:190
+					qw422016.N().S(` <table class="code"> <tbody>`)
+//line template.qtpl:192
+					streamcodeLines(qw422016, sec.lines, sec.offset)
+//line template.qtpl:192
+					qw422016.N().S(`</tbody> </table> `)
+//line template.qtpl:194
+				}
+//line template.qtpl:194
+				qw422016.N().S(` `)
+//line template.qtpl:195
+			} else {
+//line template.qtpl:195
+				qw422016.N().S(` <details class="code-section`)
+//line template.qtpl:196
+				if sec.result.ExitCode != 0 {
+//line template.qtpl:196
+					qw422016.N().S(` code-section-failed`)
+//line template.qtpl:196
+				}
+//line template.qtpl:196
+				qw422016.N().S(`" id="command-`)
+//line template.qtpl:196
+				qw422016.N().D(sec.index + 1)
+//line template.qtpl:196
+				qw422016.N().S(`"`)
+//line template.qtpl:196
+				if sec.result.ExitCode != 0 {
+//line template.qtpl:196
+					qw422016.N().S(` open`)
+//line template.qtpl:196
+				}
+//line template.qtpl:196
+				qw422016.N().S(`> <summary> <span class="code-command">`)
+//line template.qtpl:198
+				qw422016.E().S(sec.result.Command)
+//line template.qtpl:198
+				qw422016.N().S(`</span> <span class="code-result">`)
+//line template.qtpl:199
+				streamcommandResult(qw422016, sec.result)
+//line template.qtpl:199
+				qw422016.N().S(`</span> </summary> <table class="code"> <tbody>`)
+//line template.qtpl:202
+				streamcodeLines(qw422016, sec.lines, sec.offset)
+//line template.qtpl:202
+				qw422016.N().S(`</tbody> </table> </details> `)
+//line template.qtpl:205
+			}
+//line template.qtpl:205
+			qw422016.N().S(` `)
+//line template.qtpl:206
+		}
+//line template.qtpl:206
+		qw422016.N().S(` </div> `)
+//line template.qtpl:208
+	}
+//line template.qtpl:208
+	qw422016.N().S(` `)
+//line template.qtpl:209
+}
+
+//line template.qtpl:209
+func WriteCodeSections(qq422016 qtio422016.Writer, code string, results []runner.CommandResult) {
+//line template.qtpl:209
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line template.qtpl:209
+	StreamCodeSections(qw422016, code, results)
+//line template.qtpl:209
+	qt422016.ReleaseWriter(qw422016)
+//line template.qtpl:209
+}
+
+//line template.qtpl:209
+func CodeSections(code string, results []runner.CommandResult) string {
+//line template.qtpl:209
+	qb422016 := qt422016.AcquireByteBuffer()
+//line template.qtpl:209
+	WriteCodeSections(qb422016, code, results)
+//line template.qtpl:209
+	qs422016 := string(qb422016.B)
+//line template.qtpl:209
+	qt422016.ReleaseByteBuffer(qb422016)
+//line template.qtpl:209
+	return qs422016
+//line template.qtpl:209
+}
+
+//line template.qtpl:211
 func StreamStatus(qw422016 *qt422016.Writer, s runner.Status) {
-//line template/template.qtpl:108
+//line template.qtpl:211
 	qw422016.N().S(` `)
-//line template/template.qtpl:109
+//line template.qtpl:212
 	switch s {
-//line template/template.qtpl:110
+//line template.qtpl:213
 	case runner.Queued:
-//line template/template.qtpl:110
+//line template.qtpl:213
 		qw422016.N().S(` <span class="pill w-90 pill-dark">`)
-//line template/template.qtpl:111
+//line template.qtpl:214
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12 11.484l3.984-3.984v-3.516h-7.969v3.516zM15.984 16.5l-3.984-3.984-3.984 3.984v3.516h7.969v-3.516zM6 2.016h12v6l-3.984 3.984 3.984 3.984v6h-12v-6l3.984-3.984-3.984-3.984v-6z"></path>
 </svg>
 `)
-//line template/template.qtpl:111
+//line template.qtpl:214
 		qw422016.N().S(` <span>Queued</span></span> `)
-//line template/template.qtpl:112
+//line template.qtpl:215
 	case runner.Running:
-//line template/template.qtpl:112
+//line template.qtpl:215
 		qw422016.N().S(` <span class="pill w-90 pill-blue">`)
-//line template/template.qtpl:113
+//line template.qtpl:216
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M21.984 12c0 5.156-3.938 9.422-8.953 9.938v-2.016c3.938-0.516 6.984-3.891 6.984-7.922s-3.047-7.406-6.984-7.922v-2.016c5.016 0.516 8.953 4.781 8.953 9.938zM5.672 19.734l1.406-1.406c1.125 0.844 2.484 1.406 3.938 1.594v2.016c-2.016-0.188-3.844-0.984-5.344-2.203zM4.078 12.984c0.188 1.453 0.75 2.813 1.594 3.891l-1.406 1.453c-1.219-1.5-2.016-3.328-2.203-5.344h2.016zM5.672 7.078c-0.844 1.125-1.406 2.484-1.594 3.938h-2.016c0.188-2.016 0.984-3.844 2.203-5.344zM11.016 4.078c-1.453 0.188-2.813 0.75-3.938 1.594l-1.406-1.406c1.5-1.219 3.328-2.016 5.344-2.203v2.016zM13.031 9.797l2.953 2.203c-2.007 1.493-4.007 2.993-6 4.5z"></path>
 </svg>
 `)
-//line template/template.qtpl:113
+//line template.qtpl:216
 		qw422016.N().S(` <span>Running</span></span> `)
-//line template/template.qtpl:114
+//line template.qtpl:217
 	case runner.Passed:
-//line template/template.qtpl:114
+//line template.qtpl:217
 		qw422016.N().S(` <span class="pill w-90 pill-green">`)
-//line template/template.qtpl:115
+//line template.qtpl:218
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M9 16.172l10.594-10.594 1.406 1.406-12 12-5.578-5.578 1.406-1.406z"></path>
 </svg>
 `)
-//line template/template.qtpl:115
+//line template.qtpl:218
 		qw422016.N().S(` <span>Passed</span></span> `)
-//line template/template.qtpl:116
+//line template.qtpl:219
 	case runner.PassedWithFailures:
-//line template/template.qtpl:116
+//line template.qtpl:219
 		qw422016.N().S(` <span class="pill w-90 pill-orange">`)
-//line template/template.qtpl:117
+//line template.qtpl:220
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12.984 14.016v-4.031h-1.969v4.031h1.969zM12.984 18v-2.016h-1.969v2.016h1.969zM0.984 21l11.016-18.984 11.016 18.984h-22.031z"></path>
 </svg>
 `)
-//line template/template.qtpl:117
+//line template.qtpl:220
 		qw422016.N().S(` <span>Passed</span></span> `)
-//line template/template.qtpl:118
+//line template.qtpl:221
 	case runner.Failed:
-//line template/template.qtpl:118
+//line template.qtpl:221
 		qw422016.N().S(` <span class="pill w-90 pill-red">`)
-//line template/template.qtpl:119
+//line template.qtpl:222
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M18.984 6.422l-5.578 5.578 5.578 5.578-1.406 1.406-5.578-5.578-5.578 5.578-1.406-1.406 5.578-5.578-5.578-5.578 1.406-1.406 5.578 5.578 5.578-5.578z"></path>
 </svg>
 `)
-//line template/template.qtpl:119
+//line template.qtpl:222
 		qw422016.N().S(` <span>Failed</span></span> `)
-//line template/template.qtpl:120
+//line template.qtpl:223
 	case runner.Killed:
-//line template/template.qtpl:120
+//line template.qtpl:223
 		qw422016.N().S(` <span class="pill w-90 pill-red">`)
-//line template/template.qtpl:121
+//line template.qtpl:224
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12.984 12.984v-6h-1.969v6h1.969zM12 17.297c0.703 0 1.313-0.609 1.313-1.313s-0.609-1.266-1.313-1.266-1.313 0.563-1.313 1.266 0.609 1.313 1.313 1.313zM15.75 3l5.25 5.25v7.5l-5.25 5.25h-7.5l-5.25-5.25v-7.5l5.25-5.25h7.5z"></path>
 </svg>
 `)
-//line template/template.qtpl:121
+//line template.qtpl:224
 		qw422016.N().S(` <span>Killed</span></span> `)
-//line template/template.qtpl:122
+//line template.qtpl:225
 	case runner.TimedOut:
-//line template/template.qtpl:122
+//line template.qtpl:225
 		qw422016.N().S(` <span class="pill w-90 pill-gray">`)
-//line template/template.qtpl:123
+//line template.qtpl:226
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12 20.016c3.891 0 6.984-3.141 6.984-7.031s-3.094-6.984-6.984-6.984-6.984 3.094-6.984 6.984 3.094 7.031 6.984 7.031zM19.031 7.406c1.219 1.547 1.969 3.469 1.969 5.578 0 4.969-4.031 9-9 9s-9-4.031-9-9 4.031-9 9-9c2.109 0 4.078 0.797 5.625 2.016l1.406-1.453c0.516 0.422 0.984 0.891 1.406 1.406zM11.016 14.016v-6h1.969v6h-1.969zM15 0.984v2.016h-6v-2.016h6z"></path>
 </svg>
 `)
-//line template/template.qtpl:123
+//line template.qtpl:226
 		qw422016.N().S(` <span>Timed Out</span></span> `)
-//line template/template.qtpl:124
+//line template.qtpl:227
+	case runner.Skipped:
+//line template.qtpl:227
+		qw422016.N().S(` <span class="pill w-90 pill-gray">`)
+//line template.qtpl:228
+		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
+<svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
+<path d="M12 20.016c4.406 0 8.016-3.609 8.016-8.016 0-1.781-0.656-3.516-1.734-4.922l-11.203 11.203c1.406 1.078 3.141 1.734 4.922 1.734zM3.984 12c0 1.781 0.656 3.516 1.734 4.922l11.203-11.203c-1.406-1.078-3.141-1.734-4.922-1.734-4.406 0-8.016 3.609-8.016 8.016zM12 2.016c5.484 0 9.984 4.5 9.984 9.984s-4.5 9.984-9.984 9.984-9.984-4.5-9.984-9.984 4.5-9.984 9.984-9.984z"></path>
+</svg>
+`)
+//line template.qtpl:228
+		qw422016.N().S(` <span>Skipped</span></span> `)
+//line template.qtpl:229
+	case runner.Waiting:
+//line template.qtpl:229
+		qw422016.N().S(` <span class="pill w-90 pill-orange">`)
+//line template.qtpl:230
+		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
+<svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
+<path d="M15.094 8.016v-2.016c0-1.688-1.406-3.094-3.094-3.094s-3.094 1.406-3.094 3.094v2.016h6.188zM12 17.016c1.078 0 2.016-0.938 2.016-2.016s-0.938-2.016-2.016-2.016-2.016 0.938-2.016 2.016 0.938 2.016 2.016 2.016zM18 8.016c1.078 0 2.016 0.891 2.016 1.969v10.031c0 1.078-0.938 1.969-2.016 1.969h-12c-1.078 0-2.016-0.891-2.016-1.969v-10.031c0-1.078 0.938-1.969 2.016-1.969h0.984v-2.016c0-2.766 2.25-5.016 5.016-5.016s5.016 2.25 5.016 5.016v2.016h0.984z"></path>
+</svg>
+`)
+//line template.qtpl:230
+		qw422016.N().S(` <span>Waiting</span></span> `)
+//line template.qtpl:231
 	}
-//line template/template.qtpl:124
+//line template.qtpl:231
 	qw422016.N().S(` `)
-//line template/template.qtpl:125
+//line template.qtpl:232
 }
 
-//line template/template.qtpl:125
+//line template.qtpl:232
 func WriteStatus(qq422016 qtio422016.Writer, s runner.Status) {
-//line template/template.qtpl:125
+//line template.qtpl:232
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/template.qtpl:125
+//line template.qtpl:232
 	StreamStatus(qw422016, s)
-//line template/template.qtpl:125
+//line template.qtpl:232
 	qt422016.ReleaseWriter(qw422016)
-//line template/template.qtpl:125
+//line template.qtpl:232
 }
 
-//line template/template.qtpl:125
+//line template.qtpl:232
 func Status(s runner.Status) string {
-//line template/template.qtpl:125
+//line template.qtpl:232
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/template.qtpl:125
+//line template.qtpl:232
 	WriteStatus(qb422016, s)
-//line template/template.qtpl:125
+//line template.qtpl:232
 	qs422016 := string(qb422016.B)
-//line template/template.qtpl:125
+//line template.qtpl:232
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/template.qtpl:125
+//line template.qtpl:232
 	return qs422016
-//line template/template.qtpl:125
+//line template.qtpl:232
 }
 
-//line template/template.qtpl:127
+//line template.qtpl:234
 func StreamIconStatus(qw422016 *qt422016.Writer, s runner.Status) {
-//line template/template.qtpl:127
+//line template.qtpl:234
 	qw422016.N().S(` `)
-//line template/template.qtpl:128
+//line template.qtpl:235
 	switch s {
-//line template/template.qtpl:129
+//line template.qtpl:236
 	case runner.Queued:
-//line template/template.qtpl:129
+//line template.qtpl:236
 		qw422016.N().S(` <span class="pill-bubble pill-dark">`)
-//line template/template.qtpl:130
+//line template.qtpl:237
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12 11.484l3.984-3.984v-3.516h-7.969v3.516zM15.984 16.5l-3.984-3.984-3.984 3.984v3.516h7.969v-3.516zM6 2.016h12v6l-3.984 3.984 3.984 3.984v6h-12v-6l3.984-3.984-3.984-3.984v-6z"></path>
 </svg>
 `)
-//line template/template.qtpl:130
+//line template.qtpl:237
 		qw422016.N().S(`</span> `)
-//line template/template.qtpl:131
+//line template.qtpl:238
 	case runner.Running:
-//line template/template.qtpl:131
+//line template.qtpl:238
 		qw422016.N().S(` <span class="pill-bubble pill-blue">`)
-//line template/template.qtpl:132
+//line template.qtpl:239
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M21.984 12c0 5.156-3.938 9.422-8.953 9.938v-2.016c3.938-0.516 6.984-3.891 6.984-7.922s-3.047-7.406-6.984-7.922v-2.016c5.016 0.516 8.953 4.781 8.953 9.938zM5.672 19.734l1.406-1.406c1.125 0.844 2.484 1.406 3.938 1.594v2.016c-2.016-0.188-3.844-0.984-5.344-2.203zM4.078 12.984c0.188 1.453 0.75 2.813 1.594 3.891l-1.406 1.453c-1.219-1.5-2.016-3.328-2.203-5.344h2.016zM5.672 7.078c-0.844 1.125-1.406 2.484-1.594 3.938h-2.016c0.188-2.016 0.984-3.844 2.203-5.344zM11.016 4.078c-1.453 0.188-2.813 0.75-3.938 1.594l-1.406-1.406c1.5-1.219 3.328-2.016 5.344-2.203v2.016zM13.031 9.797l2.953 2.203c-2.007 1.493-4.007 2.993-6 4.5z"></path>
 </svg>
 `)
-//line template/template.qtpl:132
+//line template.qtpl:239
 		qw422016.N().S(`</span> `)
-//line template/template.qtpl:133
+//line template.qtpl:240
 	case runner.Passed:
-//line template/template.qtpl:133
+//line template.qtpl:240
 		qw422016.N().S(` <span class="pill-bubble pill-green">`)
-//line template/template.qtpl:134
+//line template.qtpl:241
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M9 16.172l10.594-10.594 1.406 1.406-12 12-5.578-5.578 1.406-1.406z"></path>
 </svg>
 `)
-//line template/template.qtpl:134
+//line template.qtpl:241
 		qw422016.N().S(`</span> `)
-//line template/template.qtpl:135
+//line template.qtpl:242
 	case runner.PassedWithFailures:
-//line template/template.qtpl:135
+//line template.qtpl:242
 		qw422016.N().S(` <span class="pill-bubble pill-orange">`)
-//line template/template.qtpl:136
+//line template.qtpl:243
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12.984 14.016v-4.031h-1.969v4.031h1.969zM12.984 18v-2.016h-1.969v2.016h1.969zM0.984 21l11.016-18.984 11.016 18.984h-22.031z"></path>
 </svg>
 `)
-//line template/template.qtpl:136
+//line template.qtpl:243
 		qw422016.N().S(`</span> `)
-//line template/template.qtpl:137
+//line template.qtpl:244
 	case runner.Failed:
-//line template/template.qtpl:137
+//line template.qtpl:244
 		qw422016.N().S(` <span class="pill-bubble pill-red">`)
-//line template/template.qtpl:138
+//line template.qtpl:245
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M18.984 6.422l-5.578 5.578 5.578 5.578-1.406 1.406-5.578-5.578-5.578 5.578-1.406-1.406 5.578-5.578-5.578-5.578 1.406-1.406 5.578 5.578 5.578-5.578z"></path>
 </svg>
 `)
-//line template/template.qtpl:138
+//line template.qtpl:245
 		qw422016.N().S(`</span> `)
-//line template/template.qtpl:139
+//line template.qtpl:246
 	case runner.Killed:
-//line template/template.qtpl:139
+//line template.qtpl:246
 		qw422016.N().S(` <span class="pill-bubble pill-red">`)
-//line template/template.qtpl:140
+//line template.qtpl:247
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12.984 12.984v-6h-1.969v6h1.969zM12 17.297c0.703 0 1.313-0.609 1.313-1.313s-0.609-1.266-1.313-1.266-1.313 0.563-1.313 1.266 0.609 1.313 1.313 1.313zM15.75 3l5.25 5.25v7.5l-5.25 5.25h-7.5l-5.25-5.25v-7.5l5.25-5.25h7.5z"></path>
 </svg>
 `)
-//line template/template.qtpl:140
+//line template.qtpl:247
 		qw422016.N().S(`</span> `)
-//line template/template.qtpl:141
+//line template.qtpl:248
 	case runner.TimedOut:
-//line template/template.qtpl:141
+//line template.qtpl:248
 		qw422016.N().S(` <span class="pill-bubble pill-gray">`)
-//line template/template.qtpl:142
+//line template.qtpl:249
 		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
 <svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
 <path d="M12 20.016c3.891 0 6.984-3.141 6.984-7.031s-3.094-6.984-6.984-6.984-6.984 3.094-6.984 6.984 3.094 7.031 6.984 7.031zM19.031 7.406c1.219 1.547 1.969 3.469 1.969 5.578 0 4.969-4.031 9-9 9s-9-4.031-9-9 4.031-9 9-9c2.109 0 4.078 0.797 5.625 2.016l1.406-1.453c0.516 0.422 0.984 0.891 1.406 1.406zM11.016 14.016v-6h1.969v6h-1.969zM15 0.984v2.016h-6v-2.016h6z"></path>
 </svg>
 `)
-//line template/template.qtpl:142
+//line template.qtpl:249
+		qw422016.N().S(`</span> `)
+//line template.qtpl:250
+	case runner.Skipped:
+//line template.qtpl:250
+		qw422016.N().S(` <span class="pill-bubble pill-gray">`)
+//line template.qtpl:251
+		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
+<svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
+<path d="M12 20.016c4.406 0 8.016-3.609 8.016-8.016 0-1.781-0.656-3.516-1.734-4.922l-11.203 11.203c1.406 1.078 3.141 1.734 4.922 1.734zM3.984 12c0 1.781 0.656 3.516 1.734 4.922l11.203-11.203c-1.406-1.078-3.141-1.734-4.922-1.734-4.406 0-8.016 3.609-8.016 8.016zM12 2.016c5.484 0 9.984 4.5 9.984 9.984s-4.5 9.984-9.984 9.984-9.984-4.5-9.984-9.984 4.5-9.984 9.984-9.984z"></path>
+</svg>
+`)
+//line template.qtpl:251
+		qw422016.N().S(`</span> `)
+//line template.qtpl:252
+	case runner.Waiting:
+//line template.qtpl:252
+		qw422016.N().S(` <span class="pill-bubble pill-orange">`)
+//line template.qtpl:253
+		qw422016.N().S(`<!-- Generated by IcoMoon.io -->
+<svg version="1.1" xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24">
+<path d="M15.094 8.016v-2.016c0-1.688-1.406-3.094-3.094-3.094s-3.094 1.406-3.094 3.094v2.016h6.188zM12 17.016c1.078 0 2.016-0.938 2.016-2.016s-0.938-2.016-2.016-2.016-2.016 0.938-2.016 2.016 0.938 2.016 2.016 2.016zM18 8.016c1.078 0 2.016 0.891 2.016 1.969v10.031c0 1.078-0.938 1.969-2.016 1.969h-12c-1.078 0-2.016-0.891-2.016-1.969v-10.031c0-1.078 0.938-1.969 2.016-1.969h0.984v-2.016c0-2.766 2.25-5.016 5.016-5.016s5.016 2.25 5.016 5.016v2.016h0.984z"></path>
+</svg>
+`)
+//line template.qtpl:253
 		qw422016.N().S(`</span> `)
-//line template/template.qtpl:143
+//line template.qtpl:254
 	}
-//line template/template.qtpl:143
+//line template.qtpl:254
 	qw422016.N().S(` `)
-//line template/template.qtpl:144
+//line template.qtpl:255
 }
 
-//line template/template.qtpl:144
+//line template.qtpl:255
 func WriteIconStatus(qq422016 qtio422016.Writer, s runner.Status) {
-//line template/template.qtpl:144
+//line template.qtpl:255
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/template.qtpl:144
+//line template.qtpl:255
 	StreamIconStatus(qw422016, s)
-//line template/template.qtpl:144
+//line template.qtpl:255
 	qt422016.ReleaseWriter(qw422016)
-//line template/template.qtpl:144
+//line template.qtpl:255
 }
 
-//line template/template.qtpl:144
+//line template.qtpl:255
 func IconStatus(s runner.Status) string {
-//line template/template.qtpl:144
+//line template.qtpl:255
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/template.qtpl:144
+//line template.qtpl:255
 	WriteIconStatus(qb422016, s)
-//line template/template.qtpl:144
+//line template.qtpl:255
 	qs422016 := string(qb422016.B)
-//line template/template.qtpl:144
+//line template.qtpl:255
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/template.qtpl:144
+//line template.qtpl:255
 	return qs422016
-//line template/template.qtpl:144
+//line template.qtpl:255
 }
 
-//line template/template.qtpl:146
+//line template.qtpl:257
 func StreamLogo(qw422016 *qt422016.Writer) {
-//line template/template.qtpl:146
+//line template.qtpl:257
 	qw422016.N().S(` <div class="logo"> <div class="handle"></div> <div class="lid"></div> <div class="lantern"></div> </div> `)
-//line template/template.qtpl:152
+//line template.qtpl:263
 }
 
-//line template/template.qtpl:152
+//line template.qtpl:263
 func WriteLogo(qq422016 qtio422016.Writer) {
-//line template/template.qtpl:152
+//line template.qtpl:263
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/template.qtpl:152
+//line template.qtpl:263
 	StreamLogo(qw422016)
-//line template/template.qtpl:152
+//line template.qtpl:263
 	qt422016.ReleaseWriter(qw422016)
-//line template/template.qtpl:152
+//line template.qtpl:263
 }
 
-//line template/template.qtpl:152
+//line template.qtpl:263
 func Logo() string {
-//line template/template.qtpl:152
+//line template.qtpl:263
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/template.qtpl:152
+//line template.qtpl:263
 	WriteLogo(qb422016)
-//line template/template.qtpl:152
+//line template.qtpl:263
 	qs422016 := string(qb422016.B)
-//line template/template.qtpl:152
+//line template.qtpl:263
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/template.qtpl:152
+//line template.qtpl:263
 	return qs422016
-//line template/template.qtpl:152
+//line template.qtpl:263
 }
 
-//line template/template.qtpl:154
+//line template.qtpl:265
 func StreamRender(qw422016 *qt422016.Writer, tmpl Template) {
-//line template/template.qtpl:154
+//line template.qtpl:265
 	qw422016.N().S(` <!DOCTYPE HTML> <html lang="en"> <head> <meta charset="utf-8"> <meta content="width=device-width, initial-scale=1" name="viewport"> <title>`)
-//line template/template.qtpl:160
+//line template.qtpl:271
 	tmpl.StreamTitle(qw422016)
-//line template/template.qtpl:160
+//line template.qtpl:271
 	qw422016.N().S(` - Djinn CI</title> <style type="text/css">`)
-//line template/template.qtpl:161
-	qw422016.N().S(`*{margin:0;padding:0}body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif,"Apple Color Emoji","Segoe UI Emoji","Sego UI Symbol";font-size:14px;background:#eee;color:#444}a{color:#146de0;cursor:pointer;text-decoration:none}a:hover{text-decoration:underline}button{cursor:pointer}h1,h2,h3,h4,h5,h6{font-weight:400}.btn{border:none;border-radius:3px;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif,"Apple Color Emoji","Segoe UI Emoji","Sego UI Symbol";padding:10px;padding-left:15px;padding-right:15px;color:#fff}.btn svg{fill:#fff}.btn:hover{text-decoration:none}.btn:disabled{cursor:not-allowed;background:#b2b2b2!important}.btn-primary{background:#61a0ea}.btn-primary:hover{background:#5090d9}.btn-danger{background:#de4141}.btn-danger:hover{background:#cd3030}span.code{padding:3px;border-radius:3px;background:#e6f0f5;font-family:monospace;white-space:pre-wrap}pre.code{background:#272b39;border-radius:0 0 3px 3px;box-sizing:border-box;color:#fff;font-family:monospace;font-size:12px;overflow:auto;padding:15px;width:100%}td.code{border-radius:0 3px 3px 0;text-align:right;width:50%}.code-wrap{overflow:scroll}table.code{background:#272b39;color:#fff;width:100%;font-family:monospace;font-size:12px;border-collapse:collapse;border-spacing:0}table.code .line-number{text-align:right;-moz-user-select:none;-ms-user-select:none;-webkit-user-select:none;min-width:30px;width:1%;padding-left:10px;padding-right:10px;line-height:20px}table.code .line-number a{display:block;color:rgba(255,255,255,.3)}table.code .line{padding-left:10px;padding-right:10px;line-height:20px;white-space:pre;word-wrap:normal}table.code .line:target{background:#383e51}.col-75{width:75%;box-sizing:border-box}.col-25{width:25%;box-sizing:border-box}.col-50{width:50%;box-sizing:border-box}.col-left{float:left;padding-right:5px}.col-right{float:right;padding-left:5px}@media (max-width:1100px){.col-75{margin-bottom:10px;width:100%}.col-25{margin-bottom:10px;width:100%}.col-50{margin-bottom:10px;width:100%}.col-left{padding-right:0;float:none}.col-right{padding-left:0;float:none}}.dashboard .sidebar{position:fixed;top:0;left:0;height:100%;width:225px;background:#383e51;overflow:auto}.dashboard .sidebar .sidebar-header{color:#fff;padding:20px;background:#272b39}.dashboard .sidebar .sidebar-header .logo{margin-top:-5px;margin-right:30px;display:inline-block;vertical-align:middle;width:0}.dashboard .sidebar .sidebar-header .logo .handle{margin-left:-3px;border-style:solid;border-width:2px 0 8px 7px;border-color:transparent transparent transparent #fff}.dashboard .sidebar .sidebar-header .logo .lid{margin-bottom:-20px;margin-left:13px;border-style:solid;border-width:5px 0 7px 5px;border-color:transparent transparent transparent #fff}.dashboard .sidebar .sidebar-header .logo .lantern{margin-left:-5px;border-style:solid;border-width:15px 15px 35px 0;border-color:transparent #fff transparent transparent}.dashboard .sidebar .sidebar-header h2{display:inline-block}.dashboard .sidebar .sidebar-auth a{display:block;color:rgba(255,255,255,.5);padding:15px;text-align:center}.dashboard .sidebar .sidebar-auth a.active,.dashboard .sidebar .sidebar-auth a:hover,.dashboard .sidebar .sidebar-auth button:hover{text-decoration:none;background:#272b39;color:#fff}.dashboard .sidebar .sidebar-nav{list-style:none}.dashboard .sidebar .sidebar-nav li{display:block}.dashboard .sidebar .sidebar-nav li a,.dashboard .sidebar .sidebar-nav li button{display:block;color:rgba(255,255,255,.5);padding:15px}.dashboard .sidebar .sidebar-nav li a svg,.dashboard .sidebar .sidebar-nav li button svg{margin-right:3px;display:inline-block;vertical-align:middle;fill:rgba(255,255,255,.5);width:15px}.dashboard .sidebar .sidebar-nav li a span,.dashboard .sidebar .sidebar-nav li button span{margin-top:2px;display:inline-block;vertical-align:middle}.dashboard .sidebar .sidebar-nav li button{width:100%;border:none;text-align:left;background:rgba(0,0,0,0)}.dashboard .sidebar .sidebar-nav li a.active,.dashboard .sidebar .sidebar-nav li a:hover,.dashboard .sidebar .sidebar-nav li button:hover{text-decoration:none;background:#272b39;color:#fff}.dashboard .sidebar .sidebar-nav li a.active svg,.dashboard .sidebar .sidebar-nav li a:hover svg,.dashboard .sidebar .sidebar-nav li button:hover svg{fill:#fff}.dashboard .sidebar .sidebar-nav li.sidebar-nav-header{padding:15px;font-weight:700;color:#fff}.dashboard-header{margin-bottom:10px}.dashboard-header h1{float:left}.dashboard-header h1 .back{margin-top:2px;display:inline-block;vertical-align:middle}.dashboard-header h1 .back svg{fill:#7f7f7f}.dashboard-header h1 .back:hover{text-decoration:none}.dashboard-header h1 .back:hover svg{fill:#444}.dashboard-header h1 small{margin-top:10px;display:block;font-size:16px;color:rgba(0,0,0,.5)}.dashboard-header .pill{margin-top:-5px;margin-left:10px}.dashboard-header .dashboard-actions{float:right;list-style:none}.dashboard-header .dashboard-actions li{display:inline}.dashboard-header .dashboard-actions li form{display:inline-block}.dashboard-header .dashboard-actions li a{cursor:pointer;display:inline-block}.dashboard-nav{list-style:none}.dashboard-nav li{display:inline}.dashboard-nav li a{display:inline-block;padding:15px;color:#9f9f9f}.dashboard-nav li a svg{margin-right:3px;width:20px;vertical-align:middle;display:inline-block;fill:#9f9f9f}.dashboard-nav li a span{margin-top:2px;display:inline-block;vertical-align:middle}.dashboard-nav li a.active,.dashboard-nav li a:hover{text-decoration:none;color:#272b39}.dashboard-nav li a.active svg,.dashboard-nav li a:hover svg{fill:#272b39}.dashboard-content{margin-left:225px}.dashboard-content .alert{overflow:auto;padding:15px}.dashboard-content .alert .alert-message{float:left;color:rgba(0,0,0,.6)}.dashboard-content .alert a.alert-close{float:right;display:inline-block}.dashboard-content .alert a.alert-close svg{width:15px;height:15px;fill:rgba(0,0,0,.4)}.dashboard-content .alert a.alert-close:hover svg{fill:rgba(0,0,0,.5)}.dashboard-content .alert-success{background:#caf5ca;border:solid 1px #a0dfa0}.dashboard-content .alert-warn{background:#fff3cd;border:solid 1px #d9c995}.dashboard-content .alert-danger{background:#ffd4d4;border:solid 1px #e19e9e}.dashboard-content .dashboard-wrap{margin:0 auto;max-width:1300px;padding:20px}@media (max-width:1500px){.dashboard .sidebar{width:70px}.dashboard .sidebar .sidebar-header{padding:15px}.dashboard .sidebar .sidebar-header .logo{margin-top:0;margin-right:0;margin-left:12px}.dashboard .sidebar .sidebar-header h2{display:none}.dashboard .sidebar .sidebar-nav .sidebar-nav-header{display:none}.dashboard .sidebar .sidebar-nav li a,.dashboard .sidebar .sidebar-nav li button{text-align:center}.dashboard .sidebar .sidebar-nav li a span,.dashboard .sidebar .sidebar-nav li button span{display:none}.dashboard .dashboard-content{margin-left:70px}}@media (max-width:1000px){.dashboard .dashboard-content .dashboard-header .dashboard-nav li a span{display:none}}.form-field+.form-field{margin-top:15px}.form-field{overflow:auto}.form-field .label{margin-bottom:5px;display:block;font-weight:700}.form-field .label small{color:rgba(0,0,0,.5)}.form-field .form-error{margin-top:5px;color:#ff4343;min-height:20px}.form-field .form-text{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif,"Apple Color Emoji","Segoe UI Emoji","Sego UI Symbol";font-size:14px;padding:10px;outline:0;border-radius:3px;box-sizing:border-box;width:100%;border:solid 1px #e4e4e4}.form-field .form-text:focus{border:solid 1px #c2c2c2}.form-field .form-code{min-height:250px;font-family:monospace}.form-field textarea.form-text{min-width:100%;max-width:100%}.form-field .form-option+.form-option{margin-top:10px}.form-field .form-option{display:block;cursor:pointer;overflow:auto}.form-field .form-option .form-selector{margin-right:5px;outline:0}.form-field .form-option .form-option-info{margin-right:5px;display:inline-block}.form-field .form-option svg{margin-right:5px;fill:rgba(0,0,0,.4)}.form-field .hook-event{cursor:pointer;display:inline-block;width:250px;padding:10px 0 10px 0}.form-field .disabled{color:#aaa;cursor:not-allowed}.form-field .disabled svg{fill:#aaa}.form-search{float:right;padding:7px}.form-search .form-text{width:auto}.form-search a svg{margin-top:-3px;fill:#e4e4e4;width:20px;vertical-align:middle;display:inline-block}.form-search a:hover svg{fill:#c2c2c2}.form-field-inline .form-text{display:inline-block;width:auto}.form-field-inline .form-error{display:inline-block}form h2{margin-bottom:15px}.panel+.panel{margin-top:15px}.panel{background:#fff;border-radius:3px;box-shadow:0 2px 4px 0 rgba(0,0,0,.1)}.panel .panel-body{padding:15px}.panel .panel-message{font-size:20px;padding:150px;text-align:center}.panel .panel-footer{border-top:solid 1px #e4e4e4;padding:15px}.panel table.code{border-radius:0 0 3px 3px}.panel-header{border-bottom:solid 1px #e4e4e4;overflow:auto}.panel-header h3{float:left;padding:15px;font-weight:700}.panel-header .panel-nav{list-style:none;float:left}.panel-header .panel-nav li{display:inline}.panel-header .panel-nav li a{display:inline-block;padding:15px;padding-left:17px;padding-right:17px;color:rgba(0,0,0,.4)}.panel-header .panel-nav li a svg{margin-right:3px;width:15px;vertical-align:middle;display:inline-block;fill:rgba(0,0,0,.4)}.panel-header .panel-nav li a span{margin-top:2px;vertical-align:middle;display:inline-block}.panel-header .panel-nav li a.active,.panel-header .panel-nav li a:hover{text-decoration:none;border-bottom:solid 2px #383e51;color:#383e51}.panel-header .panel-nav li a.active svg,.panel-header .panel-nav li a:hover svg{fill:#383e51}.panel-header .panel-actions{float:right;list-style:none;padding:7px}.panel-header .panel-actions .btn{padding:5px;padding-left:12px;padding-right:12px}.panel-header .panel-actions li{display:inline}.panel-header .panel-actions li a{display:inline-block}.panel-header .panel-actions li a svg{margin-right:3px;width:15px;vertical-align:middle;display:inline-block}.panel-header .panel-actions li a span{margin-top:2px;vertical-align:middle;display:inline-block}@media (max-width:1100px){.panel .panel-header .panel-nav li a span{display:none}}@media (max-width:700px){.panel .panel-header .form-search{display:none}}.pill{display:inline-block;text-align:center;padding:3px;padding-left:10px;padding-right:10px;border-radius:25px;color:#fff;font-size:14px;vertical-align:middle}.pill a{text-decoration:none}.pill svg{margin-top:-2px;display:inline-block;vertical-align:middle;width:15px;fill:#fff}.pill-bubble{margin-right:5px;border-radius:100%;width:25px;height:25px;text-align:center;display:inline-block}.pill-bubble svg{width:15px;fill:#fff;vertical-align:middle}a.pill:hover{text-decoration:none}.pill-light{background:#61a0ea}a.pill-light:hover{background:#5090d9}.pill-gray{background:#6a7393}.pill-dark{background:#272b39}.pill-red{background:#c64242}.pill-green{background:#269326}.pill-blue{background:#61a0ea}.pill-orange{background:#ff7400}@media (max-width:950px){.pill{width:25px!important}.pill span{display:none}}.providers{margin-top:15px;margin-bottom:15px}.provider-btn{display:inline-block;border-radius:3px;color:#fff;border:none;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif,"Apple Color Emoji","Segoe UI Emoji","Sego UI Symbol";padding:10px;padding-left:15px;padding-right:15px}.provider-btn svg{margin-right:5px;fill:#fff;vertical-align:middle}.provider-btn span{display:inline-block;vertical-align:middle}.provider-btn:hover{text-decoration:none}.provider-github{background:#24292e}.provider-github:hover{background:#353a3f}.provider-gitlab{background:#fa7035}.provider-gitlab:hover{background:#e65328}.table{border-collapse:collapse;width:100%}.table td,.table th{padding:10px}.table td svg,.table th svg{width:15px;display:inline-block;vertical-align:middle}.table td form,.table th form{display:inline-block}.table td.success{color:#269326}.table td.success svg{fill:#269326}.table td.warning{color:#ff7400}.table td.warning svg{fill:#ff7400}.table td.error{color:#c64242}.table td.error svg{fill:#c64242}.table th{background:rgba(0,0,0,.03);border-bottom:solid 1px #e4e4e4;text-align:left;color:rgba(0,0,0,.5);font-weight:400}.table th.align-right{text-align:right}.table tr{border-bottom:solid 1px #e4e4e4}.table tr:last-child{border-bottom:none}.table .cell-pill{width:100px}.table .cell-date{text-align:right!important;width:250px}@media (max-width:900px){th.hide-mobile{display:none}td.hide-mobile{display:none}}.overflow{overflow:auto;padding-bottom:5px}.muted{color:#9f9f9f}.muted svg{fill:#9f9f9f}a.active,a.muted:hover{color:#272b39}a.active svg,a.muted:hover svg{fill:#272b39}.hook-status{width:10px;text-align:center}.hook-status-err svg{fill:#c64242}.hook-status-none svg{fill:#6a7393}.hook-status-ok svg{fill:#269326}.align-center{text-align:center}.align-right{text-align:right}.inline-block{display:inline-block}.separator{margin-top:20px;margin-bottom:20px;border-bottom:solid 1px #cfcfcf}.slim{margin:0 auto;max-width:600px}.left{float:left}.right{float:right}.w-90{width:90px}.mt-5{margin-top:5px}.middle{vertical-align:middle}.mb-10{margin-bottom:10px}.pr-5{padding-right:5px}.pl-5{padding-left:5px}.progress-wrap .progress-bg{padding:3px;border-radius:3px;width:100%;background:#e4e4e4}.progress-wrap .progress{margin-top:-6px;padding:3px;border-radius:3px;background:#61a0ea}.svg-red svg{fill:#c64242}.svg-green svg{fill:#269326}.paginator{margin:0 auto;list-style:none;max-width:250px}.paginator li{display:inline}.paginator li a{display:inline-block;box-sizing:border-box;text-align:center;padding:10px;width:50%}.paginator li a.disabled{cursor:not-allowed;color:rgba(0,0,0,.5)}.paginator li a:hover{text-decoration:none}.paginator li .prev:hover{border-radius:3px 0 0 3px;background:#61a0ea;color:#fff}.paginator li .next:hover{border-radius:0 3px 3px 0;background:#61a0ea;color:#fff}.scope-list h3{margin-bottom:15px}.scope-list .scope-item{margin-top:15px;overflow:auto;border-top:solid 1px #cfcfcf;padding:15px}.scope-list .scope-item svg{display:inline-block;margin-right:15px;float:left;fill:rgba(0,0,0,.4)}.scope-list .scope-item span{display:inline-block}.scope-list .scope-item span strong{display:block}`)
-//line template/template.qtpl:161
+//line template.qtpl:272
+	qw422016.N().S(`*{margin:0;padding:0}body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif,"Apple Color Emoji","Segoe UI Emoji","Sego UI Symbol";font-size:14px;background:#eee;color:#444}a{color:#146de0;cursor:pointer;text-decoration:none}a:hover{text-decoration:underline}button{cursor:pointer}h1,h2,h3,h4,h5,h6{font-weight:400}.btn{border:none;border-radius:3px;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif,"Apple Color Emoji","Segoe UI Emoji","Sego UI Symbol";padding:10px;padding-left:15px;padding-right:15px;color:#fff}.btn svg{fill:#fff}.btn:hover{text-decoration:none}.btn:disabled{cursor:not-allowed;background:#b2b2b2!important}.btn-primary{background:#61a0ea}.btn-primary:hover{background:#5090d9}.btn-danger{background:#de4141}.btn-danger:hover{background:#cd3030}span.code{padding:3px;border-radius:3px;background:#e6f0f5;font-family:monospace;white-space:pre-wrap}pre.code{background:#272b39;border-radius:0 0 3px 3px;box-sizing:border-box;color:#fff;font-family:monospace;font-size:12px;overflow:auto;padding:15px;width:100%}td.code{border-radius:0 3px 3px 0;text-align:right;width:50%}.code-wrap{overflow:scroll}table.code{background:#272b39;color:#fff;width:100%;font-family:monospace;font-size:12px;border-collapse:collapse;border-spacing:0}table.code .line-number{text-align:right;-moz-user-select:none;-ms-user-select:none;-webkit-user-select:none;min-width:30px;width:1%;padding-left:10px;padding-right:10px;line-height:20px}table.code .line-number a{display:block;color:rgba(255,255,255,.3)}table.code .line{padding-left:10px;padding-right:10px;line-height:20px;white-space:pre;word-wrap:normal}table.code .line:target{background:#383e51}details.code-section summary{background:#383e51;color:#fff;cursor:pointer;font-family:monospace;font-size:12px;line-height:20px;padding-left:10px;padding-right:10px}details.code-section .code-result{color:rgba(255,255,255,.5);float:right}details.code-section-failed summary{background:#c64242}.col-75{width:75%;box-sizing:border-box}.col-25{width:25%;box-sizing:border-box}.col-50{width:50%;box-sizing:border-box}.col-left{float:left;padding-right:5px}.col-right{float:right;padding-left:5px}@media (max-width:1100px){.col-75{margin-bottom:10px;width:100%}.col-25{margin-bottom:10px;width:100%}.col-50{margin-bottom:10px;width:100%}.col-left{padding-right:0;float:none}.col-right{padding-left:0;float:none}}.dashboard .sidebar{position:fixed;top:0;left:0;height:100%;width:225px;background:#383e51;overflow:auto}.dashboard .sidebar .sidebar-header{color:#fff;padding:20px;background:#272b39}.dashboard .sidebar .sidebar-header .logo{margin-top:-5px;margin-right:30px;display:inline-block;vertical-align:middle;width:0}.dashboard .sidebar .sidebar-header .logo .handle{margin-left:-3px;border-style:solid;border-width:2px 0 8px 7px;border-color:transparent transparent transparent #fff}.dashboard .sidebar .sidebar-header .logo .lid{margin-bottom:-20px;margin-left:13px;border-style:solid;border-width:5px 0 7px 5px;border-color:transparent transparent transparent #fff}.dashboard .sidebar .sidebar-header .logo .lantern{margin-left:-5px;border-style:solid;border-width:15px 15px 35px 0;border-color:transparent #fff transparent transparent}.dashboard .sidebar .sidebar-header h2{display:inline-block}.dashboard .sidebar .sidebar-auth a{display:block;color:rgba(255,255,255,.5);padding:15px;text-align:center}.dashboard .sidebar .sidebar-auth a.active,.dashboard .sidebar .sidebar-auth a:hover,.dashboard .sidebar .sidebar-auth button:hover{text-decoration:none;background:#272b39;color:#fff}.dashboard .sidebar .sidebar-nav{list-style:none}.dashboard .sidebar .sidebar-nav li{display:block}.dashboard .sidebar .sidebar-nav li a,.dashboard .sidebar .sidebar-nav li button{display:block;color:rgba(255,255,255,.5);padding:15px}.dashboard .sidebar .sidebar-nav li a svg,.dashboard .sidebar .sidebar-nav li button svg{margin-right:3px;display:inline-block;vertical-align:middle;fill:rgba(255,255,255,.5);width:15px}.dashboard .sidebar .sidebar-nav li a span,.dashboard .sidebar .sidebar-nav li button span{margin-top:2px;display:inline-block;vertical-align:middle}.dashboard .sidebar .sidebar-nav li button{width:100%;border:none;text-align:left;background:rgba(0,0,0,0)}.dashboard .sidebar .sidebar-nav li a.active,.dashboard .sidebar .sidebar-nav li a:hover,.dashboard .sidebar .sidebar-nav li button:hover{text-decoration:none;background:#272b39;color:#fff}.dashboard .sidebar .sidebar-nav li a.active svg,.dashboard .sidebar .sidebar-nav li a:hover svg,.dashboard .sidebar .sidebar-nav li button:hover svg{fill:#fff}.dashboard .sidebar .sidebar-nav li.sidebar-nav-header{padding:15px;font-weight:700;color:#fff}.dashboard-header{margin-bottom:10px}.dashboard-header h1{float:left}.dashboard-header h1 .back{margin-top:2px;display:inline-block;vertical-align:middle}.dashboard-header h1 .back svg{fill:#7f7f7f}.dashboard-header h1 .back:hover{text-decoration:none}.dashboard-header h1 .back:hover svg{fill:#444}.dashboard-header h1 small{margin-top:10px;display:block;font-size:16px;color:rgba(0,0,0,.5)}.dashboard-header .pill{margin-top:-5px;margin-left:10px}.dashboard-header .dashboard-actions{float:right;list-style:none}.dashboard-header .dashboard-actions li{display:inline}.dashboard-header .dashboard-actions li form{display:inline-block}.dashboard-header .dashboard-actions li a{cursor:pointer;display:inline-block}.dashboard-nav{list-style:none}.dashboard-nav li{display:inline}.dashboard-nav li a{display:inline-block;padding:15px;color:#9f9f9f}.dashboard-nav li a svg{margin-right:3px;width:20px;vertical-align:middle;display:inline-block;fill:#9f9f9f}.dashboard-nav li a span{margin-top:2px;display:inline-block;vertical-align:middle}.dashboard-nav li a.active,.dashboard-nav li a:hover{text-decoration:none;color:#272b39}.dashboard-nav li a.active svg,.dashboard-nav li a:hover svg{fill:#272b39}.dashboard-content{margin-left:225px}.dashboard-content .alert{overflow:auto;padding:15px}.dashboard-content .alert .alert-message{float:left;color:rgba(0,0,0,.6)}.dashboard-content .alert a.alert-close{float:right;display:inline-block}.dashboard-content .alert a.alert-close svg{width:15px;height:15px;fill:rgba(0,0,0,.4)}.dashboard-content .alert a.alert-close:hover svg{fill:rgba(0,0,0,.5)}.dashboard-content .alert-success{background:#caf5ca;border:solid 1px #a0dfa0}.dashboard-content .alert-warn{background:#fff3cd;border:solid 1px #d9c995}.dashboard-content .alert-danger{background:#ffd4d4;border:solid 1px #e19e9e}.dashboard-content .dashboard-wrap{margin:0 auto;max-width:1300px;padding:20px}@media (max-width:1500px){.dashboard .sidebar{width:70px}.dashboard .sidebar .sidebar-header{padding:15px}.dashboard .sidebar .sidebar-header .logo{margin-top:0;margin-right:0;margin-left:12px}.dashboard .sidebar .sidebar-header h2{display:none}.dashboard .sidebar .sidebar-nav .sidebar-nav-header{display:none}.dashboard .sidebar .sidebar-nav li a,.dashboard .sidebar .sidebar-nav li button{text-align:center}.dashboard .sidebar .sidebar-nav li a span,.dashboard .sidebar .sidebar-nav li button span{display:none}.dashboard .dashboard-content{margin-left:70px}}@media (max-width:1000px){.dashboard .dashboard-content .dashboard-header .dashboard-nav li a span{display:none}}.form-field+.form-field{margin-top:15px}.form-field{overflow:auto}.form-field .label{margin-bottom:5px;display:block;font-weight:700}.form-field .label small{color:rgba(0,0,0,.5)}.form-field .form-error{margin-top:5px;color:#ff4343;min-height:20px}.form-field .form-text{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif,"Apple Color Emoji","Segoe UI Emoji","Sego UI Symbol";font-size:14px;padding:10px;outline:0;border-radius:3px;box-sizing:border-box;width:100%;border:solid 1px #e4e4e4}.form-field .form-text:focus{border:solid 1px #c2c2c2}.form-field .form-code{min-height:250px;font-family:monospace}.form-field textarea.form-text{min-width:100%;max-width:100%}.form-field .form-option+.form-option{margin-top:10px}.form-field .form-option{display:block;cursor:pointer;overflow:auto}.form-field .form-option .form-selector{margin-right:5px;outline:0}.form-field .form-option .form-option-info{margin-right:5px;display:inline-block}.form-field .form-option svg{margin-right:5px;fill:rgba(0,0,0,.4)}.form-field .hook-event{cursor:pointer;display:inline-block;width:250px;padding:10px 0 10px 0}.form-field .disabled{color:#aaa;cursor:not-allowed}.form-field .disabled svg{fill:#aaa}.form-search{float:right;padding:7px}.form-search .form-text{width:auto}.form-search a svg{margin-top:-3px;fill:#e4e4e4;width:20px;vertical-align:middle;display:inline-block}.form-search a:hover svg{fill:#c2c2c2}.form-field-inline .form-text{display:inline-block;width:auto}.form-field-inline .form-error{display:inline-block}form h2{margin-bottom:15px}.panel+.panel{margin-top:15px}.panel{background:#fff;border-radius:3px;box-shadow:0 2px 4px 0 rgba(0,0,0,.1)}.panel .panel-body{padding:15px}.panel .panel-message{font-size:20px;padding:150px;text-align:center}.panel .panel-footer{border-top:solid 1px #e4e4e4;padding:15px}.panel table.code{border-radius:0 0 3px 3px}.panel-header{border-bottom:solid 1px #e4e4e4;overflow:auto}.panel-header h3{float:left;padding:15px;font-weight:700}.panel-header .panel-nav{list-style:none;float:left}.panel-header .panel-nav li{display:inline}.panel-header .panel-nav li a{display:inline-block;padding:15px;padding-left:17px;padding-right:17px;color:rgba(0,0,0,.4)}.panel-header .panel-nav li a svg{margin-right:3px;width:15px;vertical-align:middle;display:inline-block;fill:rgba(0,0,0,.4)}.panel-header .panel-nav li a span{margin-top:2px;vertical-align:middle;display:inline-block}.panel-header .panel-nav li a.active,.panel-header .panel-nav li a:hover{text-decoration:none;border-bottom:solid 2px #383e51;color:#383e51}.panel-header .panel-nav li a.active svg,.panel-header .panel-nav li a:hover svg{fill:#383e51}.panel-header .panel-actions{float:right;list-style:none;padding:7px}.panel-header .panel-actions .btn{padding:5px;padding-left:12px;padding-right:12px}.panel-header .panel-actions li{display:inline}.panel-header .panel-actions li a{display:inline-block}.panel-header .panel-actions li a svg{margin-right:3px;width:15px;vertical-align:middle;display:inline-block}.panel-header .panel-actions li a span{margin-top:2px;vertical-align:middle;display:inline-block}@media (max-width:1100px){.panel .panel-header .panel-nav li a span{display:none}}@media (max-width:700px){.panel .panel-header .form-search{display:none}}.pill{display:inline-block;text-align:center;padding:3px;padding-left:10px;padding-right:10px;border-radius:25px;color:#fff;font-size:14px;vertical-align:middle}.pill a{text-decoration:none}.pill svg{margin-top:-2px;display:inline-block;vertical-align:middle;width:15px;fill:#fff}.pill-bubble{margin-right:5px;border-radius:100%;width:25px;height:25px;text-align:center;display:inline-block}.pill-bubble svg{width:15px;fill:#fff;vertical-align:middle}a.pill:hover{text-decoration:none}.pill-light{background:#61a0ea}a.pill-light:hover{background:#5090d9}.pill-gray{background:#6a7393}.pill-dark{background:#272b39}.pill-red{background:#c64242}.pill-green{background:#269326}.pill-blue{background:#61a0ea}.pill-orange{background:#ff7400}@media (max-width:950px){.pill{width:25px!important}.pill span{display:none}}.providers{margin-top:15px;margin-bottom:15px}.provider-btn{display:inline-block;border-radius:3px;color:#fff;border:none;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif,"Apple Color Emoji","Segoe UI Emoji","Sego UI Symbol";padding:10px;padding-left:15px;padding-right:15px}.provider-btn svg{margin-right:5px;fill:#fff;vertical-align:middle}.provider-btn span{display:inline-block;vertical-align:middle}.provider-btn:hover{text-decoration:none}.provider-github{background:#24292e}.provider-github:hover{background:#353a3f}.provider-gitlab{background:#fa7035}.provider-gitlab:hover{background:#e65328}.table{border-collapse:collapse;width:100%}.table td,.table th{padding:10px}.table td svg,.table th svg{width:15px;display:inline-block;vertical-align:middle}.table td form,.table th form{display:inline-block}.table td.success{color:#269326}.table td.success svg{fill:#269326}.table td.warning{color:#ff7400}.table td.warning svg{fill:#ff7400}.table td.error{color:#c64242}.table td.error svg{fill:#c64242}.table th{background:rgba(0,0,0,.03);border-bottom:solid 1px #e4e4e4;text-align:left;color:rgba(0,0,0,.5);font-weight:400}.table th.align-right{text-align:right}.table tr{border-bottom:solid 1px #e4e4e4}.table tr:last-child{border-bottom:none}.table .cell-pill{width:100px}.table .cell-date{text-align:right!important;width:250px}@media (max-width:900px){th.hide-mobile{display:none}td.hide-mobile{display:none}}.overflow{overflow:auto;padding-bottom:5px}.muted{color:#9f9f9f}.muted svg{fill:#9f9f9f}a.active,a.muted:hover{color:#272b39}a.active svg,a.muted:hover svg{fill:#272b39}.hook-status{width:10px;text-align:center}.hook-status-err svg{fill:#c64242}.hook-status-none svg{fill:#6a7393}.hook-status-ok svg{fill:#269326}.align-center{text-align:center}.align-right{text-align:right}.inline-block{display:inline-block}.separator{margin-top:20px;margin-bottom:20px;border-bottom:solid 1px #cfcfcf}.slim{margin:0 auto;max-width:600px}.left{float:left}.right{float:right}.w-90{width:90px}.mt-5{margin-top:5px}.middle{vertical-align:middle}.mb-10{margin-bottom:10px}.pr-5{padding-right:5px}.pl-5{padding-left:5px}.progress-wrap .progress-bg{padding:3px;border-radius:3px;width:100%;background:#e4e4e4}.progress-wrap .progress{margin-top:-6px;padding:3px;border-radius:3px;background:#61a0ea}.svg-red svg{fill:#c64242}.svg-green svg{fill:#269326}.paginator{margin:0 auto;list-style:none;max-width:250px}.paginator li{display:inline}.paginator li a{display:inline-block;box-sizing:border-box;text-align:center;padding:10px;width:50%}.paginator li a.disabled{cursor:not-allowed;color:rgba(0,0,0,.5)}.paginator li a:hover{text-decoration:none}.paginator li .prev:hover{border-radius:3px 0 0 3px;background:#61a0ea;color:#fff}.paginator li .next:hover{border-radius:0 3px 3px 0;background:#61a0ea;color:#fff}.scope-list h3{margin-bottom:15px}.scope-list .scope-item{margin-top:15px;overflow:auto;border-top:solid 1px #cfcfcf;padding:15px}.scope-list .scope-item svg{display:inline-block;margin-right:15px;float:left;fill:rgba(0,0,0,.4)}.scope-list .scope-item span{display:inline-block}.scope-list .scope-item span strong{display:block}`)
+//line template.qtpl:272
 	qw422016.N().S(`</style> </head> <body>`)
-//line template/template.qtpl:163
+//line template.qtpl:274
 	tmpl.StreamBody(qw422016)
-//line template/template.qtpl:163
+//line template.qtpl:274
 	qw422016.N().S(`</body> <footer>`)
-//line template/template.qtpl:164
+//line template.qtpl:275
 	tmpl.StreamFooter(qw422016)
-//line template/template.qtpl:164
+//line template.qtpl:275
 	qw422016.N().S(`</footer> </html> `)
-//line template/template.qtpl:166
+//line template.qtpl:277
 }
 
-//line template/template.qtpl:166
+//line template.qtpl:277
 func WriteRender(qq422016 qtio422016.Writer, tmpl Template) {
-//line template/template.qtpl:166
+//line template.qtpl:277
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/template.qtpl:166
+//line template.qtpl:277
 	StreamRender(qw422016, tmpl)
-//line template/template.qtpl:166
+//line template.qtpl:277
 	qt422016.ReleaseWriter(qw422016)
-//line template/template.qtpl:166
+//line template.qtpl:277
 }
 
-//line template/template.qtpl:166
+//line template.qtpl:277
 func Render(tmpl Template) string {
-//line template/template.qtpl:166
+//line template.qtpl:277
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/template.qtpl:166
+//line template.qtpl:277
 	WriteRender(qb422016, tmpl)
-//line template/template.qtpl:166
+//line template.qtpl:277
 	qs422016 := string(qb422016.B)
-//line template/template.qtpl:166
+//line template.qtpl:277
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/template.qtpl:166
+//line template.qtpl:277
 	return qs422016
-//line template/template.qtpl:166
+//line template.qtpl:277
 }
 
-//line template/template.qtpl:168
+//line template.qtpl:279
 func (p Error) StreamTitle(qw422016 *qt422016.Writer) {
-//line template/template.qtpl:168
+//line template.qtpl:279
 	qw422016.N().S(`Error`)
-//line template/template.qtpl:168
+//line template.qtpl:279
 }
 
-//line template/template.qtpl:168
+//line template.qtpl:279
 func (p Error) WriteTitle(qq422016 qtio422016.Writer) {
-//line template/template.qtpl:168
+//line template.qtpl:279
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/template.qtpl:168
+//line template.qtpl:279
 	p.StreamTitle(qw422016)
-//line template/template.qtpl:168
+//line template.qtpl:279
 	qt422016.ReleaseWriter(qw422016)
-//line template/template.qtpl:168
+//line template.qtpl:279
 }
 
-//line template/template.qtpl:168
+//line template.qtpl:279
 func (p Error) Title() string {
-//line template/template.qtpl:168
+//line template.qtpl:279
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/template.qtpl:168
+//line template.qtpl:279
 	p.WriteTitle(qb422016)
-//line template/template.qtpl:168
+//line template.qtpl:279
 	qs422016 := string(qb422016.B)
-//line template/template.qtpl:168
+//line template.qtpl:279
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/template.qtpl:168
+//line template.qtpl:279
 	return qs422016
-//line template/template.qtpl:168
+//line template.qtpl:279
 }
 
-//line template/template.qtpl:170
+//line template.qtpl:281
 func (p Error) StreamBody(qw422016 *qt422016.Writer) {
-//line template/template.qtpl:170
+//line template.qtpl:281
 	qw422016.N().S(` <div class="error"> `)
-//line template/template.qtpl:172
+//line template.qtpl:283
 	StreamLogo(qw422016)
-//line template/template.qtpl:172
+//line template.qtpl:283
 	qw422016.N().S(` <h1>`)
-//line template/template.qtpl:173
+//line template.qtpl:284
 	qw422016.E().V(p.Code)
-//line template/template.qtpl:173
+//line template.qtpl:284
 	qw422016.N().S(`</h1> <h2>`)
-//line template/template.qtpl:174
+//line template.qtpl:285
 	qw422016.E().S(p.Message)
-//line template/template.qtpl:174
+//line template.qtpl:285
 	qw422016.N().S(`</h2> <br/> <a href="/">Back</a> <br/><br/> `)
-//line template/template.qtpl:178
+//line template.qtpl:289
 	if p.Error != nil {
-//line template/template.qtpl:178
+//line template.qtpl:289
 		qw422016.N().S(` <textarea readonly>`)
-//line template/template.qtpl:179
+//line template.qtpl:290
 		qw422016.E().S(errors.Format(p.Error))
-//line template/template.qtpl:179
+//line template.qtpl:290
 		qw422016.N().S(`</textarea> `)
-//line template/template.qtpl:180
+//line template.qtpl:291
 	}
-//line template/template.qtpl:180
+//line template.qtpl:291
 	qw422016.N().S(` </div> `)
-//line template/template.qtpl:182
+//line template.qtpl:293
 }
 
-//line template/template.qtpl:182
+//line template.qtpl:293
 func (p Error) WriteBody(qq422016 qtio422016.Writer) {
-//line template/template.qtpl:182
+//line template.qtpl:293
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/template.qtpl:182
+//line template.qtpl:293
 	p.StreamBody(qw422016)
-//line template/template.qtpl:182
+//line template.qtpl:293
 	qt422016.ReleaseWriter(qw422016)
-//line template/template.qtpl:182
+//line template.qtpl:293
 }
 
-//line template/template.qtpl:182
+//line template.qtpl:293
 func (p Error) Body() string {
-//line template/template.qtpl:182
+//line template.qtpl:293
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/template.qtpl:182
+//line template.qtpl:293
 	p.WriteBody(qb422016)
-//line template/template.qtpl:182
+//line template.qtpl:293
 	qs422016 := string(qb422016.B)
-//line template/template.qtpl:182
+//line template.qtpl:293
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/template.qtpl:182
+//line template.qtpl:293
 	return qs422016
-//line template/template.qtpl:182
+//line template.qtpl:293
 }
 
-//line template/template.qtpl:184
+//line template.qtpl:295
 func (p Error) StreamFooter(qw422016 *qt422016.Writer) {
-//line template/template.qtpl:184
+//line template.qtpl:295
 	qw422016.N().S(` <style type="text/css">`)
-//line template/template.qtpl:185
+//line template.qtpl:296
 	qw422016.N().S(`*{margin:0;padding:0}a{color:#66c9ff;cursor:pointer;text-decoration:none}body{font-family:sans-serif;font-size:14px;background:#383e51;color:#fff}h1,h2{font-weight:400}.error{margin:0 auto;margin-top:250px;padding:20px;text-align:center}.error .logo{margin:0 auto;margin-bottom:20px;width:0}.error .logo .handle{margin-left:-20px;border-style:solid;border-width:5px 0 12px 10px;border-color:transparent transparent transparent #fff}.error .logo .lid{margin-bottom:-30px;margin-left:5px;border-style:solid;border-width:5px 0 12px 10px;border-color:transparent transparent transparent #fff}.error .logo .lantern{margin-left:-25px;border-style:solid;border-width:25px 25px 75px 0;border-color:transparent #fff transparent transparent}.error h2{margin-top:20px}textarea{font-family:monospace;box-sizing:border-box;min-width:100%;max-width:100%;min-width:700px;min-height:300px;border:solid 1px rgba(255,255,255,.3);border-radius:3px;background:rgba(0,0,0,.3);color:#fff;white-space:pre}textarea:focus{border:solid 1px rgba(255,255,255,.5)}`)
-//line template/template.qtpl:185
+//line template.qtpl:296
 	qw422016.N().S(`</style> `)
-//line template/template.qtpl:186
+//line template.qtpl:297
 }
 
-//line template/template.qtpl:186
+//line template.qtpl:297
 func (p Error) WriteFooter(qq422016 qtio422016.Writer) {
-//line template/template.qtpl:186
+//line template.qtpl:297
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/template.qtpl:186
+//line template.qtpl:297
 	p.StreamFooter(qw422016)
-//line template/template.qtpl:186
+//line template.qtpl:297
 	qt422016.ReleaseWriter(qw422016)
-//line template/template.qtpl:186
+//line template.qtpl:297
 }
 
-//line template/template.qtpl:186
+//line template.qtpl:297
 func (p Error) Footer() string {
-//line template/template.qtpl:186
+//line template.qtpl:297
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/template.qtpl:186
+//line template.qtpl:297
 	p.WriteFooter(qb422016)
-//line template/template.qtpl:186
+//line template.qtpl:297
 	qs422016 := string(qb422016.B)
-//line template/template.qtpl:186
+//line template.qtpl:297
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/template.qtpl:186
+//line template.qtpl:297
 	return qs422016
-//line template/template.qtpl:186
+//line template.qtpl:297
 }
 
-//line template/template.qtpl:188
+//line template.qtpl:299
 func (p FatalError) StreamBody(qw422016 *qt422016.Writer) {
-//line template/template.qtpl:188
+//line template.qtpl:299
 	qw422016.N().S(` <div class="error"> `)
-//line template/template.qtpl:190
+//line template.qtpl:301
 	StreamLogo(qw422016)
-//line template/template.qtpl:190
+//line template.qtpl:301
 	qw422016.N().S(` <h1>`)
-//line template/template.qtpl:191
+//line template.qtpl:302
 	qw422016.E().V(p.Code)
-//line template/template.qtpl:191
+//line template.qtpl:302
 	qw422016.N().S(`</h1> <h2>`)
-//line template/template.qtpl:192
+//line template.qtpl:303
 	qw422016.E().S(p.Message)
-//line template/template.qtpl:192
+//line template.qtpl:303
 	qw422016.N().S(`</h2> <br/> <a href="/">Back</a> <br/><br/> <textarea readonly>`)
-//line template/template.qtpl:196
+//line template.qtpl:307
 	qw422016.E().S(p.Stack)
-//line template/template.qtpl:196
+//line template.qtpl:307
 	qw422016.N().S(`</textarea> </div> `)
-//line template/template.qtpl:198
+//line template.qtpl:309
 }
 
-//line template/template.qtpl:198
+//line template.qtpl:309
 func (p FatalError) WriteBody(qq422016 qtio422016.Writer) {
-//line template/template.qtpl:198
+//line template.qtpl:309
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line template/template.qtpl:198
+//line template.qtpl:309
 	p.StreamBody(qw422016)
-//line template/template.qtpl:198
+//line template.qtpl:309
 	qt422016.ReleaseWriter(qw422016)
-//line template/template.qtpl:198
+//line template.qtpl:309
 }
 
-//line template/template.qtpl:198
+//line template.qtpl:309
 func (p FatalError) Body() string {
-//line template/template.qtpl:198
+//line template.qtpl:309
 	qb422016 := qt422016.AcquireByteBuffer()
-//line template/template.qtpl:198
+//line template.qtpl:309
 	p.WriteBody(qb422016)
-//line template/template.qtpl:198
+//line template.qtpl:309
 	qs422016 := string(qb422016.B)
-//line template/template.qtpl:198
+//line template.qtpl:309
 	qt422016.ReleaseByteBuffer(qb422016)
-//line template/template.qtpl:198
+//line template.qtpl:309
 	return qs422016
-//line template/template.qtpl:198
+//line template.qtpl:309
 }
@@ -8,20 +8,25 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"djinn-ci.com/build"
 	"djinn-ci.com/crypto"
 	"djinn-ci.com/database"
 	"djinn-ci.com/driver"
+	"djinn-ci.com/driver/docker"
 	"djinn-ci.com/driver/qemu"
+	"djinn-ci.com/driver/sandbox"
 	"djinn-ci.com/errors"
 	"djinn-ci.com/image"
 	"djinn-ci.com/key"
 	"djinn-ci.com/log"
+	"djinn-ci.com/manifest"
 	"djinn-ci.com/runner"
 	"djinn-ci.com/variable"
 
+	"github.com/andrewpillar/fs"
 	"github.com/andrewpillar/query"
 
 	"github.com/go-redis/redis"
@@ -29,6 +34,27 @@ import (
 	"golang.org/x/text/transform"
 )
 
+// syncBuffer is a bytes.Buffer that is safe for concurrent use. This is used
+// for the build output, since jobs in a stage may be executed concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
 type maskedBuffer struct {
 	*transform.Writer
 
@@ -42,15 +68,19 @@ type job struct {
 	buf *maskedBuffer
 }
 
-func newJob(r *runner.Runner, j *build.Job, t transform.Transformer) *job {
+func newMaskedBuffer(r *runner.Runner, t transform.Transformer) *maskedBuffer {
 	var buf bytes.Buffer
 
+	return &maskedBuffer{
+		Writer: transform.NewWriter(io.MultiWriter(r.Writer, &buf), t),
+		buf:    &buf,
+	}
+}
+
+func newJob(r *runner.Runner, j *build.Job, t transform.Transformer) *job {
 	return &job{
 		job: j,
-		buf: &maskedBuffer{
-			Writer: transform.NewWriter(io.MultiWriter(r.Writer, &buf), t),
-			buf:    &buf,
-		},
+		buf: newMaskedBuffer(r, t),
 	}
 }
 
@@ -58,27 +88,79 @@ func (j *job) fullname() string {
 	return j.job.Stage.Name + "/" + j.job.Name
 }
 
-func (j *job) runnerJob(r *runner.Runner) *runner.Job {
+func (j *job) runnerJob(r *runner.Runner) (*runner.Job, error) {
 	artifacts := make(runner.Passthrough)
 
-	for _, a := range j.job.Artifacts {
-		artifacts[a.Source] = a.Name
+	for src, dst := range j.job.ArtifactPaths {
+		artifacts[src] = dst
+	}
+
+	// Jobs submitted before the artifact paths were recorded only have the
+	// artifacts that were created for them.
+	if len(artifacts) == 0 {
+		for _, a := range j.job.Artifacts {
+			artifacts[a.Source] = a.Name
+		}
+	}
+
+	patterns, err := j.job.Retry.Patterns()
+
+	if err != nil {
+		return nil, errors.Err(err)
 	}
 
 	return &runner.Job{
 		Writer:    j.buf,
 		Name:      j.job.Name,
+		Needs:     j.job.NeedsList(),
+		Env:       j.job.Env(),
+		Workdir:   j.job.Workdir,
+		Shell:     j.job.Shell,
+		Quiet:     j.job.Quiet,
+		Manual:    j.job.Manual,
 		Commands:  strings.Split(j.job.Commands, "\n"),
 		Artifacts: artifacts,
-	}
+		Inputs:    runner.Passthrough(j.job.Inputs),
+		Timeout:   j.job.Timeout,
+		Retry: runner.Retry{
+			Max:       j.job.Retry.Max,
+			ExitCodes: j.job.Retry.ExitCodes,
+			Patterns:  patterns,
+		},
+	}, nil
 }
 
 type jobs struct {
 	build.JobStore
 
+	attempts build.JobAttemptStore
+	maskers  []transform.Transformer
+	masked   map[string]struct{}
+
 	tab map[string]*job
 }
 
+// masker returns the transformer for masking the output of the given job. The
+// values of the masked variables of the build are masked, along with the
+// values of any environment variables declared on the job that override a
+// masked variable.
+func (s *jobs) masker(j *build.Job) transform.Transformer {
+	chain := append([]transform.Transformer{}, s.maskers...)
+
+	for _, kv := range j.Environ {
+		parts := strings.SplitN(kv, "=", 2)
+
+		if len(parts) < 2 {
+			continue
+		}
+
+		if _, ok := s.masked[parts[0]]; ok {
+			chain = append(chain, variable.Masker(parts[1]))
+		}
+	}
+	return transform.Chain(chain...)
+}
+
 func (s *jobs) put(j *job) {
 	s.tab[j.fullname()] = j
 }
@@ -90,12 +172,13 @@ func (s *jobs) get(j *runner.Job) *job {
 type Runner struct {
 	*runner.Runner
 
-	timeout time.Duration
-	redis   *redis.Client
+	timeout         time.Duration
+	approvalTimeout time.Duration
+	redis           *redis.Client
 
 	log *log.Logger
 
-	buf *bytes.Buffer
+	buf *syncBuffer
 
 	driver     string
 	driverInit driver.Init
@@ -117,6 +200,7 @@ func NewRunner(ctx context.Context, w *Worker, b *build.Build) (*Runner, error)
 	}
 
 	chain := make([]transform.Transformer, 0, len(vv))
+	masked := make(map[string]struct{})
 	env := make([]string, 0, len(vv))
 
 	for _, v := range vv {
@@ -128,11 +212,10 @@ func NewRunner(ctx context.Context, w *Worker, b *build.Build) (*Runner, error)
 
 		if v.Masked {
 			chain = append(chain, variable.Masker(v.Variable.Value))
+			masked[v.Key] = struct{}{}
 		}
 	}
 
-	masker := transform.Chain(chain...)
-
 	kk, err := build.NewKeyStore(w.DB).All(ctx, query.Where("build_id", "=", query.Arg(b.ID)))
 
 	if err != nil {
@@ -164,8 +247,9 @@ func NewRunner(ctx context.Context, w *Worker, b *build.Build) (*Runner, error)
 	}
 
 	artifacts := build.ArtifactStore{
-		Store: build.NewArtifactStore(w.DB),
-		FS:    w.Artifacts,
+		Store:  build.NewArtifactStore(w.DB),
+		FS:     w.Artifacts,
+		Hasher: w.Hasher,
 	}
 
 	jj, err := build.NewJobStore(w.DB).All(
@@ -181,13 +265,14 @@ func NewRunner(ctx context.Context, w *Worker, b *build.Build) (*Runner, error)
 	}
 
 	r := &Runner{
-		timeout:    w.Timeout,
-		redis:      w.Redis,
-		log:        w.Log,
-		buf:        &bytes.Buffer{},
-		driver:     w.Driver,
-		driverInit: w.DriverInit,
-		driverCfg:  w.DriverConfig,
+		timeout:         w.Timeout,
+		approvalTimeout: w.ApprovalTimeout,
+		redis:           w.Redis,
+		log:             w.Log,
+		buf:             &syncBuffer{},
+		driver:          w.Driver,
+		driverInit:      w.DriverInit,
+		driverCfg:       w.DriverConfig,
 		builds: &build.Store{
 			Store: build.NewStore(w.DB),
 		},
@@ -196,7 +281,12 @@ func NewRunner(ctx context.Context, w *Worker, b *build.Build) (*Runner, error)
 			JobStore: build.JobStore{
 				Store: build.NewJobStore(w.DB),
 			},
-			tab: make(map[string]*job, len(jj)),
+			attempts: build.JobAttemptStore{
+				Store: build.NewJobAttemptStore(w.DB),
+			},
+			maskers: chain,
+			masked:  masked,
+			tab:     make(map[string]*job, len(jj)),
 		},
 		build: b,
 	}
@@ -205,13 +295,43 @@ func NewRunner(ctx context.Context, w *Worker, b *build.Build) (*Runner, error)
 		Writer:      r.buf,
 		Env:         env,
 		Passthrough: pt,
+		Parallelism: w.JobParallelism,
+		PostTimeout: w.PostTimeout,
 		Objects:     objects.Filestore(b, keyChain(w.AESGCM, kk)),
 		Artifacts:   artifacts.Filestore(b, w.ArtifactLimit),
+		JobArtifacts: func(rj *runner.Job) fs.FS {
+			return artifacts.JobFilestore(b, r.jobs.get(rj).job, w.ArtifactLimit)
+		},
+		JobInputs: func(rj *runner.Job) fs.FS {
+			return artifacts.InputFilestore(b, r.jobs.get(rj).job)
+		},
+	}
+
+	r.Runner.Approve = r.approve
+
+	var cache *runner.Cache
+
+	if w.Caches != nil && !b.Manifest.Cache.IsZero() {
+		caches := build.CacheStore{
+			Store:  build.NewCacheStore(w.DB),
+			FS:     w.Caches,
+			Hasher: w.Hasher,
+		}
+
+		cache = &runner.Cache{
+			Key: b.Manifest.Cache.Key,
+			Vars: map[string]string{
+				"Branch":    b.Trigger.RulesContext(nil).Branch,
+				"Namespace": b.Manifest.Namespace,
+			},
+			Paths: b.Manifest.Cache.Paths,
+			Store: caches.Filestore(b),
+		}
 	}
 
 	ss, err := build.NewStageStore(w.DB).Select(
 		ctx,
-		[]string{"id", "name", "can_fail"},
+		[]string{"id", "name", "can_fail", "always", "after_failure"},
 		query.Where("build_id", "=", query.Arg(b.ID)),
 		query.OrderAsc("created_at"),
 	)
@@ -223,20 +343,38 @@ func NewRunner(ctx context.Context, w *Worker, b *build.Build) (*Runner, error)
 	stagetab := make(map[int64]*runner.Stage, len(ss))
 
 	for _, s := range ss {
-		st := &runner.Stage{
-			Name:    s.Name,
-			CanFail: s.CanFail,
-		}
+		st := s.Stage()
 
 		stagetab[s.ID] = st
 		r.Runner.Add(st)
 	}
 
 	for _, j := range jj {
-		jb := newJob(r.Runner, j, masker)
-		rj := jb.runnerJob(r.Runner)
+		// Skipped jobs were filtered out by the rules of the manifest when
+		// the build was submitted, so they are never executed.
+		if j.Status == runner.Skipped {
+			continue
+		}
+
+		jb := newJob(r.Runner, j, r.jobs.masker(j))
+		rj, err := jb.runnerJob(r.Runner)
+
+		if err != nil {
+			return nil, errors.Err(err)
+		}
+
+		// As with the build, the timeout configured for the worker is the
+		// maximum amount of time each attempt of a job can run for.
+		rj.Timeout = manifest.Duration(rj.Timeout).Cap(r.timeout)
 
 		st := stagetab[j.StageID]
+
+		// The jobs in the setup stage run before the build has anything to
+		// cache.
+		if !strings.HasPrefix(st.Name, "setup - ") {
+			rj.Cache = cache
+		}
+
 		st.Add(rj)
 
 		r.jobs.put(jb)
@@ -291,6 +429,99 @@ func (r *Runner) qemuRealpath(ctx context.Context, dir string) qemu.RealpathFunc
 	}
 }
 
+// sandboxRealpath returns the path to the rootfs tarball of the given image.
+// Images uploaded to the namespace of the build, or by the user if there is no
+// namespace, are used over the base images.
+func (r *Runner) sandboxRealpath(ctx context.Context, dir string) sandbox.RealpathFunc {
+	return func(name string) (string, error) {
+		col := "user_id"
+		arg := r.build.UserID
+
+		if r.build.NamespaceID.Valid {
+			col = "namespace_id"
+			arg = r.build.NamespaceID.Elem
+		}
+
+		i, ok, err := r.images.Get(
+			ctx,
+			query.Where(col, "=", query.Arg(arg)),
+			query.Where("driver", "=", query.Arg(driver.Sandbox)),
+			query.Where("name", "=", query.Arg(name)),
+		)
+
+		if err != nil {
+			return "", errors.Err(err)
+		}
+
+		if !ok {
+			return sandbox.BaseImage(dir, name)
+		}
+		return filepath.Join(dir, strconv.Itoa(int(i.UserID)), "sandbox", i.Hash), nil
+	}
+}
+
+// approve waits for the given manual job to be approved. The job is approved
+// via the API, which publishes to the approve channel of the build. The job
+// is checked for approval once subscribed, in case it was approved before the
+// subscription was made. If the job is not approved within the approval
+// timeout then runner.ErrNotApproved is returned. The time spent waiting
+// counts towards the timeout of the build.
+func (r *Runner) approve(ctx context.Context, rj *runner.Job) error {
+	j := r.jobs.get(rj)
+
+	sub := r.redis.Subscribe(fmt.Sprintf("approve-%v", r.build.ID))
+	defer sub.Close()
+
+	if _, err := sub.Receive(); err != nil {
+		return errors.Err(err)
+	}
+
+	var expired <-chan time.Time
+
+	if r.approvalTimeout > 0 {
+		t := time.NewTimer(r.approvalTimeout)
+		defer t.Stop()
+
+		expired = t.C
+	}
+
+	ch := sub.Channel()
+
+	for {
+		approval, _, err := r.jobs.SelectOne(
+			ctx, []string{"approved_by", "approved_at"}, query.Where("id", "=", query.Arg(j.job.ID)),
+		)
+
+		if err != nil {
+			return errors.Err(err)
+		}
+
+		if approval.ApprovedBy.Valid {
+			// Copy the approval over so it is not lost when the job is
+			// next updated.
+			j.job.ApprovedBy = approval.ApprovedBy
+			j.job.ApprovedAt = approval.ApprovedAt
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-expired:
+			r.log.Debug.Println("approval expired for job", j.fullname(), "in build", r.build.ID)
+			return runner.ErrNotApproved
+		case <-ch:
+		}
+	}
+
+	r.log.Debug.Println("job", j.fullname(), "in build", r.build.ID, "approved")
+
+	if err := r.builds.SetStatus(ctx, r.build, runner.Running); err != nil {
+		return errors.Err(err)
+	}
+	return nil
+}
+
 func sanitize(s string) string {
 	buf := make([]rune, 0, len(s))
 
@@ -315,6 +546,24 @@ func (r *Runner) Run(ctx context.Context) error {
 		q.Realpath = r.qemuRealpath(ctx, qemuCfg.Disks)
 	}
 
+	if s, ok := d.(*sandbox.Driver); ok {
+		sandboxCfg := cfg.(*sandbox.Config)
+
+		s.Image = filepath.Clean(s.Image)
+		s.Realpath = r.sandboxRealpath(ctx, sandboxCfg.Images)
+	}
+
+	if v, ok := d.(*docker.Driver); ok {
+		for _, s := range r.build.Manifest.Services {
+			v.Services = append(v.Services, docker.Service{
+				Image:   s.Image,
+				Alias:   s.Name(),
+				Env:     s.Env,
+				Command: s.Command,
+			})
+		}
+	}
+
 	r.HandleJobStart(func(rj *runner.Job) {
 		j := r.jobs.get(rj)
 		j.job.Status = rj.Status()
@@ -324,6 +573,44 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 	})
 
+	r.HandleJobWait(func(rj *runner.Job) {
+		j := r.jobs.get(rj)
+		j.job.Status = rj.Status()
+
+		if err := r.jobs.Update(ctx, j.job); err != nil {
+			r.log.Error.Println(errors.Err(err))
+		}
+
+		if err := r.builds.SetStatus(ctx, r.build, runner.Waiting); err != nil {
+			r.log.Error.Println(errors.Err(err))
+		}
+	})
+
+	r.HandleJobRetry(func(rj *runner.Job) {
+		j := r.jobs.get(rj)
+		j.buf.Close()
+
+		j.job.Output = database.Null[string]{
+			Elem:  sanitize(j.buf.String()),
+			Valid: true,
+		}
+		j.job.Results = build.JobCommands(rj.Results())
+		j.job.Status = rj.Status()
+
+		if _, err := r.jobs.attempts.Retried(ctx, j.job); err != nil {
+			r.log.Error.Println(errors.Err(err))
+		}
+
+		if err := r.jobs.Update(ctx, j.job); err != nil {
+			r.log.Error.Println(errors.Err(err))
+		}
+
+		// Each attempt has its own output, so give the job a new buffer for
+		// the next attempt.
+		j.buf = newMaskedBuffer(r.Runner, r.jobs.masker(j.job))
+		rj.Writer = j.buf
+	})
+
 	r.HandleJobComplete(func(rj *runner.Job) {
 		j := r.jobs.get(rj)
 		j.buf.Close()
@@ -332,6 +619,7 @@ func (r *Runner) Run(ctx context.Context) error {
 			Elem:  sanitize(j.buf.String()),
 			Valid: true,
 		}
+		j.job.Results = build.JobCommands(rj.Results())
 		j.job.Status = rj.Status()
 
 		if err := r.jobs.Finished(ctx, j.job); err != nil {
@@ -345,7 +633,11 @@ func (r *Runner) Run(ctx context.Context) error {
 
 	r.log.Debug.Println("running build", r.build.ID)
 
-	timeoutCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	// The timeout configured for the worker is the maximum amount of time a
+	// build can run for, the manifest can only lower this.
+	timeout := r.build.Manifest.Timeout.Cap(r.timeout)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	sub := r.redis.Subscribe(fmt.Sprintf("kill-%v", r.build.ID))
@@ -362,8 +654,24 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 	}()
 
+	// The always, and after failure stages outlive the timeout, and kill
+	// signal of the build, but not the worker.
+	r.Runner.PostContext = ctx
+
 	if err := r.Runner.Run(timeoutCtx, d); err != nil {
-		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		// Failed and timed out runs are still finished builds, so only bail
+		// out on errors that are not a status of the run.
+		outcomes := []error{context.Canceled, context.DeadlineExceeded, runner.ErrFailed, runner.ErrTimedOut}
+		outcome := false
+
+		for _, target := range outcomes {
+			if errors.Is(err, target) {
+				outcome = true
+				break
+			}
+		}
+
+		if !outcome {
 			return errors.Err(err)
 		}
 	}
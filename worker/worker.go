@@ -41,6 +41,7 @@ type Worker struct {
 	SMTP *mail.Client
 
 	AESGCM *crypto.AESGCM
+	Hasher *crypto.Hasher
 
 	Consumer *curlyq.Consumer
 	Queue    queue.Queue
@@ -48,6 +49,20 @@ type Worker struct {
 	Driver  string
 	Timeout time.Duration
 
+	// ApprovalTimeout is how long a build waits for a manual job to be
+	// approved before it is killed. If zero then the build waits until it
+	// times out.
+	ApprovalTimeout time.Duration
+
+	// PostTimeout is how long the always, and after failure stages of a
+	// build can be executed for. If zero then runner.DefaultPostTimeout is
+	// used.
+	PostTimeout time.Duration
+
+	// JobParallelism is the maximum number of jobs in a build stage that will
+	// be executed concurrently.
+	JobParallelism int
+
 	DriverInit   driver.Init
 	DriverConfig driver.Config
 
@@ -56,6 +71,10 @@ type Worker struct {
 	Objects       fs.FS
 	Artifacts     fs.FS
 	ArtifactLimit int64
+
+	// Caches is where the caches of builds are restored from, and saved to.
+	// If nil then builds are not cached.
+	Caches fs.FS
 }
 
 func New(cfg *config.Worker, driverCfg driver.Config, driverInit driver.Init) *Worker {
@@ -77,21 +96,26 @@ func New(cfg *config.Worker, driverCfg driver.Config, driverInit driver.Init) *W
 	memq.InitFunc("event:build.finished", build.InitEvent(webhooks))
 
 	return &Worker{
-		Log:           log,
-		DB:            cfg.DB(),
-		Redis:         cfg.Redis(),
-		SMTP:          smtp,
-		AESGCM:        aesgcm,
-		Consumer:      cfg.Consumer(),
-		Driver:        cfg.Driver(),
-		Queue:         memq,
-		Timeout:       cfg.Timeout(),
-		DriverInit:    driverInit,
-		DriverConfig:  driverCfg,
-		Providers:     cfg.Providers(),
-		Objects:       cfg.Objects(),
-		Artifacts:     cfg.Artifacts(),
-		ArtifactLimit: cfg.ArtifactLimit(),
+		Log:             log,
+		DB:              cfg.DB(),
+		Redis:           cfg.Redis(),
+		SMTP:            smtp,
+		AESGCM:          aesgcm,
+		Hasher:          cfg.Hasher(),
+		Consumer:        cfg.Consumer(),
+		Driver:          cfg.Driver(),
+		Queue:           memq,
+		Timeout:         cfg.Timeout(),
+		ApprovalTimeout: cfg.ApprovalTimeout(),
+		PostTimeout:     cfg.PostTimeout(),
+		JobParallelism:  cfg.JobParallelism(),
+		DriverInit:      driverInit,
+		DriverConfig:    driverCfg,
+		Providers:       cfg.Providers(),
+		Objects:         cfg.Objects(),
+		Artifacts:       cfg.Artifacts(),
+		ArtifactLimit:   cfg.ArtifactLimit(),
+		Caches:          cfg.Caches(),
 	}
 }
 
@@ -147,7 +171,7 @@ func (w *Worker) handle(ctx context.Context, job curlyq.Job) error {
 
 	b, _, err := builds.SelectOne(
 		ctx,
-		[]string{"id", "user_id", "number", "output", "status", "secret", "namespace_id", "started_at", "finished_at"},
+		[]string{"id", "user_id", "number", "manifest", "output", "status", "secret", "namespace_id", "started_at", "finished_at"},
 		query.Where("id", "=", query.Arg(payload.BuildID)),
 	)
 